@@ -2,20 +2,35 @@ package main
 
 import (
 	"context"
+	"expvar"
+	"fmt"
 	"log"
 	"net/http"
+	"os"
 	"strconv"
 	"time"
 
+	"currency-rate-app/internal/api/alert"
+	"currency-rate-app/internal/api/batch"
+	"currency-rate-app/internal/api/callback"
+	"currency-rate-app/internal/api/conversion"
 	"currency-rate-app/internal/api/currency"
+	"currency-rate-app/internal/api/deadletter"
+	"currency-rate-app/internal/api/pricing"
+	"currency-rate-app/internal/api/stream"
+	"currency-rate-app/internal/api/subscription"
+	"currency-rate-app/internal/api/timeseries"
 	"currency-rate-app/internal/application"
 	"currency-rate-app/internal/common/config"
 	http_client "currency-rate-app/internal/common/http-client"
+	"currency-rate-app/internal/common/idempotency"
 	"currency-rate-app/internal/common/logger"
 	"currency-rate-app/internal/common/middlewares"
 	"currency-rate-app/internal/common/utils"
+	currency_domain "currency-rate-app/internal/domains/currency"
 	"currency-rate-app/internal/infrastructure/db"
 	rateservice "currency-rate-app/internal/infrastructure/http/rates-api"
+	"currency-rate-app/internal/infrastructure/http/webhook"
 
 	_ "currency-rate-app/docs"
 
@@ -32,7 +47,7 @@ func main() {
 	gorm := db.SetupGorm(cfg)
 	serveMux := http.NewServeMux()
 
-	server := setupServer(cfg, serveMux)
+	server := setupServer(cfg, serveMux, db.NewIdempotencyRepository(gorm))
 	cancel := setupApp(ctx, serveMux, cfg, gorm)
 
 	go func() {
@@ -44,11 +59,16 @@ func main() {
 	utils.WaitForShutdown(ctx, server, cancel, cfg.GracefulShutdownTimeoutInSeconds)
 }
 
-func setupServer(cfg *config.Config, serveMux *http.ServeMux) *http.Server {
+func setupServer(cfg *config.Config, serveMux *http.ServeMux, idempotencyStore idempotency.Store) *http.Server {
 	middlewareStack := middlewares.ChainMiddlewares(
 		middlewares.RecoveryMiddleware,
 		middlewares.TracingMiddleware,
 		middlewares.LoggingMiddleware,
+		middlewares.NewClientAuthMiddleware(cfg.ApiClientKeys),
+		middlewares.NewIdempotencyMiddleware(idempotencyStore, middlewares.IdempotencyConfig{
+			TTL:         time.Duration(cfg.IdempotencyTTLInSeconds) * time.Second,
+			LockTimeout: time.Duration(cfg.IdempotencyLockTimeoutInSeconds) * time.Second,
+		}),
 	)
 
 	server := http.Server{
@@ -66,22 +86,170 @@ func setupApp(
 	gorm *gorm.DB,
 ) context.CancelFunc {
 	currencyRepoGorm := db.NewCurrencyRepository(gorm)
-	currencyService := application.NewCurrencyService(currencyRepoGorm)
+	rateCompletionHub := application.NewRateCompletionHub()
+	currencyService := application.NewCurrencyService(currencyRepoGorm, freshnessConfig(cfg), rateCompletionHub)
 
-	currency.NewCurrencyController(serveMux, currencyService)
-	serveMux.Handle("/swagger/", httpSwagger.WrapHandler)
+	conversionService := application.NewConversionService(currencyService)
+	rateBatchService := application.NewRateBatchService(currencyRepoGorm)
+	rateDeadLetterService := application.NewRateDeadLetterService(currencyRepoGorm, currencyService)
+
+	spreadRepoGorm := db.NewSpreadRepository(gorm)
+	pricingService := application.NewPricingService(spreadRepoGorm, cfg.PricingDefaultBidBps, cfg.PricingDefaultAskBps)
 
 	httpClient := &http.Client{
 		Transport: http_client.LogRoundTripper{DefaultClient: http.DefaultTransport},
 		Timeout:   time.Duration(cfg.HttpClientsDefaultTimeoutInSeconds) * time.Second,
 	}
 
-	rateApiService := rateservice.NewRateService(httpClient, *cfg)
-	processRateService := application.NewProcessRatesService(currencyRepoGorm, rateApiService)
+	subscriptionRepoGorm := db.NewSubscriptionRepository(gorm)
+	subscriptionService := application.NewSubscriptionService(subscriptionRepoGorm)
+
+	rateApiService, err := rateservice.NewRateService(httpClient, *cfg)
+
+	if err != nil {
+		panic(err)
+	}
+
+	timeSeriesService := application.NewTimeSeriesService(currencyRepoGorm, rateApiService)
+
+	webhookTimeout := time.Duration(cfg.WebhookTimeoutInSeconds) * time.Second
+	webhookHttpClient := &http.Client{
+		Transport: http_client.LogRoundTripper{DefaultClient: http_client.NewPublicTransport(webhookTimeout)},
+		Timeout:   webhookTimeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	alertRepoGorm := db.NewAlertRepository(gorm)
+	alertService := application.NewAlertService(alertRepoGorm)
+
+	callbackRepoGorm := db.NewCallbackRepository(gorm)
+	callbackService := application.NewCallbackService(callbackRepoGorm, currencyRepoGorm)
+
+	rateWaitService := application.NewRateWaitService(currencyRepoGorm, rateCompletionHub)
 
-	cancel := utils.CreateCronJob(ctx, time.Duration(cfg.RatesUpdateCronInSeconds)*time.Second, func(cronCtx context.Context) {
+	currency.NewCurrencyController(
+		serveMux,
+		currencyService,
+		pricingService,
+		rateWaitService,
+		time.Duration(cfg.RatesMaxWaitInSeconds)*time.Second,
+		time.Duration(cfg.RatesUpdateCronInSeconds)*time.Second,
+	)
+	batch.NewBatchController(serveMux, rateBatchService)
+	conversion.NewConversionController(serveMux, conversionService)
+	timeseries.NewTimeSeriesController(serveMux, timeSeriesService)
+	subscription.NewSubscriptionController(serveMux, subscriptionService)
+	alert.NewAlertController(serveMux, alertService)
+	callback.NewCallbackController(serveMux, callbackService)
+
+	rateEventRepoGorm := db.NewRateEventRepository(gorm)
+	rateStreamService := application.NewRateStreamService(rateEventRepoGorm)
+	stream.NewStreamController(serveMux, rateStreamService)
+
+	adminMux := http.NewServeMux()
+	pricing.NewSpreadController(adminMux, pricingService)
+	deadletter.NewDeadLetterController(adminMux, rateDeadLetterService)
+	adminMux.Handle("GET /v1/admin/metrics", expvar.Handler())
+	serveMux.Handle("/v1/admin/", middlewares.NewAdminAuthMiddleware(cfg.AdminApiToken)(adminMux))
+
+	serveMux.Handle("/swagger/", httpSwagger.WrapHandler)
+	processRateService := application.NewProcessRatesService(currencyRepoGorm, rateApiService, alertService, rateCompletionHub, application.ProcessRatesConfig{
+		PivotCurrency:  pivotCurrency(cfg),
+		WorkerId:       workerId(cfg),
+		MaxAttempts:    cfg.RatesMaxAttempts,
+		InitialBackoff: time.Duration(cfg.RatesInitialBackoffInSeconds) * time.Second,
+		MaxBackoff:     time.Duration(cfg.RatesMaxBackoffInSeconds) * time.Second,
+	})
+
+	rateLeaseReaper := application.NewRateLeaseReaperService(currencyRepoGorm, rateCompletionHub, application.RateLeaseReaperConfig{
+		Lease:       time.Duration(cfg.RatesLeaseInSeconds) * time.Second,
+		MaxAttempts: cfg.RatesMaxAttempts,
+	})
+
+	subscriptionScheduler := application.NewSubscriptionSchedulerService(subscriptionRepoGorm)
+	idempotencyCleanup := application.NewIdempotencyCleanupService(db.NewIdempotencyRepository(gorm))
+
+	// One attempt per run, retries are scheduled by the delivery log. Alert
+	// firings follow the same policy.
+	webhookSender := webhook.NewSender(webhookHttpClient, webhook.Config{SigningSecret: cfg.WebhookSigningSecret, MaxAttempts: 1})
+	deliveryConfig := application.CallbackDeliveryConfig{
+		MaxAttempts:    cfg.CallbacksMaxAttempts,
+		InitialBackoff: time.Duration(cfg.CallbacksInitialBackoffInSeconds) * time.Second,
+		MaxBackoff:     time.Duration(cfg.CallbacksMaxBackoffInSeconds) * time.Second,
+		Lease:          time.Duration(cfg.CallbacksLeaseInSeconds) * time.Second,
+	}
+	callbackDeliveryService := application.NewCallbackDeliveryService(callbackRepoGorm, currencyRepoGorm, webhookSender, deliveryConfig)
+	alertDeliveryService := application.NewAlertDeliveryService(alertRepoGorm, webhookSender, deliveryConfig)
+
+	cancelRates := utils.CreateCronJob(ctx, time.Duration(cfg.RatesUpdateCronInSeconds)*time.Second, func(cronCtx context.Context) {
 		processRateService.ProcessRates(cronCtx, cfg.RatesUpdateBatchSize)
 	})
+	cancelReaper := utils.CreateCronJob(ctx, time.Duration(cfg.RatesReaperCronInSeconds)*time.Second, func(cronCtx context.Context) {
+		rateLeaseReaper.ReclaimExpired(cronCtx, cfg.RatesReaperBatchSize)
+	})
+	cancelSubscriptions := utils.CreateCronJob(ctx, time.Duration(cfg.SubscriptionsSchedulerCronInSeconds)*time.Second, func(cronCtx context.Context) {
+		subscriptionScheduler.EnqueueDueSubscriptions(cronCtx, cfg.SubscriptionsSchedulerBatchSize)
+	})
+
+	cancelCallbacks := utils.CreateCronJob(ctx, time.Duration(cfg.CallbacksDeliveryCronInSeconds)*time.Second, func(cronCtx context.Context) {
+		callbackDeliveryService.DeliverDue(cronCtx, cfg.CallbacksDeliveryBatchSize)
+		alertDeliveryService.DeliverDue(cronCtx, cfg.CallbacksDeliveryBatchSize)
+	})
+	cancelIdempotencyCleanup := utils.CreateCronJob(ctx, time.Duration(cfg.IdempotencyCleanupCronInSeconds)*time.Second, func(cronCtx context.Context) {
+		idempotencyCleanup.DeleteExpired(cronCtx, cfg.IdempotencyCleanupBatchSize)
+	})
+
+	// Every instance follows completed rates over LISTEN/NOTIFY, whichever
+	// instance's worker completed them.
+	rateEventListener := db.NewRateEventListener(db.DatabaseUrl(cfg), rateEventRepoGorm, time.Duration(cfg.RatesStreamListenerRetryInSeconds)*time.Second)
+	listenerCtx, cancelListener := context.WithCancel(ctx)
+
+	go rateEventListener.Listen(listenerCtx, rateStreamService.Publish)
+
+	return func() {
+		cancelRates()
+		cancelReaper()
+		cancelSubscriptions()
+		cancelCallbacks()
+		cancelIdempotencyCleanup()
+		cancelListener()
+		rateStreamService.Close()
+	}
+}
+
+func freshnessConfig(cfg *config.Config) application.FreshnessConfig {
+	maxAgeByPair := make(map[string]time.Duration, len(cfg.RatesMaxAgeByPairInSeconds))
+
+	for pair, seconds := range cfg.RatesMaxAgeByPairInSeconds {
+		maxAgeByPair[pair] = time.Duration(seconds) * time.Second
+	}
+
+	return application.FreshnessConfig{
+		DefaultMaxAge: time.Duration(cfg.RatesMaxAgeDefaultInSeconds) * time.Second,
+		MaxAgeByPair:  maxAgeByPair,
+	}
+}
+
+// pivotCurrency checks RATES_TRIANGULATION_PIVOT against the currency
+// registry, a typo would otherwise only show up as failed cross rates.
+func pivotCurrency(cfg *config.Config) currency_domain.CurrencyCode {
+	pivot := currency_domain.CurrencyCode(cfg.RatesTriangulationPivot)
+
+	if pivot != "" && !pivot.IsValid() {
+		panic(fmt.Sprintf("RATES_TRIANGULATION_PIVOT %q is not a known currency", cfg.RatesTriangulationPivot))
+	}
+
+	return pivot
+}
+
+func workerId(cfg *config.Config) string {
+	if cfg.RatesWorkerId != "" {
+		return cfg.RatesWorkerId
+	}
+
+	hostname, _ := os.Hostname()
 
-	return cancel
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
 }
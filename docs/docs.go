@@ -15,9 +15,84 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/v1/currencies": {
+        "/v1/admin/rates/failed": {
+            "get": {
+                "description": "Dead-letter list: FAILED rate requests that aren't archived, with the failure reason, latest failures first. Paginated with ` + "`" + `nextCursor` + "`" + `",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List failed rates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer admin token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who makes the request",
+                        "name": "X-Admin-Actor",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Base currency",
+                        "name": "baseCurrency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Result currency",
+                        "name": "resultCurrency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Failed at or after, RFC 3339",
+                        "name": "failedFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Failed before, RFC 3339",
+                        "name": "failedTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 50 by default, up to 200",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "nextCursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/deadletter.ListFailedRatesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/admin/rates/requeue": {
             "post": {
-                "description": "Create currency rate",
+                "description": "Return FAILED rates matching the filter to PENDING with a fresh attempts budget, oldest failures first. Every requeue is recorded with the actor",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,14 +100,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "currency"
+                    "admin"
                 ],
-                "summary": "Create currency rate",
+                "summary": "Requeue failed rates",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Idempotency key for request",
-                        "name": "Idempotency-Key",
+                        "description": "Bearer admin token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who makes the change",
+                        "name": "X-Admin-Actor",
                         "in": "header",
                         "required": true
                     },
@@ -42,7 +124,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/currency.CreateRateRequest"
+                            "$ref": "#/definitions/deadletter.RequeueFailedRatesRequest"
                         }
                     }
                 ],
@@ -50,15 +132,15 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/currency.CreateRateResponse"
+                            "$ref": "#/definitions/deadletter.RequeueFailedRatesResponse"
                         }
                     }
                 }
             }
         },
-        "/v1/currencies/actual": {
+        "/v1/admin/rates/{id}/actions": {
             "get": {
-                "description": "Get actual currency rate by currency code",
+                "description": "Requeues and archivation of a rate with their actors, newest first",
                 "consumes": [
                     "application/json"
                 ],
@@ -66,32 +148,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "currency"
+                    "admin"
                 ],
-                "summary": "Get actual currency rate",
+                "summary": "Get admin actions of a rate",
                 "parameters": [
                     {
-                        "enum": [
-                            "USD",
-                            "EUR",
-                            "MXN"
-                        ],
                         "type": "string",
-                        "description": "Currency Code",
-                        "name": "baseCurrency",
-                        "in": "query",
+                        "description": "Bearer admin token",
+                        "name": "Authorization",
+                        "in": "header",
                         "required": true
                     },
                     {
-                        "enum": [
-                            "USD",
-                            "EUR",
-                            "MXN"
-                        ],
                         "type": "string",
-                        "description": "Currency Code",
-                        "name": "resultCurrency",
-                        "in": "query",
+                        "description": "Who makes the request",
+                        "name": "X-Admin-Actor",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Id",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -99,15 +178,15 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/currency.GetCurrencyResponse"
+                            "$ref": "#/definitions/deadletter.RateAdminActionsResponse"
                         }
                     }
                 }
             }
         },
-        "/v1/currencies/{id}": {
-            "get": {
-                "description": "Get currency rate by id",
+        "/v1/admin/rates/{id}/archive": {
+            "post": {
+                "description": "Take a FAILED rate off the dead-letter list for good, it stays FAILED",
                 "consumes": [
                     "application/json"
                 ],
@@ -115,80 +194,2377 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "currency"
+                    "admin"
                 ],
-                "summary": "Get currency rate by id",
+                "summary": "Archive failed rate",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer admin token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "description": "Id",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who makes the change",
+                        "name": "X-Admin-Actor",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/currency.GetCurrencyResponse"
+                            "$ref": "#/definitions/deadletter.DeadLetterRateResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "currency.CreateRateRequest": {
-            "type": "object",
-            "required": [
-                "baseCurrency",
-                "resultCurrency"
-            ],
-            "properties": {
-                "baseCurrency": {
-                    "$ref": "#/definitions/currency.CurrencyCode"
-                },
-                "resultCurrency": {
-                    "$ref": "#/definitions/currency.CurrencyCode"
+        },
+        "/v1/admin/rates/{id}/requeue": {
+            "post": {
+                "description": "Return a FAILED rate to PENDING with a fresh attempts budget. Archived rates can't be requeued",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Requeue failed rate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer admin token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who makes the change",
+                        "name": "X-Admin-Actor",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/deadletter.DeadLetterRateResponse"
+                        }
+                    }
                 }
             }
         },
-        "currency.CreateRateResponse": {
-            "type": "object",
-            "properties": {
-                "id": {
-                    "type": "string"
+        "/v1/admin/spreads": {
+            "get": {
+                "description": "List configured bid/ask spreads",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List spreads",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer admin token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who makes the request",
+                        "name": "X-Admin-Actor",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pricing.ListSpreadsResponse"
+                        }
+                    }
                 }
             }
         },
-        "currency.CurrencyCode": {
-            "type": "string",
-            "enum": [
-                "USD",
-                "EUR",
-                "MXN"
-            ],
-            "x-enum-varnames": [
-                "USD",
-                "EUR",
-                "MXN"
-            ]
-        },
-        "currency.GetCurrencyResponse": {
-            "type": "object",
-            "properties": {
-                "baseCurrency": {
-                    "$ref": "#/definitions/currency.CurrencyCode"
-                },
-                "completedAt": {
-                    "type": "string"
-                },
-                "rate": {
-                    "type": "number"
-                },
-                "resultCurrency": {
-                    "$ref": "#/definitions/currency.CurrencyCode"
+        "/v1/admin/spreads/{scope}": {
+            "put": {
+                "description": "Create or replace the spread for a pair (EURUSD) or a currency (MXN)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Set spread",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer admin token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pair or currency code",
+                        "name": "scope",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who makes the change",
+                        "name": "X-Admin-Actor",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/pricing.SetSpreadRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pricing.SpreadResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete the spread for a pair or a currency, the pair falls back to broader rules",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete spread",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer admin token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pair or currency code",
+                        "name": "scope",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who makes the change",
+                        "name": "X-Admin-Actor",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/v1/admin/spreads/{scope}/history": {
+            "get": {
+                "description": "Get all changes of the spread for a pair or a currency, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get spread history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer admin token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who makes the request",
+                        "name": "X-Admin-Actor",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pair or currency code",
+                        "name": "scope",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pricing.SpreadHistoryResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/alert-rules": {
+            "get": {
+                "description": "List all alert rules",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alert"
+                ],
+                "summary": "List alert rules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/alert.ListAlertRulesResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Notify targetUrl when the latest rate of the pair crosses threshold (ABOVE, BELOW) or moves by threshold percent within windowSeconds (CHANGE_PERCENT). Webhooks are signed with HMAC-SHA256 in X-Webhook-Signature over \"\u003cX-Webhook-Timestamp\u003e.\u003cbody\u003e\"",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alert"
+                ],
+                "summary": "Create alert rule",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/alert.CreateAlertRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/alert.AlertRuleResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/alert-rules/{id}": {
+            "get": {
+                "description": "Get alert rule by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alert"
+                ],
+                "summary": "Get alert rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/alert.AlertRuleResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete alert rule with its firings",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alert"
+                ],
+                "summary": "Delete alert rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/v1/alert-rules/{id}/firings": {
+            "get": {
+                "description": "Get firings of the alert rule with their delivery status, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alert"
+                ],
+                "summary": "Get alert firings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/alert.ListAlertFiringsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/conversions": {
+            "get": {
+                "description": "Convert amount using the actual currency rate, rounded to the target currency minor units",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "conversion"
+                ],
+                "summary": "Convert amount",
+                "parameters": [
+                    {
+                        "enum": [
+                            "USD",
+                            "EUR",
+                            "MXN"
+                        ],
+                        "type": "string",
+                        "description": "Currency Code",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "USD",
+                            "EUR",
+                            "MXN"
+                        ],
+                        "type": "string",
+                        "description": "Currency Code",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Amount in the base currency, up to 20 integer and 18 fractional digits",
+                        "name": "amount",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "half-even",
+                            "half-up",
+                            "down"
+                        ],
+                        "type": "string",
+                        "description": "Rounding mode, half-even by default",
+                        "name": "rounding",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/conversion.GetConversionResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/currencies": {
+            "get": {
+                "description": "List rate requests with filters, newest first by default. Pass nextCursor of the response as cursor to get the next page with the same sort",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "currency"
+                ],
+                "summary": "List currency rate requests",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated statuses, e.g. PENDING,PROCESSING",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "USD",
+                            "EUR",
+                            "MXN"
+                        ],
+                        "type": "string",
+                        "description": "Currency Code",
+                        "name": "baseCurrency",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "USD",
+                            "EUR",
+                            "MXN"
+                        ],
+                        "type": "string",
+                        "description": "Currency Code",
+                        "name": "resultCurrency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Idempotency key",
+                        "name": "idempotencyKey",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created at or after (RFC 3339)",
+                        "name": "createdFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created before (RFC 3339)",
+                        "name": "createdTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Completed at or after (RFC 3339)",
+                        "name": "completedFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Completed before (RFC 3339)",
+                        "name": "completedTo",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "createdAt",
+                            "updatedAt"
+                        ],
+                        "type": "string",
+                        "description": "Sort field, createdAt by default",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Sort order, desc by default",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 50 by default, at most 200",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "nextCursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "string",
+                            "number"
+                        ],
+                        "type": "string",
+                        "description": "Rate encoding, number is kept for legacy clients",
+                        "name": "rateFormat",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/currency.ListRatesResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create currency rate. With callbackUrl the result is POSTed there once the request is COMPLETED or FAILED, signed like alert webhooks.\nWith Prefer: wait=N the request blocks until the rate is final and answers with the rate, 202 with the id when the wait runs out.\nWith Prefer: respond-async it answers 202 with Location of the status resource and Retry-After, without it 200 with the id as in the original v1 contract",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "currency"
+                ],
+                "summary": "Create currency rate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Idempotency key for request",
+                        "name": "Idempotency-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond-async for 202 with Location, wait=N to wait up to N seconds for the rate, capped by the server",
+                        "name": "Prefer",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "string",
+                            "number"
+                        ],
+                        "type": "string",
+                        "description": "Rate encoding of the Prefer: wait answer, number is kept for legacy clients",
+                        "name": "rateFormat",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/currency.CreateRateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/currency.CreateRateResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/currency.CreateRateResponse"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "Status of the rate request"
+                            },
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds to wait before polling the status"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/currencies/actual": {
+            "get": {
+                "description": "Get actual currency rate by currency code, with bid and ask after the configured spread",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "currency"
+                ],
+                "summary": "Get actual currency rate",
+                "parameters": [
+                    {
+                        "enum": [
+                            "USD",
+                            "EUR",
+                            "MXN"
+                        ],
+                        "type": "string",
+                        "description": "Currency Code",
+                        "name": "baseCurrency",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "USD",
+                            "EUR",
+                            "MXN"
+                        ],
+                        "type": "string",
+                        "description": "Currency Code",
+                        "name": "resultCurrency",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Business date (YYYY-MM-DD), latest rate when omitted",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Answer with 1/rate of the opposite pair when it is fresher",
+                        "name": "allowInverse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max age of the rate in seconds (up to 31622400, one year), overrides the configured default for the pair",
+                        "name": "maxAge",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "allow",
+                            "error"
+                        ],
+                        "type": "string",
+                        "description": "What to do with a rate older than maxAge, allow by default",
+                        "name": "stalePolicy",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "string",
+                            "number"
+                        ],
+                        "type": "string",
+                        "description": "Rate encoding, number is kept for legacy clients",
+                        "name": "rateFormat",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/currency.GetCurrencyResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/currencies/batch": {
+            "post": {
+                "description": "Create a rate request per item under one idempotency key, in one transaction. Every item is validated on its own and answered with its id or its error",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "currency"
+                ],
+                "summary": "Create currency rates in batch",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Idempotency key for the whole batch",
+                        "name": "Idempotency-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/batch.CreateRateBatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/batch.RateBatchResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/currencies/batch/{id}/items": {
+            "get": {
+                "description": "Get every item of a batch with the status of its rate request, or its validation error",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "currency"
+                ],
+                "summary": "Get currency rate batch",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Batch id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/batch.RateBatchResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/currencies/codes": {
+            "get": {
+                "description": "Get all supported ISO 4217 currency codes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "currency"
+                ],
+                "summary": "Get currency codes",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/currency.GetCurrencyCodesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/currencies/matrix": {
+            "get": {
+                "description": "Get the freshest rate of every ordered pair of the codes in one request. Pairs without a rate are listed with available false",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "currency"
+                ],
+                "summary": "Get rate matrix",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated currency codes, e.g. USD,EUR,MXN",
+                        "name": "codes",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Business date (YYYY-MM-DD), latest rates when omitted",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "string",
+                            "number"
+                        ],
+                        "type": "string",
+                        "description": "Rate encoding, number is kept for legacy clients",
+                        "name": "rateFormat",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/currency.GetRateMatrixResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/currencies/stream": {
+            "get": {
+                "description": "Server-Sent Events: a \"rate\" event every time a rate of one of the pairs is completed. Event ids grow, reconnecting with Last-Event-ID replays the events missed meanwhile",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "currency"
+                ],
+                "summary": "Stream rate updates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated pairs, e.g. EURUSD,USDMXN",
+                        "name": "pairs",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Id of the last received event, to resume the stream",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "data of every rate event",
+                        "schema": {
+                            "$ref": "#/definitions/stream.RateEventResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/currencies/timeseries": {
+            "get": {
+                "description": "Get daily rates of a currency pair for a date range, gaps are filled from the rates provider",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "currency"
+                ],
+                "summary": "Get currency rate time series",
+                "parameters": [
+                    {
+                        "enum": [
+                            "USD",
+                            "EUR",
+                            "MXN"
+                        ],
+                        "type": "string",
+                        "description": "Currency Code",
+                        "name": "baseCurrency",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "USD",
+                            "EUR",
+                            "MXN"
+                        ],
+                        "type": "string",
+                        "description": "Currency Code",
+                        "name": "resultCurrency",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "First business date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last business date (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/timeseries.GetTimeSeriesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/currencies/{id}": {
+            "get": {
+                "description": "Get currency rate by id. With wait the request blocks until the rate is COMPLETED or FAILED, 202 with the id when the wait runs out",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "currency"
+                ],
+                "summary": "Get currency rate by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Seconds to wait for a pending rate, capped by the server",
+                        "name": "wait",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "string",
+                            "number"
+                        ],
+                        "type": "string",
+                        "description": "Rate encoding, number is kept for legacy clients",
+                        "name": "rateFormat",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/currency.GetCurrencyResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/currency.CreateRateResponse"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "Status of the rate request"
+                            },
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds to wait before polling again"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Cancel a PENDING rate request, the worker never picks it up. 409 when it is already PROCESSING or final",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "currency"
+                ],
+                "summary": "Cancel currency rate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/v1/currencies/{id}/callbacks": {
+            "get": {
+                "description": "Get completion callbacks of a rate request with their delivery log",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "currency"
+                ],
+                "summary": "Get rate callbacks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/callback.GetRateCallbacksResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/currencies/{id}/status": {
+            "get": {
+                "description": "Get the status of a rate request in any state, with attempts and timestamps. Unlike /v1/currencies/{id} it answers 200 while the rate is pending or failed.\nRetry-After is set while the rate isn't final",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "currency"
+                ],
+                "summary": "Get currency rate status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/currency.RateStatusResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds to wait before polling again, absent for final rates"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/subscriptions": {
+            "get": {
+                "description": "List all pair subscriptions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "List subscriptions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/subscription.ListSubscriptionsResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Subscribe to a pair, its latest rate is refreshed every intervalSeconds (60..604800)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Create subscription",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/subscription.CreateSubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/subscription.SubscriptionResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/subscriptions/{id}": {
+            "get": {
+                "description": "Get subscription by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Get subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/subscription.SubscriptionResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete subscription, rate requests already enqueued are still processed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Delete subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "alert.AlertFiringResponse": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "deliveryStatus": {
+                    "$ref": "#/definitions/alert.DeliveryStatus"
+                },
+                "firedAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastError": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "string"
+                },
+                "referenceRate": {
+                    "type": "string"
+                }
+            }
+        },
+        "alert.AlertRuleResponse": {
+            "type": "object",
+            "properties": {
+                "baseCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                },
+                "condition": {
+                    "$ref": "#/definitions/alert.Condition"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastFiredAt": {
+                    "type": "string"
+                },
+                "resultCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                },
+                "targetUrl": {
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "string"
+                },
+                "triggered": {
+                    "type": "boolean"
+                },
+                "windowSeconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "alert.Condition": {
+            "type": "string",
+            "enum": [
+                "ABOVE",
+                "BELOW",
+                "CHANGE_PERCENT"
+            ],
+            "x-enum-varnames": [
+                "ConditionAbove",
+                "ConditionBelow",
+                "ConditionChangePercent"
+            ]
+        },
+        "alert.CreateAlertRuleRequest": {
+            "type": "object",
+            "required": [
+                "baseCurrency",
+                "condition",
+                "resultCurrency",
+                "targetUrl"
+            ],
+            "properties": {
+                "baseCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                },
+                "condition": {
+                    "enum": [
+                        "ABOVE",
+                        "BELOW",
+                        "CHANGE_PERCENT"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/alert.Condition"
+                        }
+                    ]
+                },
+                "resultCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                },
+                "targetUrl": {
+                    "type": "string",
+                    "example": "https://hooks.example.com/fx"
+                },
+                "threshold": {
+                    "type": "string",
+                    "example": "1.1"
+                },
+                "windowSeconds": {
+                    "type": "integer",
+                    "example": 3600
+                }
+            }
+        },
+        "alert.DeliveryStatus": {
+            "type": "string",
+            "enum": [
+                "PENDING",
+                "DELIVERED",
+                "FAILED"
+            ],
+            "x-enum-varnames": [
+                "DeliveryStatusPending",
+                "DeliveryStatusDelivered",
+                "DeliveryStatusFailed"
+            ]
+        },
+        "alert.ListAlertFiringsResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/alert.AlertFiringResponse"
+                    }
+                }
+            }
+        },
+        "alert.ListAlertRulesResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/alert.AlertRuleResponse"
+                    }
+                }
+            }
+        },
+        "batch.CreateRateBatchRequest": {
+            "type": "object",
+            "required": [
+                "items"
+            ],
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/batch.RateBatchItemRequest"
+                    }
+                }
+            }
+        },
+        "batch.RateBatchItemRequest": {
+            "type": "object",
+            "properties": {
+                "baseCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                },
+                "callbackUrl": {
+                    "type": "string",
+                    "example": "https://hooks.example.com/rates"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2024-10-02"
+                },
+                "resultCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                }
+            }
+        },
+        "batch.RateBatchItemResponse": {
+            "type": "object",
+            "properties": {
+                "baseCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2024-10-02"
+                },
+                "error": {
+                    "$ref": "#/definitions/http_server.HttpErrorResponse"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "rate": {
+                    "type": "string",
+                    "example": "1.0845"
+                },
+                "resultCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                },
+                "status": {
+                    "$ref": "#/definitions/currency.CurrencyRateStatus"
+                }
+            }
+        },
+        "batch.RateBatchResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/batch.RateBatchItemResponse"
+                    }
+                }
+            }
+        },
+        "callback.CallbackAttemptResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "durationMs": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "integer"
+                },
+                "statusCode": {
+                    "type": "integer"
+                }
+            }
+        },
+        "callback.CallbackDeliveryResponse": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deliveredAt": {
+                    "type": "string"
+                },
+                "event": {
+                    "$ref": "#/definitions/callback.Event"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "log": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/callback.CallbackAttemptResponse"
+                    }
+                },
+                "nextAttemptAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/callback.DeliveryStatus"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "callback.DeliveryStatus": {
+            "type": "string",
+            "enum": [
+                "PENDING",
+                "DELIVERED",
+                "FAILED"
+            ],
+            "x-enum-varnames": [
+                "DeliveryStatusPending",
+                "DeliveryStatusDelivered",
+                "DeliveryStatusFailed"
+            ]
+        },
+        "callback.Event": {
+            "type": "string",
+            "enum": [
+                "currency_rate.completed",
+                "currency_rate.failed",
+                "currency_rate.cancelled"
+            ],
+            "x-enum-varnames": [
+                "EventRateCompleted",
+                "EventRateFailed",
+                "EventRateCancelled"
+            ]
+        },
+        "callback.GetRateCallbacksResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/callback.CallbackDeliveryResponse"
+                    }
+                }
+            }
+        },
+        "conversion.GetConversionResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "123.45"
+                },
+                "convertedAmount": {
+                    "type": "string",
+                    "example": "113.57"
+                },
+                "from": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                },
+                "rate": {
+                    "type": "string",
+                    "example": "0.92"
+                },
+                "rateCompletedAt": {
+                    "type": "string"
+                },
+                "roundingMode": {
+                    "$ref": "#/definitions/currency.RoundingMode"
+                },
+                "to": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                }
+            }
+        },
+        "currency.CreateRateRequest": {
+            "type": "object",
+            "required": [
+                "baseCurrency",
+                "resultCurrency"
+            ],
+            "properties": {
+                "baseCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                },
+                "callbackUrl": {
+                    "type": "string",
+                    "maxLength": 2048,
+                    "example": "https://hooks.example.com/rates"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2024-10-02"
+                },
+                "resultCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                }
+            }
+        },
+        "currency.CreateRateResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "currency.CurrencyCode": {
+            "type": "string",
+            "enum": [
+                "USD",
+                "EUR",
+                "MXN"
+            ],
+            "x-enum-varnames": [
+                "USD",
+                "EUR",
+                "MXN"
+            ]
+        },
+        "currency.CurrencyCodeResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                },
+                "minorUnits": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "numericCode": {
+                    "type": "string"
+                }
+            }
+        },
+        "currency.CurrencyRateStatus": {
+            "type": "string",
+            "enum": [
+                "PENDING",
+                "PROCESSING",
+                "COMPLETED",
+                "FAILED",
+                "CANCELLED"
+            ],
+            "x-enum-varnames": [
+                "CurrencyRateStatusPending",
+                "CurrencyRateStatusProcessing",
+                "CurrencyRateStatusCompleted",
+                "CurrencyRateStatusFailed",
+                "CurrencyRateStatusCancelled"
+            ]
+        },
+        "currency.GetCurrencyCodesResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/currency.CurrencyCodeResponse"
+                    }
+                }
+            }
+        },
+        "currency.GetCurrencyResponse": {
+            "type": "object",
+            "properties": {
+                "ageSeconds": {
+                    "type": "integer"
+                },
+                "ask": {
+                    "type": "string",
+                    "example": "1.08721125"
+                },
+                "askBps": {
+                    "type": "integer",
+                    "example": 25
+                },
+                "baseCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                },
+                "bid": {
+                    "type": "string",
+                    "example": "1.08179875"
+                },
+                "bidBps": {
+                    "type": "integer",
+                    "example": 25
+                },
+                "completedAt": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2024-10-02"
+                },
+                "derived": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "inverted": {
+                    "type": "boolean"
+                },
+                "mid": {
+                    "type": "string",
+                    "example": "1.0845"
+                },
+                "pivotCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                },
+                "provider": {
+                    "type": "string",
+                    "example": "frankfurter"
+                },
+                "rate": {
+                    "type": "string",
+                    "example": "1.0845"
+                },
+                "resultCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                },
+                "spreadScope": {
+                    "type": "string",
+                    "example": "EURUSD"
+                },
+                "stale": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "currency.GetRateMatrixResponse": {
+            "type": "object",
+            "properties": {
+                "cells": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/currency.RateMatrixCellResponse"
+                    }
+                },
+                "codes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/currency.CurrencyCode"
+                    }
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2024-10-02"
+                }
+            }
+        },
+        "currency.ListRatesResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/currency.RateListItemResponse"
+                    }
+                },
+                "nextCursor": {
+                    "description": "NextCursor is absent on the last page.",
+                    "type": "string"
+                }
+            }
+        },
+        "currency.RateAdminActionType": {
+            "type": "string",
+            "enum": [
+                "REQUEUE",
+                "ARCHIVE"
+            ],
+            "x-enum-varnames": [
+                "RateAdminActionRequeue",
+                "RateAdminActionArchive"
+            ]
+        },
+        "currency.RateFailureCode": {
+            "type": "string",
+            "enum": [
+                "PROVIDER_UNAVAILABLE",
+                "PROVIDER_TIMEOUT",
+                "PROVIDER_ERROR",
+                "PROVIDER_REJECTED",
+                "PROVIDER_BAD_RESPONSE",
+                "PAIR_NOT_SUPPORTED",
+                "LEASE_EXPIRED"
+            ],
+            "x-enum-varnames": [
+                "RateFailureProviderUnavailable",
+                "RateFailureProviderTimeout",
+                "RateFailureProviderError",
+                "RateFailureProviderRejected",
+                "RateFailureProviderBadResponse",
+                "RateFailurePairNotSupported",
+                "RateFailureLeaseExpired"
+            ]
+        },
+        "currency.RateListItemResponse": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "baseCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                },
+                "callbackUrl": {
+                    "type": "string"
+                },
+                "completedAt": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2024-10-02"
+                },
+                "failureCode": {
+                    "$ref": "#/definitions/currency.RateFailureCode"
+                },
+                "failureMessage": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "idempotencyKey": {
+                    "type": "string"
+                },
+                "nextAttemptAt": {
+                    "type": "string"
+                },
+                "pivotCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                },
+                "processingStartedAt": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string",
+                    "example": "frankfurter"
+                },
+                "rate": {
+                    "type": "string",
+                    "example": "1.0845"
+                },
+                "resultCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                },
+                "status": {
+                    "$ref": "#/definitions/currency.CurrencyRateStatus"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "workerId": {
+                    "type": "string"
+                }
+            }
+        },
+        "currency.RateMatrixCellResponse": {
+            "type": "object",
+            "properties": {
+                "ageSeconds": {
+                    "type": "integer"
+                },
+                "available": {
+                    "type": "boolean"
+                },
+                "baseCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                },
+                "completedAt": {
+                    "type": "string"
+                },
+                "pivotCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                },
+                "rate": {
+                    "type": "string",
+                    "example": "1.0845"
+                },
+                "resultCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                },
+                "stale": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "currency.RateStatusResponse": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "completedAt": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "failureCode": {
+                    "$ref": "#/definitions/currency.RateFailureCode"
+                },
+                "failureMessage": {
+                    "type": "string"
+                },
+                "final": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "nextAttemptAt": {
+                    "type": "string"
+                },
+                "processingStartedAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/currency.CurrencyRateStatus"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "currency.RoundingMode": {
+            "type": "string",
+            "enum": [
+                "half-even",
+                "half-up",
+                "down"
+            ],
+            "x-enum-varnames": [
+                "RoundingModeHalfEven",
+                "RoundingModeHalfUp",
+                "RoundingModeDown"
+            ]
+        },
+        "deadletter.DeadLetterRateResponse": {
+            "type": "object",
+            "properties": {
+                "archivedAt": {
+                    "type": "string"
+                },
+                "attempts": {
+                    "type": "integer"
+                },
+                "baseCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                },
+                "callbackUrl": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2024-10-02"
+                },
+                "failureCode": {
+                    "$ref": "#/definitions/currency.RateFailureCode"
+                },
+                "failureMessage": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "idempotencyKey": {
+                    "type": "string"
+                },
+                "resultCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                },
+                "status": {
+                    "$ref": "#/definitions/currency.CurrencyRateStatus"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "deadletter.ListFailedRatesResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/deadletter.DeadLetterRateResponse"
+                    }
+                },
+                "nextCursor": {
+                    "description": "NextCursor is absent on the last page.",
+                    "type": "string"
+                }
+            }
+        },
+        "deadletter.RateAdminActionResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/currency.RateAdminActionType"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "deadletter.RateAdminActionsResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/deadletter.RateAdminActionResponse"
+                    }
+                }
+            }
+        },
+        "deadletter.RequeueFailedRatesRequest": {
+            "type": "object",
+            "properties": {
+                "baseCurrency": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/currency.CurrencyCode"
+                        }
+                    ],
+                    "example": "USD"
+                },
+                "failedFrom": {
+                    "type": "string"
+                },
+                "failedTo": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 100
+                },
+                "resultCurrency": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/currency.CurrencyCode"
+                        }
+                    ],
+                    "example": "EUR"
+                }
+            }
+        },
+        "deadletter.RequeueFailedRatesResponse": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "requeued": {
+                    "type": "integer"
+                }
+            }
+        },
+        "http_server.HttpErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "details": {},
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "pricing.ListSpreadsResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/pricing.SpreadResponse"
+                    }
+                }
+            }
+        },
+        "pricing.SetSpreadRequest": {
+            "type": "object",
+            "required": [
+                "askBps",
+                "bidBps"
+            ],
+            "properties": {
+                "askBps": {
+                    "type": "integer",
+                    "maximum": 10000,
+                    "minimum": 0,
+                    "example": 25
+                },
+                "bidBps": {
+                    "type": "integer",
+                    "maximum": 9999,
+                    "minimum": 0,
+                    "example": 25
+                }
+            }
+        },
+        "pricing.SpreadAction": {
+            "type": "string",
+            "enum": [
+                "SET",
+                "DELETE"
+            ],
+            "x-enum-varnames": [
+                "SpreadActionSet",
+                "SpreadActionDelete"
+            ]
+        },
+        "pricing.SpreadChangeResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/pricing.SpreadAction"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "askBps": {
+                    "type": "integer"
+                },
+                "bidBps": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "scope": {
+                    "type": "string"
+                }
+            }
+        },
+        "pricing.SpreadHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/pricing.SpreadChangeResponse"
+                    }
+                }
+            }
+        },
+        "pricing.SpreadResponse": {
+            "type": "object",
+            "properties": {
+                "askBps": {
+                    "type": "integer"
+                },
+                "bidBps": {
+                    "type": "integer"
+                },
+                "scope": {
+                    "type": "string",
+                    "example": "EURUSD"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "updatedBy": {
+                    "type": "string"
+                }
+            }
+        },
+        "stream.RateEventResponse": {
+            "type": "object",
+            "properties": {
+                "baseCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                },
+                "completedAt": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2024-10-02"
+                },
+                "pivotCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                },
+                "rate": {
+                    "type": "string",
+                    "example": "1.0845"
+                },
+                "resultCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                }
+            }
+        },
+        "subscription.CreateSubscriptionRequest": {
+            "type": "object",
+            "required": [
+                "baseCurrency",
+                "intervalSeconds",
+                "resultCurrency"
+            ],
+            "properties": {
+                "baseCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                },
+                "intervalSeconds": {
+                    "type": "integer",
+                    "example": 300
+                },
+                "resultCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                }
+            }
+        },
+        "subscription.ListSubscriptionsResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/subscription.SubscriptionResponse"
+                    }
+                }
+            }
+        },
+        "subscription.SubscriptionResponse": {
+            "type": "object",
+            "properties": {
+                "baseCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "intervalSeconds": {
+                    "type": "integer"
+                },
+                "lastEnqueuedAt": {
+                    "type": "string"
+                },
+                "nextRunAt": {
+                    "type": "string"
+                },
+                "resultCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                }
+            }
+        },
+        "timeseries.GetTimeSeriesResponse": {
+            "type": "object",
+            "properties": {
+                "baseCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2024-10-01"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/timeseries.TimeSeriesPointResponse"
+                    }
+                },
+                "resultCurrency": {
+                    "$ref": "#/definitions/currency.CurrencyCode"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2024-10-31"
+                }
+            }
+        },
+        "timeseries.TimeSeriesPointResponse": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2024-10-02"
+                },
+                "filledFromProvider": {
+                    "type": "boolean"
+                },
+                "rate": {
+                    "type": "string",
+                    "example": "1.0845"
                 }
             }
         }
@@ -0,0 +1,151 @@
+package alert
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"currency-rate-app/internal/application"
+	error_utils "currency-rate-app/internal/common/error-utils"
+	http_server "currency-rate-app/internal/common/http-server"
+	"currency-rate-app/internal/common/validation"
+)
+
+type AlertController struct {
+	service application.AlertService
+}
+
+func NewAlertController(
+	mux *http.ServeMux,
+	service application.AlertService,
+) *AlertController {
+	controller := &AlertController{service: service}
+
+	mux.HandleFunc("POST /v1/alert-rules", func(w http.ResponseWriter, r *http.Request) {
+		controller.createAlertRuleHandler(w, r)
+	})
+	mux.HandleFunc("GET /v1/alert-rules", func(w http.ResponseWriter, r *http.Request) {
+		controller.listAlertRulesHandler(w, r)
+	})
+	mux.HandleFunc("GET /v1/alert-rules/{id}", func(w http.ResponseWriter, r *http.Request) {
+		controller.getAlertRuleHandler(w, r)
+	})
+	mux.HandleFunc("DELETE /v1/alert-rules/{id}", func(w http.ResponseWriter, r *http.Request) {
+		controller.deleteAlertRuleHandler(w, r)
+	})
+	mux.HandleFunc("GET /v1/alert-rules/{id}/firings", func(w http.ResponseWriter, r *http.Request) {
+		controller.getAlertFiringsHandler(w, r)
+	})
+
+	return controller
+}
+
+// @Summary      Create alert rule
+// @Description  Notify targetUrl when the latest rate of the pair crosses threshold (ABOVE, BELOW) or moves by threshold percent within windowSeconds (CHANGE_PERCENT). Webhooks are signed with HMAC-SHA256 in X-Webhook-Signature over "<X-Webhook-Timestamp>.<body>"
+// @Tags         alert
+// @Accept       json
+// @Produce      json
+// @Success 200  {object} AlertRuleResponse
+// @Param        request   body      CreateAlertRuleRequest  true  "Body"
+// @Router       /v1/alert-rules [post]
+func (c *AlertController) createAlertRuleHandler(w http.ResponseWriter, r *http.Request) {
+	var dto CreateAlertRuleRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		http_server.SendErrorResponse(w, error_utils.ErrValidationError(err.Error()))
+
+		return
+	}
+
+	if err := validation.GetValidator().Struct(&dto); err != nil {
+		http_server.SendErrorResponse(w, error_utils.ErrValidationError(err.Error()))
+
+		return
+	}
+
+	rule, err := c.service.CreateAlertRule(r.Context(), dto.ToDomain())
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	http_server.SendSuccessResponse(w, ToAlertRuleResponse(*rule))
+}
+
+// @Summary      List alert rules
+// @Description  List all alert rules
+// @Tags         alert
+// @Accept       json
+// @Produce      json
+// @Success 200  {object} ListAlertRulesResponse
+// @Router       /v1/alert-rules [get]
+func (c *AlertController) listAlertRulesHandler(w http.ResponseWriter, r *http.Request) {
+	rules, err := c.service.ListAlertRules(r.Context())
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	http_server.SendSuccessResponse(w, ToListAlertRulesResponse(rules))
+}
+
+// @Summary      Get alert rule
+// @Description  Get alert rule by id
+// @Tags         alert
+// @Accept       json
+// @Produce      json
+// @Success 200  {object} AlertRuleResponse
+// @Param        id        path      string  true  "Id"
+// @Router       /v1/alert-rules/{id} [get]
+func (c *AlertController) getAlertRuleHandler(w http.ResponseWriter, r *http.Request) {
+	rule, err := c.service.GetAlertRuleById(r.Context(), r.PathValue("id"))
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	http_server.SendSuccessResponse(w, ToAlertRuleResponse(*rule))
+}
+
+// @Summary      Delete alert rule
+// @Description  Delete alert rule with its firings
+// @Tags         alert
+// @Accept       json
+// @Produce      json
+// @Success 204
+// @Param        id        path      string  true  "Id"
+// @Router       /v1/alert-rules/{id} [delete]
+func (c *AlertController) deleteAlertRuleHandler(w http.ResponseWriter, r *http.Request) {
+	if err := c.service.DeleteAlertRule(r.Context(), r.PathValue("id")); err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary      Get alert firings
+// @Description  Get firings of the alert rule with their delivery status, newest first
+// @Tags         alert
+// @Accept       json
+// @Produce      json
+// @Success 200  {object} ListAlertFiringsResponse
+// @Param        id        path      string  true  "Id"
+// @Router       /v1/alert-rules/{id}/firings [get]
+func (c *AlertController) getAlertFiringsHandler(w http.ResponseWriter, r *http.Request) {
+	firings, err := c.service.GetAlertFirings(r.Context(), r.PathValue("id"))
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	http_server.SendSuccessResponse(w, ToListAlertFiringsResponse(firings))
+}
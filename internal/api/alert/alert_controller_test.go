@@ -0,0 +1,121 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"currency-rate-app/internal/application"
+	"currency-rate-app/internal/domains/alert"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockService struct {
+	application.AlertService
+	createFn  func(ctx context.Context, rule alert.AlertRule) (*alert.AlertRule, error)
+	firingsFn func(ctx context.Context, ruleId string) ([]alert.AlertFiring, error)
+}
+
+func (m *mockService) CreateAlertRule(ctx context.Context, rule alert.AlertRule) (*alert.AlertRule, error) {
+	return m.createFn(ctx, rule)
+}
+func (m *mockService) GetAlertFirings(ctx context.Context, ruleId string) ([]alert.AlertFiring, error) {
+	return m.firingsFn(ctx, ruleId)
+}
+
+func setupMux(service application.AlertService) *http.ServeMux {
+	mux := http.NewServeMux()
+	NewAlertController(mux, service)
+	return mux
+}
+
+func TestCreateAlertRule_Success(t *testing.T) {
+	var got alert.AlertRule
+	service := &mockService{
+		createFn: func(ctx context.Context, rule alert.AlertRule) (*alert.AlertRule, error) {
+			got = rule
+			rule.Id = "rule-1"
+			return &rule, nil
+		},
+	}
+	mux := setupMux(service)
+
+	body := []byte(`{"baseCurrency":"EUR","resultCurrency":"USD","condition":"CHANGE_PERCENT","threshold":"1.5","windowSeconds":3600,"targetUrl":"https://hooks.example.com/fx"}`)
+	req := httptest.NewRequest(http.MethodPost, "/v1/alert-rules", bytes.NewReader(body))
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, alert.ConditionChangePercent, got.Condition)
+	assert.Equal(t, "1.5", got.Threshold.String())
+	assert.Equal(t, time.Hour, got.Window)
+
+	var resp map[string]any
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	assert.Equal(t, "rule-1", resp["id"])
+	assert.Equal(t, "1.5", resp["threshold"])
+}
+
+func TestCreateAlertRule_ValidationError(t *testing.T) {
+	service := &mockService{
+		createFn: func(ctx context.Context, rule alert.AlertRule) (*alert.AlertRule, error) {
+			return nil, alert.ErrInvalidAlertCondition()
+		},
+	}
+	mux := setupMux(service)
+
+	body := []byte(`{"baseCurrency":"EUR","resultCurrency":"USD","condition":"CROSS","threshold":"1","targetUrl":"https://hooks.example.com/fx"}`)
+	req := httptest.NewRequest(http.MethodPost, "/v1/alert-rules", bytes.NewReader(body))
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+}
+
+func TestGetAlertFirings_Success(t *testing.T) {
+	service := &mockService{
+		firingsFn: func(ctx context.Context, ruleId string) ([]alert.AlertFiring, error) {
+			return []alert.AlertFiring{{Id: "firing-1", RuleId: ruleId, Rate: decimal.RequireFromString("1.2"), DeliveryStatus: alert.DeliveryStatusDelivered, Attempts: 1}}, nil
+		},
+	}
+	mux := setupMux(service)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/alert-rules/rule-1/firings", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	var resp ListAlertFiringsResponse
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	assert.Len(t, resp.Items, 1)
+	assert.Equal(t, alert.DeliveryStatusDelivered, resp.Items[0].DeliveryStatus)
+}
+
+func TestGetAlertFirings_RuleNotFound(t *testing.T) {
+	service := &mockService{
+		firingsFn: func(ctx context.Context, ruleId string) ([]alert.AlertFiring, error) {
+			return nil, alert.ErrAlertRuleNotFound()
+		},
+	}
+	mux := setupMux(service)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/alert-rules/rule-1/firings", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusNotFound, res.Code)
+}
@@ -0,0 +1,110 @@
+package alert
+
+import (
+	"currency-rate-app/internal/domains/alert"
+	"currency-rate-app/internal/domains/currency"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type CreateAlertRuleRequest struct {
+	BaseCurrency   currency.CurrencyCode `json:"baseCurrency" validate:"required,len=3"`
+	ResultCurrency currency.CurrencyCode `json:"resultCurrency" validate:"required,len=3"`
+	Condition      alert.Condition       `json:"condition" validate:"required" enums:"ABOVE,BELOW,CHANGE_PERCENT"`
+	Threshold      decimal.Decimal       `json:"threshold" swaggertype:"string" example:"1.1"`
+	WindowSeconds  int                   `json:"windowSeconds,omitempty" example:"3600"`
+	TargetUrl      string                `json:"targetUrl" validate:"required" example:"https://hooks.example.com/fx"`
+}
+
+type AlertRuleResponse struct {
+	Id             string                `json:"id"`
+	BaseCurrency   currency.CurrencyCode `json:"baseCurrency"`
+	ResultCurrency currency.CurrencyCode `json:"resultCurrency"`
+	Condition      alert.Condition       `json:"condition"`
+	Threshold      decimal.Decimal       `json:"threshold" swaggertype:"string"`
+	WindowSeconds  int64                 `json:"windowSeconds,omitempty"`
+	TargetUrl      string                `json:"targetUrl"`
+	Triggered      bool                  `json:"triggered"`
+	LastFiredAt    *time.Time            `json:"lastFiredAt,omitempty"`
+	CreatedAt      time.Time             `json:"createdAt"`
+}
+
+type ListAlertRulesResponse struct {
+	Items []AlertRuleResponse `json:"items"`
+}
+
+type AlertFiringResponse struct {
+	Id             string               `json:"id"`
+	Rate           decimal.Decimal      `json:"rate" swaggertype:"string"`
+	ReferenceRate  *decimal.Decimal     `json:"referenceRate,omitempty" swaggertype:"string"`
+	DeliveryStatus alert.DeliveryStatus `json:"deliveryStatus"`
+	Attempts       int                  `json:"attempts"`
+	LastError      *string              `json:"lastError,omitempty"`
+	FiredAt        time.Time            `json:"firedAt"`
+}
+
+type ListAlertFiringsResponse struct {
+	Items []AlertFiringResponse `json:"items"`
+}
+
+func (r CreateAlertRuleRequest) ToDomain() alert.AlertRule {
+	return alert.AlertRule{
+		BaseCurrency:   r.BaseCurrency,
+		ResultCurrency: r.ResultCurrency,
+		Condition:      r.Condition,
+		Threshold:      r.Threshold,
+		Window:         time.Duration(r.WindowSeconds) * time.Second,
+		TargetUrl:      r.TargetUrl,
+	}
+}
+
+func ToAlertRuleResponse(rule alert.AlertRule) *AlertRuleResponse {
+	var lastFiredAt *time.Time
+
+	if rule.LastFiredAt != nil {
+		t := rule.LastFiredAt.UTC()
+		lastFiredAt = &t
+	}
+
+	return &AlertRuleResponse{
+		Id:             rule.Id,
+		BaseCurrency:   rule.BaseCurrency,
+		ResultCurrency: rule.ResultCurrency,
+		Condition:      rule.Condition,
+		Threshold:      rule.Threshold,
+		WindowSeconds:  int64(rule.Window.Seconds()),
+		TargetUrl:      rule.TargetUrl,
+		Triggered:      rule.Triggered,
+		LastFiredAt:    lastFiredAt,
+		CreatedAt:      rule.CreatedAt.UTC(),
+	}
+}
+
+func ToListAlertRulesResponse(rules []alert.AlertRule) *ListAlertRulesResponse {
+	items := make([]AlertRuleResponse, 0, len(rules))
+
+	for _, rule := range rules {
+		items = append(items, *ToAlertRuleResponse(rule))
+	}
+
+	return &ListAlertRulesResponse{Items: items}
+}
+
+func ToListAlertFiringsResponse(firings []alert.AlertFiring) *ListAlertFiringsResponse {
+	items := make([]AlertFiringResponse, 0, len(firings))
+
+	for _, firing := range firings {
+		items = append(items, AlertFiringResponse{
+			Id:             firing.Id,
+			Rate:           firing.Rate,
+			ReferenceRate:  firing.ReferenceRate,
+			DeliveryStatus: firing.DeliveryStatus,
+			Attempts:       firing.Attempts,
+			LastError:      firing.LastError,
+			FiredAt:        firing.FiredAt.UTC(),
+		})
+	}
+
+	return &ListAlertFiringsResponse{Items: items}
+}
@@ -0,0 +1,100 @@
+package batch
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"currency-rate-app/internal/application"
+	"currency-rate-app/internal/common/auth"
+	error_utils "currency-rate-app/internal/common/error-utils"
+	http_server "currency-rate-app/internal/common/http-server"
+	"currency-rate-app/internal/common/validation"
+)
+
+type BatchController struct {
+	service application.RateBatchService
+}
+
+func NewBatchController(
+	mux *http.ServeMux,
+	service application.RateBatchService,
+) *BatchController {
+	controller := &BatchController{service: service}
+
+	mux.HandleFunc("POST /v1/currencies/batch", func(w http.ResponseWriter, r *http.Request) {
+		controller.createRateBatchHandler(w, r)
+	})
+	// Not /v1/currencies/batch/{id}: ServeMux refuses to register it next to
+	// GET /v1/currencies/{id}/status and /{id}/callbacks, both would match
+	// /v1/currencies/batch/status and neither is more specific.
+	mux.HandleFunc("GET /v1/currencies/batch/{id}/items", func(w http.ResponseWriter, r *http.Request) {
+		controller.getRateBatchHandler(w, r)
+	})
+
+	return controller
+}
+
+// @Summary      Create currency rates in batch
+// @Description  Create a rate request per item under one idempotency key, in one transaction. Every item is validated on its own and answered with its id or its error
+// @Tags         currency
+// @Accept       json
+// @Produce      json
+// @Success 200  {object} RateBatchResponse
+// @Param 		 Idempotency-Key header string true "Idempotency key for the whole batch"
+// @Param        request   body      CreateRateBatchRequest  true  "Body"
+// @Router       /v1/currencies/batch [post]
+func (c *BatchController) createRateBatchHandler(w http.ResponseWriter, r *http.Request) {
+	var dto CreateRateBatchRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		http_server.SendErrorResponse(w, error_utils.ErrValidationError(err.Error()))
+
+		return
+	}
+
+	if err := validation.GetValidator().Struct(&dto); err != nil {
+		http_server.SendErrorResponse(w, error_utils.ErrValidationError(err.Error()))
+
+		return
+	}
+
+	idempotencyKey := r.Header.Get("Idempotency-Key")
+
+	if idempotencyKey == "" {
+		http_server.SendErrorResponse(w, error_utils.ErrValidationError("Idempotency key not defined"))
+
+		return
+	}
+
+	clientId, _ := auth.ClientFromContext(r.Context())
+
+	batch, err := c.service.CreateRateBatch(r.Context(), clientId, idempotencyKey, ToRateBatchRequests(dto.Items))
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	http_server.SendSuccessResponse(w, ToRateBatchResponse(batch))
+}
+
+// @Summary      Get currency rate batch
+// @Description  Get every item of a batch with the status of its rate request, or its validation error
+// @Tags         currency
+// @Accept       json
+// @Produce      json
+// @Success 200  {object} RateBatchResponse
+// @Param        id        path      string  true  "Batch id"
+// @Router       /v1/currencies/batch/{id}/items [get]
+func (c *BatchController) getRateBatchHandler(w http.ResponseWriter, r *http.Request) {
+	batch, err := c.service.GetRateBatch(r.Context(), r.PathValue("id"))
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	http_server.SendSuccessResponse(w, ToRateBatchResponse(batch))
+}
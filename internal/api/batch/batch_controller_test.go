@@ -0,0 +1,165 @@
+package batch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"currency-rate-app/internal/common/auth"
+	error_utils "currency-rate-app/internal/common/error-utils"
+	"currency-rate-app/internal/domains/currency"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockBatchService struct {
+	createFn func(ctx context.Context, clientId string, idempotencyKey string, requests []currency.RateBatchRequest) (*currency.RateBatch, error)
+	getFn    func(ctx context.Context, id string) (*currency.RateBatch, error)
+}
+
+func (m *mockBatchService) CreateRateBatch(ctx context.Context, clientId string, idempotencyKey string, requests []currency.RateBatchRequest) (*currency.RateBatch, error) {
+	return m.createFn(ctx, clientId, idempotencyKey, requests)
+}
+
+func (m *mockBatchService) GetRateBatch(ctx context.Context, id string) (*currency.RateBatch, error) {
+	return m.getFn(ctx, id)
+}
+
+func setupMux(service *mockBatchService) *http.ServeMux {
+	mux := http.NewServeMux()
+	NewBatchController(mux, service)
+	return mux
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestCreateRateBatch_Success(t *testing.T) {
+	var received []currency.RateBatchRequest
+	var receivedClientId string
+	service := &mockBatchService{
+		createFn: func(ctx context.Context, clientId string, idempotencyKey string, requests []currency.RateBatchRequest) (*currency.RateBatch, error) {
+			received = requests
+			receivedClientId = clientId
+			return &currency.RateBatch{Id: "batch-1", Items: []currency.RateBatchItem{
+				{Position: 0, BaseCurrency: currency.EUR, ResultCurrency: currency.USD, RateId: ptr("rate-1"), Status: ptr(currency.CurrencyRateStatusPending)},
+				{Position: 1, BaseCurrency: currency.EUR, ResultCurrency: currency.EUR, ErrorCode: ptr("CurrenciesShouldDiffer")},
+			}}, nil
+		},
+	}
+	mux := setupMux(service)
+
+	body := new(bytes.Buffer)
+	json.NewEncoder(body).Encode(CreateRateBatchRequest{Items: []RateBatchItemRequest{
+		{BaseCurrency: currency.EUR, ResultCurrency: currency.USD, CallbackUrl: "https://hooks.example.com/rates"},
+		{BaseCurrency: currency.EUR, ResultCurrency: currency.EUR},
+	}})
+	req := httptest.NewRequest(http.MethodPost, "/v1/currencies/batch", body)
+	req.Header.Set("Idempotency-Key", "nightly-2024-10-02")
+	req = req.WithContext(auth.WithClient(req.Context(), "client-a"))
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "client-a", receivedClientId)
+	assert.Equal(t, "https://hooks.example.com/rates", *received[0].CallbackUrl)
+	assert.Nil(t, received[1].CallbackUrl)
+
+	var resDto RateBatchResponse
+	if err := json.NewDecoder(res.Body).Decode(&resDto); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	assert.Equal(t, "batch-1", resDto.Id)
+	assert.Equal(t, "rate-1", *resDto.Items[0].Id)
+	assert.Nil(t, resDto.Items[0].Error)
+	assert.Nil(t, resDto.Items[1].Id)
+	assert.Equal(t, "CurrenciesShouldDiffer", resDto.Items[1].Error.Code)
+}
+
+func TestCreateRateBatch_MissingHeader(t *testing.T) {
+	mux := setupMux(&mockBatchService{})
+
+	body := new(bytes.Buffer)
+	json.NewEncoder(body).Encode(CreateRateBatchRequest{Items: []RateBatchItemRequest{{BaseCurrency: currency.EUR, ResultCurrency: currency.USD}}})
+	req := httptest.NewRequest(http.MethodPost, "/v1/currencies/batch", body)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+}
+
+func TestCreateRateBatch_EmptyItems(t *testing.T) {
+	mux := setupMux(&mockBatchService{})
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/currencies/batch", bytes.NewBufferString(`{"items":[]}`))
+	req.Header.Set("Idempotency-Key", "idem-1")
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+}
+
+func TestCreateRateBatch_IdempotencyConflict(t *testing.T) {
+	service := &mockBatchService{
+		createFn: func(ctx context.Context, clientId string, idempotencyKey string, requests []currency.RateBatchRequest) (*currency.RateBatch, error) {
+			return nil, error_utils.ErrBusinessLogic("CurrencyRateIdempotencyConflict")
+		},
+	}
+	mux := setupMux(service)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/currencies/batch", bytes.NewBufferString(`{"items":[{"baseCurrency":"EUR","resultCurrency":"USD"}]}`))
+	req.Header.Set("Idempotency-Key", "idem-1")
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+}
+
+func TestGetRateBatch_Success(t *testing.T) {
+	rate := decimal.RequireFromString("1.0845")
+	service := &mockBatchService{
+		getFn: func(ctx context.Context, id string) (*currency.RateBatch, error) {
+			return &currency.RateBatch{Id: id, Items: []currency.RateBatchItem{
+				{Position: 0, BaseCurrency: currency.EUR, ResultCurrency: currency.USD, RateId: ptr("rate-1"), Status: ptr(currency.CurrencyRateStatusCompleted), Rate: &rate},
+			}}, nil
+		},
+	}
+	mux := setupMux(service)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/currencies/batch/batch-1/items", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	var resDto RateBatchResponse
+	if err := json.NewDecoder(res.Body).Decode(&resDto); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	assert.Equal(t, "batch-1", resDto.Id)
+	assert.Equal(t, currency.CurrencyRateStatusCompleted, *resDto.Items[0].Status)
+	assert.Equal(t, "1.0845", *resDto.Items[0].Rate)
+}
+
+func TestGetRateBatch_NotFound(t *testing.T) {
+	service := &mockBatchService{
+		getFn: func(ctx context.Context, id string) (*currency.RateBatch, error) {
+			return nil, currency.ErrRateBatchNotFound()
+		},
+	}
+	mux := setupMux(service)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/currencies/batch/unknown/items", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusNotFound, res.Code)
+}
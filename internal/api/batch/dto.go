@@ -0,0 +1,91 @@
+package batch
+
+import (
+	http_server "currency-rate-app/internal/common/http-server"
+	"currency-rate-app/internal/domains/currency"
+	"time"
+)
+
+type CreateRateBatchRequest struct {
+	Items []RateBatchItemRequest `json:"items" validate:"required,min=1,max=100"`
+}
+
+// RateBatchItemRequest has the fields of a single rate request. Items are
+// validated one by one, an invalid item is reported in the response instead
+// of failing the batch.
+type RateBatchItemRequest struct {
+	BaseCurrency   currency.CurrencyCode `json:"baseCurrency"`
+	ResultCurrency currency.CurrencyCode `json:"resultCurrency"`
+	Date           string                `json:"date,omitempty" example:"2024-10-02"`
+	CallbackUrl    string                `json:"callbackUrl,omitempty" example:"https://hooks.example.com/rates"`
+}
+
+func ToRateBatchRequests(items []RateBatchItemRequest) []currency.RateBatchRequest {
+	res := make([]currency.RateBatchRequest, 0, len(items))
+
+	for _, item := range items {
+		request := currency.RateBatchRequest{
+			BaseCurrency:   item.BaseCurrency,
+			ResultCurrency: item.ResultCurrency,
+			Date:           item.Date,
+		}
+
+		if item.CallbackUrl != "" {
+			callbackUrl := item.CallbackUrl
+			request.CallbackUrl = &callbackUrl
+		}
+
+		res = append(res, request)
+	}
+
+	return res
+}
+
+type RateBatchItemResponse struct {
+	Position       int                            `json:"position"`
+	BaseCurrency   currency.CurrencyCode          `json:"baseCurrency"`
+	ResultCurrency currency.CurrencyCode          `json:"resultCurrency"`
+	Date           *string                        `json:"date,omitempty" example:"2024-10-02"`
+	Id             *string                        `json:"id,omitempty"`
+	Status         *currency.CurrencyRateStatus   `json:"status,omitempty"`
+	Rate           *string                        `json:"rate,omitempty" example:"1.0845"`
+	Error          *http_server.HttpErrorResponse `json:"error,omitempty"`
+}
+
+type RateBatchResponse struct {
+	Id        string                  `json:"id"`
+	Items     []RateBatchItemResponse `json:"items"`
+	CreatedAt time.Time               `json:"createdAt"`
+}
+
+func ToRateBatchResponse(batch *currency.RateBatch) *RateBatchResponse {
+	items := make([]RateBatchItemResponse, 0, len(batch.Items))
+
+	for _, item := range batch.Items {
+		res := RateBatchItemResponse{
+			Position:       item.Position,
+			BaseCurrency:   item.BaseCurrency,
+			ResultCurrency: item.ResultCurrency,
+			Date:           currency.FormatRateDate(item.RateDate),
+			Id:             item.RateId,
+			Status:         item.Status,
+		}
+
+		if item.Rate != nil {
+			rate := item.Rate.String()
+			res.Rate = &rate
+		}
+
+		if item.ErrorCode != nil {
+			res.Error = &http_server.HttpErrorResponse{Code: *item.ErrorCode}
+
+			if item.ErrorMessage != nil {
+				res.Error.Message = *item.ErrorMessage
+			}
+		}
+
+		items = append(items, res)
+	}
+
+	return &RateBatchResponse{Id: batch.Id, Items: items, CreatedAt: batch.CreatedAt.UTC()}
+}
@@ -0,0 +1,45 @@
+package callback
+
+import (
+	"net/http"
+
+	"currency-rate-app/internal/application"
+	http_server "currency-rate-app/internal/common/http-server"
+)
+
+type CallbackController struct {
+	service application.CallbackService
+}
+
+func NewCallbackController(
+	mux *http.ServeMux,
+	service application.CallbackService,
+) *CallbackController {
+	controller := &CallbackController{service: service}
+
+	mux.HandleFunc("GET /v1/currencies/{id}/callbacks", func(w http.ResponseWriter, r *http.Request) {
+		controller.getRateCallbacksHandler(w, r)
+	})
+
+	return controller
+}
+
+// @Summary      Get rate callbacks
+// @Description  Get completion callbacks of a rate request with their delivery log
+// @Tags         currency
+// @Accept       json
+// @Produce      json
+// @Success 200  {object} GetRateCallbacksResponse
+// @Param        id        path      string  true  "Id"
+// @Router       /v1/currencies/{id}/callbacks [get]
+func (c *CallbackController) getRateCallbacksHandler(w http.ResponseWriter, r *http.Request) {
+	deliveries, err := c.service.GetRateCallbacks(r.Context(), r.PathValue("id"))
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	http_server.SendSuccessResponse(w, ToGetRateCallbacksResponse(deliveries))
+}
@@ -0,0 +1,83 @@
+package callback
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"currency-rate-app/internal/domains/callback"
+	"currency-rate-app/internal/domains/currency"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockService struct {
+	getFn func(ctx context.Context, rateId string) ([]callback.Delivery, error)
+}
+
+func (m *mockService) GetRateCallbacks(ctx context.Context, rateId string) ([]callback.Delivery, error) {
+	return m.getFn(ctx, rateId)
+}
+
+func TestGetRateCallbacks_Success(t *testing.T) {
+	now := time.Date(2024, 10, 2, 15, 4, 5, 0, time.UTC)
+	code := 503
+	msg := "webhook responded with 503 Service Unavailable"
+	var gotId string
+	service := &mockService{
+		getFn: func(ctx context.Context, rateId string) ([]callback.Delivery, error) {
+			gotId = rateId
+			return []callback.Delivery{{
+				Id:            "d1",
+				RateId:        rateId,
+				Url:           "https://hooks.example.com",
+				Event:         callback.EventRateCompleted,
+				Status:        callback.DeliveryStatusPending,
+				Attempts:      1,
+				NextAttemptAt: now.Add(10 * time.Second),
+				CreatedAt:     now,
+				Log:           []callback.Attempt{{Number: 1, StatusCode: &code, Error: &msg, Duration: 120 * time.Millisecond, CreatedAt: now}},
+			}}, nil
+		},
+	}
+	mux := http.NewServeMux()
+	NewCallbackController(mux, service)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/currencies/r1/callbacks", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "r1", gotId)
+
+	var body GetRateCallbacksResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	assert.Len(t, body.Items, 1)
+	assert.Equal(t, callback.DeliveryStatusPending, body.Items[0].Status)
+	assert.NotNil(t, body.Items[0].NextAttemptAt)
+	assert.Equal(t, 503, *body.Items[0].Log[0].StatusCode)
+	assert.EqualValues(t, 120, body.Items[0].Log[0].DurationMs)
+}
+
+func TestGetRateCallbacks_RateNotFound(t *testing.T) {
+	service := &mockService{
+		getFn: func(ctx context.Context, rateId string) ([]callback.Delivery, error) {
+			return nil, currency.ErrCurrencyRateNotFound()
+		},
+	}
+	mux := http.NewServeMux()
+	NewCallbackController(mux, service)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/currencies/r1/callbacks", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusNotFound, res.Code)
+}
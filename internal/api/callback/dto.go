@@ -0,0 +1,74 @@
+package callback
+
+import (
+	"currency-rate-app/internal/domains/callback"
+	"time"
+)
+
+type CallbackAttemptResponse struct {
+	Number     int       `json:"number"`
+	StatusCode *int      `json:"statusCode,omitempty"`
+	Error      *string   `json:"error,omitempty"`
+	DurationMs int64     `json:"durationMs"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type CallbackDeliveryResponse struct {
+	Id            string                    `json:"id"`
+	Url           string                    `json:"url"`
+	Event         callback.Event            `json:"event"`
+	Status        callback.DeliveryStatus   `json:"status"`
+	Attempts      int                       `json:"attempts"`
+	NextAttemptAt *time.Time                `json:"nextAttemptAt,omitempty"`
+	DeliveredAt   *time.Time                `json:"deliveredAt,omitempty"`
+	CreatedAt     time.Time                 `json:"createdAt"`
+	Log           []CallbackAttemptResponse `json:"log"`
+}
+
+type GetRateCallbacksResponse struct {
+	Items []CallbackDeliveryResponse `json:"items"`
+}
+
+func ToGetRateCallbacksResponse(deliveries []callback.Delivery) *GetRateCallbacksResponse {
+	items := make([]CallbackDeliveryResponse, 0, len(deliveries))
+
+	for _, d := range deliveries {
+		log := make([]CallbackAttemptResponse, 0, len(d.Log))
+
+		for _, a := range d.Log {
+			log = append(log, CallbackAttemptResponse{
+				Number:     a.Number,
+				StatusCode: a.StatusCode,
+				Error:      a.Error,
+				DurationMs: a.Duration.Milliseconds(),
+				CreatedAt:  a.CreatedAt.UTC(),
+			})
+		}
+
+		var nextAttemptAt, deliveredAt *time.Time
+
+		if d.Status == callback.DeliveryStatusPending {
+			t := d.NextAttemptAt.UTC()
+			nextAttemptAt = &t
+		}
+
+		if d.DeliveredAt != nil {
+			t := d.DeliveredAt.UTC()
+			deliveredAt = &t
+		}
+
+		items = append(items, CallbackDeliveryResponse{
+			Id:            d.Id,
+			Url:           d.Url,
+			Event:         d.Event,
+			Status:        d.Status,
+			Attempts:      d.Attempts,
+			NextAttemptAt: nextAttemptAt,
+			DeliveredAt:   deliveredAt,
+			CreatedAt:     d.CreatedAt.UTC(),
+			Log:           log,
+		})
+	}
+
+	return &GetRateCallbacksResponse{Items: items}
+}
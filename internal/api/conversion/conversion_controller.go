@@ -0,0 +1,78 @@
+package conversion
+
+import (
+	"net/http"
+
+	"currency-rate-app/internal/application"
+	http_server "currency-rate-app/internal/common/http-server"
+	"currency-rate-app/internal/domains/currency"
+)
+
+type ConversionController struct {
+	service application.ConversionService
+}
+
+func NewConversionController(
+	mux *http.ServeMux,
+	service application.ConversionService,
+) *ConversionController {
+	controller := &ConversionController{service: service}
+
+	mux.HandleFunc("GET /v1/conversions", func(w http.ResponseWriter, r *http.Request) {
+		controller.getConversionHandler(w, r)
+	})
+
+	return controller
+}
+
+// @Summary      Convert amount
+// @Description  Convert amount using the actual currency rate, rounded to the target currency minor units
+// @Tags         conversion
+// @Accept       json
+// @Produce      json
+// @Success 200  {object} GetConversionResponse
+// @Param        from   query      currency.CurrencyCode  true  "Currency Code"
+// @Param        to   query      currency.CurrencyCode  true  "Currency Code"
+// @Param        amount   query      string  true  "Amount in the base currency, up to 20 integer and 18 fractional digits"
+// @Param        rounding   query      string  false  "Rounding mode, half-even by default"  Enums(half-even, half-up, down)
+// @Router       /v1/conversions [get]
+func (c *ConversionController) getConversionHandler(w http.ResponseWriter, r *http.Request) {
+	from := currency.CurrencyCode(r.URL.Query().Get("from"))
+	to := currency.CurrencyCode(r.URL.Query().Get("to"))
+
+	if err := currency.ValidateCurrencyPair(from, to); err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	amount, err := currency.ParseAmount(r.URL.Query().Get("amount"))
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	roundingMode := currency.RoundingMode(r.URL.Query().Get("rounding"))
+
+	if roundingMode == "" {
+		roundingMode = currency.RoundingModeHalfEven
+	}
+
+	if !roundingMode.IsValid() {
+		http_server.SendErrorResponse(w, currency.ErrInvalidRoundingMode())
+
+		return
+	}
+
+	conversion, err := c.service.Convert(r.Context(), from, to, amount, roundingMode)
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	http_server.SendSuccessResponse(w, ToGetConversionResponse(*conversion))
+}
@@ -0,0 +1,98 @@
+package conversion
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"currency-rate-app/internal/domains/currency"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockService struct {
+	convertFn func(ctx context.Context, from currency.CurrencyCode, to currency.CurrencyCode, amount decimal.Decimal, mode currency.RoundingMode) (*currency.Conversion, error)
+}
+
+func (m *mockService) Convert(ctx context.Context, from currency.CurrencyCode, to currency.CurrencyCode, amount decimal.Decimal, mode currency.RoundingMode) (*currency.Conversion, error) {
+	return m.convertFn(ctx, from, to, amount, mode)
+}
+
+func setupMux(service *mockService) *http.ServeMux {
+	mux := http.NewServeMux()
+	NewConversionController(mux, service)
+	return mux
+}
+
+func TestGetConversion_Success(t *testing.T) {
+	completed := time.Date(2024, 10, 2, 15, 4, 5, 0, time.UTC)
+	var gotMode currency.RoundingMode
+	service := &mockService{
+		convertFn: func(ctx context.Context, from currency.CurrencyCode, to currency.CurrencyCode, amount decimal.Decimal, mode currency.RoundingMode) (*currency.Conversion, error) {
+			gotMode = mode
+			rate := currency.CurrencyRate{BaseCurrency: from, ResultCurrency: to, Rate: ptr(decimal.RequireFromString("0.92")), CompletedAt: &completed}
+			return currency.Convert(amount, rate, mode)
+		},
+	}
+	mux := setupMux(service)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/conversions?from=USD&to=EUR&amount=123.45", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, currency.RoundingModeHalfEven, gotMode)
+
+	var body GetConversionResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+
+	assert.Equal(t, "113.57", body.ConvertedAmount)
+	assert.Equal(t, "123.45", body.Amount.String())
+	assert.Equal(t, "0.92", body.Rate.String())
+	assert.Equal(t, completed, body.RateCompletedAt)
+}
+
+func TestGetConversion_ValidationErrors(t *testing.T) {
+	service := &mockService{
+		convertFn: func(ctx context.Context, from currency.CurrencyCode, to currency.CurrencyCode, amount decimal.Decimal, mode currency.RoundingMode) (*currency.Conversion, error) {
+			t.Error("invalid requests shouldn't reach the rate lookup")
+			return nil, nil
+		},
+	}
+	mux := setupMux(service)
+
+	tests := []struct {
+		name  string
+		query string
+		code  string
+	}{
+		{"invalid_currency", "from=AAA&to=EUR&amount=1", "InvalidCurrencyCode"},
+		{"invalid_amount", "from=USD&to=EUR&amount=abc", "InvalidAmount"},
+		{"negative_amount", "from=USD&to=EUR&amount=-1", "InvalidAmount"},
+		{"huge_exponent", "from=USD&to=EUR&amount=1e1000000000", "InvalidAmount"},
+		{"tiny_exponent", "from=USD&to=EUR&amount=1e-1000000000", "InvalidAmount"},
+		{"too_many_digits", "from=USD&to=EUR&amount=123456789012345678901", "InvalidAmount"},
+		{"invalid_rounding", "from=USD&to=EUR&amount=1&rounding=ceiling", "InvalidRoundingMode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/conversions?"+tt.query, nil)
+			res := httptest.NewRecorder()
+
+			mux.ServeHTTP(res, req)
+
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+			assert.Contains(t, res.Body.String(), tt.code)
+		})
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -0,0 +1,30 @@
+package conversion
+
+import (
+	"currency-rate-app/internal/domains/currency"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type GetConversionResponse struct {
+	From            currency.CurrencyCode `json:"from"`
+	To              currency.CurrencyCode `json:"to"`
+	Amount          decimal.Decimal       `json:"amount" swaggertype:"string" example:"123.45"`
+	ConvertedAmount string                `json:"convertedAmount" example:"113.57"`
+	RoundingMode    currency.RoundingMode `json:"roundingMode"`
+	Rate            decimal.Decimal       `json:"rate" swaggertype:"string" example:"0.92"`
+	RateCompletedAt time.Time             `json:"rateCompletedAt"`
+}
+
+func ToGetConversionResponse(conversion currency.Conversion) *GetConversionResponse {
+	return &GetConversionResponse{
+		From:            conversion.From,
+		To:              conversion.To,
+		Amount:          conversion.Amount,
+		ConvertedAmount: conversion.FormattedAmount(),
+		RoundingMode:    conversion.RoundingMode,
+		Rate:            conversion.Rate,
+		RateCompletedAt: conversion.RateCompletedAt.UTC(),
+	}
+}
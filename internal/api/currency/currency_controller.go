@@ -2,9 +2,15 @@ package currency
 
 import (
 	"encoding/json"
+	"math"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"currency-rate-app/internal/application"
+	"currency-rate-app/internal/common/auth"
 	error_utils "currency-rate-app/internal/common/error-utils"
 	http_server "currency-rate-app/internal/common/http-server"
 	"currency-rate-app/internal/common/validation"
@@ -12,36 +18,72 @@ import (
 )
 
 type CurrencyController struct {
-	service application.CurrencyService
+	service        application.CurrencyService
+	pricingService application.PricingService
+	waitService    application.RateWaitService
+	// maxWait caps how long a request may block for a pending rate
+	maxWait time.Duration
+	// pollInterval is the Retry-After sent with pending rates
+	pollInterval time.Duration
 }
 
 func NewCurrencyController(
 	mux *http.ServeMux,
 	service application.CurrencyService,
+	pricingService application.PricingService,
+	waitService application.RateWaitService,
+	maxWait time.Duration,
+	pollInterval time.Duration,
 ) *CurrencyController {
-	controller := &CurrencyController{service: service}
+	controller := &CurrencyController{
+		service:        service,
+		pricingService: pricingService,
+		waitService:    waitService,
+		maxWait:        maxWait,
+		pollInterval:   pollInterval,
+	}
 
 	mux.HandleFunc("GET /v1/currencies/actual", func(w http.ResponseWriter, r *http.Request) {
 		controller.GetActualCurrencyHandler(w, r)
 	})
+	mux.HandleFunc("GET /v1/currencies/codes", func(w http.ResponseWriter, r *http.Request) {
+		controller.getCurrencyCodesHandler(w, r)
+	})
+	mux.HandleFunc("GET /v1/currencies/matrix", func(w http.ResponseWriter, r *http.Request) {
+		controller.getRateMatrixHandler(w, r)
+	})
 	mux.HandleFunc("GET /v1/currencies/{id}", func(w http.ResponseWriter, r *http.Request) {
 		controller.getCurrencyByIdHandler(w, r)
 	})
+	mux.HandleFunc("GET /v1/currencies/{id}/status", func(w http.ResponseWriter, r *http.Request) {
+		controller.getRateStatusHandler(w, r)
+	})
 	mux.HandleFunc("POST /v1/currencies", func(w http.ResponseWriter, r *http.Request) {
 		controller.createCurrencyRateHandler(w, r)
 	})
+	mux.HandleFunc("GET /v1/currencies", func(w http.ResponseWriter, r *http.Request) {
+		controller.listRatesHandler(w, r)
+	})
+	mux.HandleFunc("DELETE /v1/currencies/{id}", func(w http.ResponseWriter, r *http.Request) {
+		controller.cancelRateHandler(w, r)
+	})
 
 	return controller
 }
 
 // @Summary      Get actual currency rate
-// @Description  Get actual currency rate by currency code
+// @Description  Get actual currency rate by currency code, with bid and ask after the configured spread
 // @Tags         currency
 // @Accept       json
 // @Produce      json
 // @Success 200  {object} GetCurrencyResponse
 // @Param        baseCurrency   query      currency.CurrencyCode  true  "Currency Code"
 // @Param        resultCurrency   query      currency.CurrencyCode  true  "Currency Code"
+// @Param        date   query      string  false  "Business date (YYYY-MM-DD), latest rate when omitted"
+// @Param        allowInverse   query      bool  false  "Answer with 1/rate of the opposite pair when it is fresher"
+// @Param        maxAge   query      int  false  "Max age of the rate in seconds (up to 31622400, one year), overrides the configured default for the pair"
+// @Param        stalePolicy   query      string  false  "What to do with a rate older than maxAge, allow by default"  Enums(allow, error)
+// @Param        rateFormat   query      string  false  "Rate encoding, number is kept for legacy clients"  Enums(string, number)
 // @Router       /v1/currencies/actual [get]
 func (c *CurrencyController) GetActualCurrencyHandler(w http.ResponseWriter, r *http.Request) {
 	baseCurrency := currency.CurrencyCode(r.URL.Query().Get("baseCurrency"))
@@ -55,7 +97,56 @@ func (c *CurrencyController) GetActualCurrencyHandler(w http.ResponseWriter, r *
 		return
 	}
 
-	currencyRate, err := c.service.GetActualRate(r.Context(), baseCurrency, resultCurrency)
+	rateDate, err := currency.ParseRateDate(r.URL.Query().Get("date"))
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	rateFormat, err := parseRateFormat(r)
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	allowInverse, err := parseBoolQuery(r, "allowInverse")
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	maxAge, err := parseMaxAge(r)
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	stalePolicy := currency.StalePolicy(r.URL.Query().Get("stalePolicy"))
+
+	if stalePolicy == "" {
+		stalePolicy = currency.StalePolicyAllow
+	}
+
+	if !stalePolicy.IsValid() {
+		http_server.SendErrorResponse(w, currency.ErrInvalidStalePolicy())
+
+		return
+	}
+
+	currencyRate, err := c.service.GetActualRate(r.Context(), baseCurrency, resultCurrency, application.ActualRateOptions{
+		RateDate:     rateDate,
+		AllowInverse: allowInverse,
+		MaxAge:       maxAge,
+		StalePolicy:  stalePolicy,
+	})
 
 	if err != nil {
 		http_server.SendErrorResponse(w, err)
@@ -63,16 +154,86 @@ func (c *CurrencyController) GetActualCurrencyHandler(w http.ResponseWriter, r *
 		return
 	}
 
-	http_server.SendSuccessResponse(w, ToGetCurrencyResponse(*currencyRate))
+	priced, err := c.pricingService.PriceRate(r.Context(), *currencyRate)
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	http_server.SendSuccessResponse(w, ToGetCurrencyResponse(*currencyRate, *priced, rateFormat))
+}
+
+// @Summary      Get currency codes
+// @Description  Get all supported ISO 4217 currency codes
+// @Tags         currency
+// @Accept       json
+// @Produce      json
+// @Success 200  {object} GetCurrencyCodesResponse
+// @Router       /v1/currencies/codes [get]
+func (c *CurrencyController) getCurrencyCodesHandler(w http.ResponseWriter, r *http.Request) {
+	http_server.SendSuccessResponse(w, ToGetCurrencyCodesResponse(currency.ListCurrencies()))
+}
+
+// @Summary      Get rate matrix
+// @Description  Get the freshest rate of every ordered pair of the codes in one request. Pairs without a rate are listed with available false
+// @Tags         currency
+// @Accept       json
+// @Produce      json
+// @Success 200  {object} GetRateMatrixResponse
+// @Param        codes   query      string  true  "Comma separated currency codes, e.g. USD,EUR,MXN"
+// @Param        date   query      string  false  "Business date (YYYY-MM-DD), latest rates when omitted"
+// @Param        rateFormat   query      string  false  "Rate encoding, number is kept for legacy clients"  Enums(string, number)
+// @Router       /v1/currencies/matrix [get]
+func (c *CurrencyController) getRateMatrixHandler(w http.ResponseWriter, r *http.Request) {
+	var codes []currency.CurrencyCode
+
+	if value := r.URL.Query().Get("codes"); value != "" {
+		for _, code := range strings.Split(value, ",") {
+			codes = append(codes, currency.CurrencyCode(strings.TrimSpace(code)))
+		}
+	}
+
+	rateDate, err := currency.ParseRateDate(r.URL.Query().Get("date"))
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	rateFormat, err := parseRateFormat(r)
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	matrix, err := c.service.GetRateMatrix(r.Context(), codes, rateDate)
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	http_server.SendSuccessResponse(w, ToGetRateMatrixResponse(matrix, rateDate, rateFormat))
 }
 
 // @Summary      Get currency rate by id
-// @Description  Get currency rate by id
+// @Description  Get currency rate by id. With wait the request blocks until the rate is COMPLETED or FAILED, 202 with the id when the wait runs out
 // @Tags         currency
 // @Accept       json
 // @Produce      json
 // @Success 200  {object} GetCurrencyResponse
+// @Success 202  {object} CreateRateResponse
+// @Header  202  {string} Location "Status of the rate request"
+// @Header  202  {integer} Retry-After "Seconds to wait before polling again"
 // @Param        id        path      string  true  "Id"
+// @Param        wait   query      int  false  "Seconds to wait for a pending rate, capped by the server"
+// @Param        rateFormat   query      string  false  "Rate encoding, number is kept for legacy clients"  Enums(string, number)
 // @Router       /v1/currencies/{id} [get]
 func (c *CurrencyController) getCurrencyByIdHandler(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
@@ -83,6 +244,46 @@ func (c *CurrencyController) getCurrencyByIdHandler(w http.ResponseWriter, r *ht
 		return
 	}
 
+	rateFormat, err := parseRateFormat(r)
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	wait, err := parseWaitQuery(r)
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	c.sendRate(w, r, id, wait, rateFormat)
+}
+
+// sendRate answers with the completed rate. With a positive wait it first
+// blocks until the rate is final and answers 202 with the id if it isn't.
+func (c *CurrencyController) sendRate(w http.ResponseWriter, r *http.Request, id string, wait time.Duration, rateFormat RateFormat) {
+	if wait > 0 {
+		wait = min(wait, c.maxWait)
+
+		final, err := c.waitService.WaitForFinalStatus(r.Context(), id, wait)
+
+		if err != nil {
+			http_server.SendErrorResponse(w, err)
+
+			return
+		}
+
+		if !final {
+			c.sendAccepted(w, id, c.pollInterval)
+
+			return
+		}
+	}
+
 	currencyRate, err := c.service.GetCompletedRateById(r.Context(), id)
 
 	if err != nil {
@@ -91,19 +292,137 @@ func (c *CurrencyController) getCurrencyByIdHandler(w http.ResponseWriter, r *ht
 		return
 	}
 
-	http_server.SendSuccessResponse(w, ToGetCurrencyResponse(*currencyRate))
+	priced, err := c.pricingService.PriceRate(r.Context(), *currencyRate)
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	response := ToGetCurrencyResponse(*currencyRate, *priced, rateFormat)
+	response.Id = currencyRate.Id
+
+	http_server.SendSuccessResponse(w, response)
+}
+
+// @Summary      List currency rate requests
+// @Description  List rate requests with filters, newest first by default. Pass nextCursor of the response as cursor to get the next page with the same sort
+// @Tags         currency
+// @Accept       json
+// @Produce      json
+// @Success 200  {object} ListRatesResponse
+// @Param        status   query      string  false  "Comma separated statuses, e.g. PENDING,PROCESSING"
+// @Param        baseCurrency   query      currency.CurrencyCode  false  "Currency Code"
+// @Param        resultCurrency   query      currency.CurrencyCode  false  "Currency Code"
+// @Param        idempotencyKey   query      string  false  "Idempotency key"
+// @Param        createdFrom   query      string  false  "Created at or after (RFC 3339)"
+// @Param        createdTo   query      string  false  "Created before (RFC 3339)"
+// @Param        completedFrom   query      string  false  "Completed at or after (RFC 3339)"
+// @Param        completedTo   query      string  false  "Completed before (RFC 3339)"
+// @Param        sort   query      string  false  "Sort field, createdAt by default"  Enums(createdAt, updatedAt)
+// @Param        order   query      string  false  "Sort order, desc by default"  Enums(asc, desc)
+// @Param        limit   query      int  false  "Page size, 50 by default, at most 200"
+// @Param        cursor   query      string  false  "nextCursor of the previous page"
+// @Param        rateFormat   query      string  false  "Rate encoding, number is kept for legacy clients"  Enums(string, number)
+// @Router       /v1/currencies [get]
+func (c *CurrencyController) listRatesHandler(w http.ResponseWriter, r *http.Request) {
+	query, err := parseRateListQuery(r)
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	rateFormat, err := parseRateFormat(r)
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	page, err := c.service.ListRates(r.Context(), *query)
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	http_server.SendSuccessResponse(w, ToListRatesResponse(page, rateFormat))
+}
+
+// @Summary      Cancel currency rate
+// @Description  Cancel a PENDING rate request, the worker never picks it up. 409 when it is already PROCESSING or final
+// @Tags         currency
+// @Accept       json
+// @Produce      json
+// @Success 204
+// @Param        id        path      string  true  "Id"
+// @Router       /v1/currencies/{id} [delete]
+func (c *CurrencyController) cancelRateHandler(w http.ResponseWriter, r *http.Request) {
+	if _, err := c.service.CancelRate(r.Context(), r.PathValue("id")); err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary      Get currency rate status
+// @Description  Get the status of a rate request in any state, with attempts and timestamps. Unlike /v1/currencies/{id} it answers 200 while the rate is pending or failed.
+// @Description  Retry-After is set while the rate isn't final
+// @Tags         currency
+// @Accept       json
+// @Produce      json
+// @Success 200  {object} RateStatusResponse
+// @Header  200  {integer} Retry-After "Seconds to wait before polling again, absent for final rates"
+// @Param        id        path      string  true  "Id"
+// @Router       /v1/currencies/{id}/status [get]
+func (c *CurrencyController) getRateStatusHandler(w http.ResponseWriter, r *http.Request) {
+	currencyRate, err := c.service.GetRateById(r.Context(), r.PathValue("id"))
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	if retryAfter := currencyRate.RetryAfter(time.Now(), c.pollInterval); retryAfter > 0 {
+		w.Header().Set("Retry-After", formatRetryAfter(retryAfter))
+	}
+
+	http_server.SendSuccessResponse(w, ToRateStatusResponse(*currencyRate))
 }
 
 // @Summary      Create currency rate
-// @Description  Create currency rate
+// @Description  Create currency rate. With callbackUrl the result is POSTed there once the request is COMPLETED or FAILED, signed like alert webhooks.
+// @Description  With Prefer: wait=N the request blocks until the rate is final and answers with the rate, 202 with the id when the wait runs out.
+// @Description  With Prefer: respond-async it answers 202 with Location of the status resource and Retry-After, without it 200 with the id as in the original v1 contract
 // @Tags         currency
 // @Accept       json
 // @Produce      json
 // @Success 200  {object} CreateRateResponse
+// @Success 202  {object} CreateRateResponse
+// @Header  202  {string} Location "Status of the rate request"
+// @Header  202  {integer} Retry-After "Seconds to wait before polling the status"
 // @Param 		 Idempotency-Key header string true "Idempotency key for request"
+// @Param 		 Prefer header string false "respond-async for 202 with Location, wait=N to wait up to N seconds for the rate, capped by the server"
+// @Param        rateFormat   query      string  false  "Rate encoding of the Prefer: wait answer, number is kept for legacy clients"  Enums(string, number)
 // @Param        request   body      CreateRateRequest  true  "Body"
 // @Router       /v1/currencies [post]
 func (c *CurrencyController) createCurrencyRateHandler(w http.ResponseWriter, r *http.Request) {
+	rateFormat, err := parseRateFormat(r)
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
 	var dto CreateRateRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
@@ -124,6 +443,14 @@ func (c *CurrencyController) createCurrencyRateHandler(w http.ResponseWriter, r
 		return
 	}
 
+	rateDate, err := currency.ParseRateDate(dto.Date)
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
 	idempotencyKey := r.Header.Get("Idempotency-Key")
 
 	if idempotencyKey == "" {
@@ -132,7 +459,15 @@ func (c *CurrencyController) createCurrencyRateHandler(w http.ResponseWriter, r
 		return
 	}
 
-	currencyRate, err := c.service.CreateRate(r.Context(), dto.BaseCurrency, dto.ResultCurrency, idempotencyKey)
+	var callbackUrl *string
+
+	if dto.CallbackUrl != "" {
+		callbackUrl = &dto.CallbackUrl
+	}
+
+	clientId, _ := auth.ClientFromContext(r.Context())
+
+	currencyRate, err := c.service.CreateRate(r.Context(), dto.BaseCurrency, dto.ResultCurrency, rateDate, clientId, idempotencyKey, callbackUrl)
 
 	if err != nil {
 		http_server.SendErrorResponse(w, err)
@@ -140,9 +475,227 @@ func (c *CurrencyController) createCurrencyRateHandler(w http.ResponseWriter, r
 		return
 	}
 
+	if wait := parsePreferWait(r); wait > 0 {
+		w.Header().Set("Preference-Applied", "wait="+strconv.Itoa(int(min(wait, c.maxWait).Seconds())))
+		c.sendRate(w, r, currencyRate.Id, wait, rateFormat)
+
+		return
+	}
+
+	if preferRespondAsync(r) {
+		w.Header().Set("Preference-Applied", "respond-async")
+		c.sendAccepted(w, currencyRate.Id, currencyRate.RetryAfter(time.Now(), c.pollInterval))
+
+		return
+	}
+
 	response := &CreateRateResponse{
 		Id: currencyRate.Id,
 	}
 
 	http_server.SendSuccessResponse(w, response)
 }
+
+// sendAccepted answers 202 with the id and points the client at the status
+// resource of the rate.
+func (c *CurrencyController) sendAccepted(w http.ResponseWriter, id string, retryAfter time.Duration) {
+	w.Header().Set("Location", "/v1/currencies/"+url.PathEscape(id)+"/status")
+	w.Header().Set("Retry-After", formatRetryAfter(retryAfter))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(&CreateRateResponse{Id: id})
+}
+
+// formatRetryAfter rounds up to whole seconds, Retry-After has no fractions.
+func formatRetryAfter(retryAfter time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))
+}
+
+func parseRateFormat(r *http.Request) (RateFormat, error) {
+	rateFormat := RateFormat(r.URL.Query().Get("rateFormat"))
+
+	if rateFormat == "" {
+		return RateFormatString, nil
+	}
+
+	if !rateFormat.IsValid() {
+		return "", error_utils.ErrValidationError("rateFormat should be one of: string, number")
+	}
+
+	return rateFormat, nil
+}
+
+func parseBoolQuery(r *http.Request, name string) (bool, error) {
+	value := r.URL.Query().Get(name)
+
+	if value == "" {
+		return false, nil
+	}
+
+	res, err := strconv.ParseBool(value)
+
+	if err != nil {
+		return false, error_utils.ErrValidationError(name + " should be a boolean")
+	}
+
+	return res, nil
+}
+
+func parseMaxAge(r *http.Request) (*time.Duration, error) {
+	value := r.URL.Query().Get("maxAge")
+
+	if value == "" {
+		return nil, nil
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+
+	if err != nil || seconds <= 0 || seconds > int64(currency.MaxRateAge/time.Second) {
+		return nil, currency.ErrInvalidMaxAge()
+	}
+
+	maxAge := time.Duration(seconds) * time.Second
+
+	return &maxAge, nil
+}
+
+func parseWaitQuery(r *http.Request) (time.Duration, error) {
+	value := r.URL.Query().Get("wait")
+
+	if value == "" {
+		return 0, nil
+	}
+
+	seconds, err := strconv.Atoi(value)
+
+	if err != nil || seconds < 0 {
+		return 0, error_utils.ErrValidationError("wait should be a non-negative number of seconds")
+	}
+
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// parsePreferWait reads wait=N from the Prefer header (RFC 7240). Preferences
+// are optional by definition, so a malformed value is ignored.
+func parsePreferWait(r *http.Request) time.Duration {
+	for _, header := range r.Header.Values("Prefer") {
+		for _, preference := range strings.Split(header, ",") {
+			token, _, _ := strings.Cut(preference, ";")
+			name, value, found := strings.Cut(strings.TrimSpace(token), "=")
+
+			if !found || !strings.EqualFold(strings.TrimSpace(name), "wait") {
+				continue
+			}
+
+			seconds, err := strconv.Atoi(strings.Trim(strings.TrimSpace(value), `"`))
+
+			if err != nil || seconds < 0 {
+				return 0
+			}
+
+			return time.Duration(seconds) * time.Second
+		}
+	}
+
+	return 0
+}
+
+func preferRespondAsync(r *http.Request) bool {
+	for _, header := range r.Header.Values("Prefer") {
+		for _, preference := range strings.Split(header, ",") {
+			token, _, _ := strings.Cut(preference, ";")
+
+			if strings.EqualFold(strings.TrimSpace(token), "respond-async") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func parseRateListQuery(r *http.Request) (*currency.RateListQuery, error) {
+	values := r.URL.Query()
+	query := &currency.RateListQuery{
+		Filter: currency.RateFilter{
+			BaseCurrency:   currency.CurrencyCode(values.Get("baseCurrency")),
+			ResultCurrency: currency.CurrencyCode(values.Get("resultCurrency")),
+			IdempotencyKey: values.Get("idempotencyKey"),
+		},
+		SortField: currency.RateSortField(values.Get("sort")),
+		Desc:      true,
+	}
+
+	if value := values.Get("status"); value != "" {
+		for _, item := range strings.Split(value, ",") {
+			status := currency.CurrencyRateStatus(strings.ToUpper(strings.TrimSpace(item)))
+
+			if !status.IsValid() {
+				return nil, currency.ErrInvalidRateListQuery("unknown status " + item)
+			}
+
+			query.Filter.Statuses = append(query.Filter.Statuses, status)
+		}
+	}
+
+	for _, code := range []currency.CurrencyCode{query.Filter.BaseCurrency, query.Filter.ResultCurrency} {
+		if code != "" && !code.IsValid() {
+			return nil, currency.ErrInvalidCurrencyCode()
+		}
+	}
+
+	for name, target := range map[string]**time.Time{
+		"createdFrom":   &query.Filter.CreatedFrom,
+		"createdTo":     &query.Filter.CreatedTo,
+		"completedFrom": &query.Filter.CompletedFrom,
+		"completedTo":   &query.Filter.CompletedTo,
+	} {
+		value := values.Get(name)
+
+		if value == "" {
+			continue
+		}
+
+		parsed, err := time.Parse(time.RFC3339, value)
+
+		if err != nil {
+			return nil, currency.ErrInvalidRateListQuery(name + " should be an RFC 3339 time")
+		}
+
+		*target = &parsed
+	}
+
+	if query.SortField != "" && !query.SortField.IsValid() {
+		return nil, currency.ErrInvalidRateListQuery("sort should be one of: createdAt, updatedAt")
+	}
+
+	switch values.Get("order") {
+	case "", "desc":
+	case "asc":
+		query.Desc = false
+	default:
+		return nil, currency.ErrInvalidRateListQuery("order should be one of: asc, desc")
+	}
+
+	if value := values.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+
+		if err != nil || limit <= 0 {
+			return nil, currency.ErrInvalidRateListQuery("limit should be a positive number")
+		}
+
+		query.Limit = limit
+	}
+
+	if value := values.Get("cursor"); value != "" {
+		cursor, err := currency.ParseRateCursor(value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		query.Cursor = cursor
+	}
+
+	return query, nil
+}
@@ -10,42 +10,93 @@ import (
 	"time"
 
 	"currency-rate-app/internal/application"
+	"currency-rate-app/internal/common/auth"
 	error_utils "currency-rate-app/internal/common/error-utils"
 	http_server "currency-rate-app/internal/common/http-server"
 	"currency-rate-app/internal/domains/currency"
+	"currency-rate-app/internal/domains/pricing"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 )
 
 type mockService struct {
-	getActualFn func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode) (*currency.CurrencyRate, error)
+	getActualFn func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, opts application.ActualRateOptions) (*currency.CurrencyRate, error)
 	getByIdFn   func(ctx context.Context, id string) (*currency.CurrencyRate, error)
-	createFn    func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, idem string) (*currency.CurrencyRate, error)
+	getRateFn   func(ctx context.Context, id string) (*currency.CurrencyRate, error)
+	createFn    func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, date *time.Time, clientId string, idem string, callbackUrl *string) (*currency.CurrencyRate, error)
+	cancelFn    func(ctx context.Context, id string) (*currency.CurrencyRate, error)
+	listFn      func(ctx context.Context, query currency.RateListQuery) (*currency.RatePage, error)
+	matrixFn    func(ctx context.Context, codes []currency.CurrencyCode, date *time.Time) (*currency.RateMatrix, error)
 }
 
-func (m *mockService) GetActualRate(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode) (*currency.CurrencyRate, error) {
-	return m.getActualFn(ctx, base, result)
+func (m *mockService) GetActualRate(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, opts application.ActualRateOptions) (*currency.CurrencyRate, error) {
+	return m.getActualFn(ctx, base, result, opts)
 }
 func (m *mockService) GetCompletedRateById(ctx context.Context, id string) (*currency.CurrencyRate, error) {
 	return m.getByIdFn(ctx, id)
 }
-func (m *mockService) CreateRate(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, idem string) (*currency.CurrencyRate, error) {
-	return m.createFn(ctx, base, result, idem)
+func (m *mockService) GetRateById(ctx context.Context, id string) (*currency.CurrencyRate, error) {
+	return m.getRateFn(ctx, id)
+}
+func (m *mockService) CreateRate(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, date *time.Time, clientId string, idem string, callbackUrl *string) (*currency.CurrencyRate, error) {
+	return m.createFn(ctx, base, result, date, clientId, idem, callbackUrl)
+}
+
+func (m *mockService) GetRateMatrix(ctx context.Context, codes []currency.CurrencyCode, date *time.Time) (*currency.RateMatrix, error) {
+	return m.matrixFn(ctx, codes, date)
+}
+
+func (m *mockService) CancelRate(ctx context.Context, id string) (*currency.CurrencyRate, error) {
+	return m.cancelFn(ctx, id)
+}
+
+func (m *mockService) ListRates(ctx context.Context, query currency.RateListQuery) (*currency.RatePage, error) {
+	return m.listFn(ctx, query)
+}
+
+type mockPricingService struct {
+	application.PricingService
+	priceFn func(ctx context.Context, rate currency.CurrencyRate) (*pricing.PricedRate, error)
+}
+
+func (m *mockPricingService) PriceRate(ctx context.Context, rate currency.CurrencyRate) (*pricing.PricedRate, error) {
+	if m.priceFn == nil {
+		priced := pricing.Apply(*rate.Rate, pricing.Spread{Scope: pricing.DefaultScope})
+		return &priced, nil
+	}
+	return m.priceFn(ctx, rate)
+}
+
+type mockWaitService struct {
+	waitFn func(ctx context.Context, id string, timeout time.Duration) (bool, error)
+}
+
+func (m *mockWaitService) WaitForFinalStatus(ctx context.Context, id string, timeout time.Duration) (bool, error) {
+	return m.waitFn(ctx, id, timeout)
 }
 
 func fixedTime() time.Time { return time.Date(2024, 10, 2, 15, 4, 5, 0, time.UTC) }
 
 func setupMux(currencyService application.CurrencyService) *http.ServeMux {
+	return setupMuxWithPricing(currencyService, &mockPricingService{})
+}
+
+func setupMuxWithPricing(currencyService application.CurrencyService, pricingService application.PricingService) *http.ServeMux {
+	return setupMuxWithWait(currencyService, pricingService, nil)
+}
+
+func setupMuxWithWait(currencyService application.CurrencyService, pricingService application.PricingService, waitService application.RateWaitService) *http.ServeMux {
 	mux := http.NewServeMux()
-	NewCurrencyController(mux, currencyService)
+	NewCurrencyController(mux, currencyService, pricingService, waitService, 30*time.Second, 10*time.Second)
 	return mux
 }
 
 func TestGetActualCurrencyHandler_Success(t *testing.T) {
-	rate := 1.11
+	rate := decimal.RequireFromString("1.11")
 	completed := fixedTime()
 	currencyService := &mockService{
-		getActualFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode) (*currency.CurrencyRate, error) {
+		getActualFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, opts application.ActualRateOptions) (*currency.CurrencyRate, error) {
 			return &currency.CurrencyRate{BaseCurrency: base, ResultCurrency: result, Status: currency.CurrencyRateStatusCompleted, Rate: &rate, CompletedAt: &completed}, nil
 		},
 	}
@@ -64,12 +115,12 @@ func TestGetActualCurrencyHandler_Success(t *testing.T) {
 
 	assert.Equal(t, currency.USD, body.BaseCurrency)
 	assert.Equal(t, currency.EUR, body.ResultCurrency)
-	assert.Equal(t, rate, body.Rate)
+	assert.Equal(t, rate.String(), body.Rate.Value.String())
 }
 
 func TestGetActualCurrencyHandler_WrongCurrencyCodeError(t *testing.T) {
 	currencyService := &mockService{
-		getActualFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode) (*currency.CurrencyRate, error) {
+		getActualFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, opts application.ActualRateOptions) (*currency.CurrencyRate, error) {
 			return nil, nil
 		},
 	}
@@ -85,7 +136,7 @@ func TestGetActualCurrencyHandler_WrongCurrencyCodeError(t *testing.T) {
 
 func TestGetActualCurrencyHandler_SameCurrencyCodesError(t *testing.T) {
 	currencyService := &mockService{
-		getActualFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode) (*currency.CurrencyRate, error) {
+		getActualFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, opts application.ActualRateOptions) (*currency.CurrencyRate, error) {
 			return nil, nil
 		},
 	}
@@ -101,7 +152,7 @@ func TestGetActualCurrencyHandler_SameCurrencyCodesError(t *testing.T) {
 
 func TestGetActualCurrencyHandler_ServiceFail(t *testing.T) {
 	currencyService := &mockService{
-		getActualFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode) (*currency.CurrencyRate, error) {
+		getActualFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, opts application.ActualRateOptions) (*currency.CurrencyRate, error) {
 			return nil, &error_utils.CustomError{
 				ErrorType: error_utils.ErrorCodeNotFound,
 				Code:      "SomethingWrong",
@@ -119,11 +170,12 @@ func TestGetActualCurrencyHandler_ServiceFail(t *testing.T) {
 }
 
 func TestGetRateById_Success(t *testing.T) {
-	rate := 2.22
+	rate := decimal.RequireFromString("2.22")
 	completed := fixedTime()
+	provider := "frankfurter"
 	currencyService := &mockService{
 		getByIdFn: func(ctx context.Context, id string) (*currency.CurrencyRate, error) {
-			return &currency.CurrencyRate{Id: id, BaseCurrency: currency.USD, ResultCurrency: currency.MXN, Status: currency.CurrencyRateStatusCompleted, Rate: &rate, CompletedAt: &completed}, nil
+			return &currency.CurrencyRate{Id: id, BaseCurrency: currency.USD, ResultCurrency: currency.MXN, Status: currency.CurrencyRateStatusCompleted, Rate: &rate, Provider: &provider, CompletedAt: &completed}, nil
 		},
 	}
 	mux := setupMux(currencyService)
@@ -142,7 +194,10 @@ func TestGetRateById_Success(t *testing.T) {
 
 	assert.Equal(t, currency.USD, body.BaseCurrency)
 	assert.Equal(t, currency.MXN, body.ResultCurrency)
-	assert.Equal(t, rate, body.Rate)
+	assert.Equal(t, rate.String(), body.Rate.Value.String())
+	if assert.NotNil(t, body.Provider) {
+		assert.Equal(t, provider, *body.Provider)
+	}
 }
 
 func TestGetRateById_NotFound(t *testing.T) {
@@ -162,8 +217,10 @@ func TestGetRateById_NotFound(t *testing.T) {
 }
 
 func TestCreateRate_Success(t *testing.T) {
+	var gotClientId string
 	currencyService := &mockService{
-		createFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, idem string) (*currency.CurrencyRate, error) {
+		createFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, date *time.Time, clientId string, idem string, callbackUrl *string) (*currency.CurrencyRate, error) {
+			gotClientId = clientId
 			return &currency.CurrencyRate{Id: "qid-1", BaseCurrency: base, ResultCurrency: result, IdempotencyKey: idem, Status: currency.CurrencyRateStatusPending}, nil
 		},
 	}
@@ -175,11 +232,14 @@ func TestCreateRate_Success(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/v1/currencies", body)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Idempotency-Key", "idem-1")
+	req = req.WithContext(auth.WithClient(req.Context(), "client-a"))
 	res := httptest.NewRecorder()
 
 	mux.ServeHTTP(res, req)
 
 	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "client-a", gotClientId, "the key is deduped per API client")
+	assert.Empty(t, res.Header().Get("Location"))
 	var resDto CreateRateResponse
 	if err := json.NewDecoder(res.Body).Decode(&resDto); err != nil {
 		t.Fatalf("invalid response: %v", err)
@@ -187,9 +247,130 @@ func TestCreateRate_Success(t *testing.T) {
 	assert.NotNil(t, resDto.Id)
 }
 
+func TestCreateRate_PreferRespondAsync(t *testing.T) {
+	currencyService := &mockService{
+		createFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, date *time.Time, clientId string, idem string, callbackUrl *string) (*currency.CurrencyRate, error) {
+			return &currency.CurrencyRate{Id: "qid-1", BaseCurrency: base, ResultCurrency: result, IdempotencyKey: idem, Status: currency.CurrencyRateStatusPending}, nil
+		},
+	}
+	mux := setupMux(currencyService)
+
+	body := new(bytes.Buffer)
+	json.NewEncoder(body).Encode(CreateRateRequest{BaseCurrency: currency.USD, ResultCurrency: currency.EUR})
+	req := httptest.NewRequest(http.MethodPost, "/v1/currencies", body)
+	req.Header.Set("Idempotency-Key", "idem-1")
+	req.Header.Set("Prefer", "handling=lenient, respond-async")
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusAccepted, res.Code)
+	assert.Equal(t, "/v1/currencies/qid-1/status", res.Header().Get("Location"))
+	assert.Equal(t, "10", res.Header().Get("Retry-After"))
+	assert.Equal(t, "respond-async", res.Header().Get("Preference-Applied"))
+	var resDto CreateRateResponse
+	if err := json.NewDecoder(res.Body).Decode(&resDto); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	assert.Equal(t, "qid-1", resDto.Id)
+}
+
+func TestGetRateStatus(t *testing.T) {
+	created := fixedTime()
+	nextAttemptAt := time.Now().Add(time.Minute)
+	cases := []struct {
+		name       string
+		rate       currency.CurrencyRate
+		retryAfter bool
+	}{
+		{
+			name:       "pending",
+			rate:       currency.CurrencyRate{Id: "id-1", Status: currency.CurrencyRateStatusPending, CreatedAt: created, UpdatedAt: created},
+			retryAfter: true,
+		},
+		{
+			name:       "backing off",
+			rate:       currency.CurrencyRate{Id: "id-1", Status: currency.CurrencyRateStatusPending, Attempts: 2, NextAttemptAt: &nextAttemptAt, CreatedAt: created, UpdatedAt: created},
+			retryAfter: true,
+		},
+		{
+			name: "failed",
+			rate: currency.CurrencyRate{
+				Id:        "id-1",
+				Status:    currency.CurrencyRateStatusFailed,
+				Attempts:  5,
+				Failure:   &currency.RateFailure{Code: currency.RateFailureProviderUnavailable, Message: "connection refused"},
+				CreatedAt: created,
+				UpdatedAt: created,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			currencyService := &mockService{
+				getRateFn: func(ctx context.Context, id string) (*currency.CurrencyRate, error) {
+					return &tc.rate, nil
+				},
+			}
+			mux := setupMux(currencyService)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/currencies/id-1/status", nil)
+			res := httptest.NewRecorder()
+
+			mux.ServeHTTP(res, req)
+
+			assert.Equal(t, http.StatusOK, res.Code)
+			assert.Equal(t, tc.retryAfter, res.Header().Get("Retry-After") != "")
+			var body RateStatusResponse
+			if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+			assert.Equal(t, tc.rate.Status, body.Status)
+			assert.Equal(t, tc.rate.Attempts, body.Attempts)
+			assert.Equal(t, tc.rate.Status.IsFinal(), body.Final)
+			assert.Equal(t, tc.rate.Failure != nil, body.FailureCode != nil)
+		})
+	}
+}
+
+func TestGetRateStatus_BackoffRetryAfter(t *testing.T) {
+	nextAttemptAt := time.Now().Add(95 * time.Second)
+	currencyService := &mockService{
+		getRateFn: func(ctx context.Context, id string) (*currency.CurrencyRate, error) {
+			return &currency.CurrencyRate{Id: id, Status: currency.CurrencyRateStatusPending, Attempts: 3, NextAttemptAt: &nextAttemptAt}, nil
+		},
+	}
+	mux := setupMux(currencyService)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/currencies/id-1/status", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "95", res.Header().Get("Retry-After"))
+}
+
+func TestGetRateStatus_NotFound(t *testing.T) {
+	currencyService := &mockService{
+		getRateFn: func(ctx context.Context, id string) (*currency.CurrencyRate, error) {
+			return nil, currency.ErrCurrencyRateNotFound()
+		},
+	}
+	mux := setupMux(currencyService)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/currencies/unknown/status", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusNotFound, res.Code)
+}
+
 func TestCreateRate_MissingHeader(t *testing.T) {
 	currencyService := &mockService{
-		createFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, idem string) (*currency.CurrencyRate, error) {
+		createFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, date *time.Time, clientId string, idem string, callbackUrl *string) (*currency.CurrencyRate, error) {
 			return nil, nil
 		},
 	}
@@ -214,7 +395,7 @@ func TestCreateRate_MissingHeader(t *testing.T) {
 
 func TestCreateRate_InvalidBody(t *testing.T) {
 	currencyService := &mockService{
-		createFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, idem string) (*currency.CurrencyRate, error) {
+		createFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, date *time.Time, clientId string, idem string, callbackUrl *string) (*currency.CurrencyRate, error) {
 			return nil, nil
 		},
 	}
@@ -240,7 +421,7 @@ func TestCreateRate_InvalidBody(t *testing.T) {
 
 func TestCreateRate_SameCurrenciesError(t *testing.T) {
 	currencyService := &mockService{
-		createFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, idem string) (*currency.CurrencyRate, error) {
+		createFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, date *time.Time, clientId string, idem string, callbackUrl *string) (*currency.CurrencyRate, error) {
 			return nil, nil
 		},
 	}
@@ -264,3 +445,767 @@ func TestCreateRate_SameCurrenciesError(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, res.Code)
 	assert.Equal(t, "CurrenciesShouldDiffer", resDto.Code)
 }
+
+func TestGetCurrencyCodes_Success(t *testing.T) {
+	mux := setupMux(&mockService{})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/currencies/codes", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	var body GetCurrencyCodesResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+
+	assert.Len(t, body.Items, len(currency.ListCurrencies()))
+
+	var jpy, xau *CurrencyCodeResponse
+	for i := range body.Items {
+		switch body.Items[i].Code {
+		case "JPY":
+			jpy = &body.Items[i]
+		case "XAU":
+			xau = &body.Items[i]
+		}
+	}
+
+	if assert.NotNil(t, jpy) {
+		assert.Equal(t, "392", jpy.NumericCode)
+		assert.Equal(t, 0, *jpy.MinorUnits)
+	}
+	if assert.NotNil(t, xau) {
+		assert.Nil(t, xau.MinorUnits)
+	}
+}
+
+func TestGetActualCurrencyHandler_RegistryCurrency(t *testing.T) {
+	rate := decimal.RequireFromString("0.86")
+	completed := fixedTime()
+	currencyService := &mockService{
+		getActualFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, opts application.ActualRateOptions) (*currency.CurrencyRate, error) {
+			return &currency.CurrencyRate{BaseCurrency: base, ResultCurrency: result, Status: currency.CurrencyRateStatusCompleted, Rate: &rate, CompletedAt: &completed}, nil
+		},
+	}
+	mux := setupMux(currencyService)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/currencies/actual?baseCurrency=GBP&resultCurrency=JPY", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+}
+
+func TestGetActualCurrencyHandler_RateFormat(t *testing.T) {
+	rate := decimal.RequireFromString("17.123456789012345678")
+	completed := fixedTime()
+	currencyService := &mockService{
+		getActualFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, opts application.ActualRateOptions) (*currency.CurrencyRate, error) {
+			return &currency.CurrencyRate{BaseCurrency: base, ResultCurrency: result, Status: currency.CurrencyRateStatusCompleted, Rate: &rate, CompletedAt: &completed}, nil
+		},
+	}
+	mux := setupMux(currencyService)
+
+	tests := []struct {
+		name     string
+		query    string
+		status   int
+		expected string
+	}{
+		{"default_string", "", http.StatusOK, `"rate":"17.123456789012345678"`},
+		{"string", "&rateFormat=string", http.StatusOK, `"rate":"17.123456789012345678"`},
+		{"number", "&rateFormat=number", http.StatusOK, `"rate":17.123456789012345678`},
+		{"invalid", "&rateFormat=float", http.StatusBadRequest, `"code":"ValidationError"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/currencies/actual?baseCurrency=USD&resultCurrency=MXN"+tt.query, nil)
+			res := httptest.NewRecorder()
+
+			mux.ServeHTTP(res, req)
+
+			assert.Equal(t, tt.status, res.Code)
+			assert.Contains(t, res.Body.String(), tt.expected)
+		})
+	}
+}
+
+func TestGetActualCurrencyHandler_DerivedRate(t *testing.T) {
+	rate := decimal.RequireFromString("32")
+	pivot := currency.EUR
+	completed := fixedTime()
+	currencyService := &mockService{
+		getActualFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, opts application.ActualRateOptions) (*currency.CurrencyRate, error) {
+			return &currency.CurrencyRate{BaseCurrency: base, ResultCurrency: result, Status: currency.CurrencyRateStatusCompleted, Rate: &rate, PivotCurrency: &pivot, CompletedAt: &completed}, nil
+		},
+	}
+	mux := setupMux(currencyService)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/currencies/actual?baseCurrency=USD&resultCurrency=THB", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	var body GetCurrencyResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+
+	assert.True(t, body.Derived)
+	assert.Equal(t, &pivot, body.PivotCurrency)
+}
+
+func TestGetActualCurrencyHandler_Date(t *testing.T) {
+	rate := decimal.RequireFromString("1.0921")
+	completed := fixedTime()
+	var gotDate *time.Time
+	currencyService := &mockService{
+		getActualFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, opts application.ActualRateOptions) (*currency.CurrencyRate, error) {
+			gotDate = opts.RateDate
+			return &currency.CurrencyRate{BaseCurrency: base, ResultCurrency: result, RateDate: opts.RateDate, Status: currency.CurrencyRateStatusCompleted, Rate: &rate, CompletedAt: &completed}, nil
+		},
+	}
+	mux := setupMux(currencyService)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/currencies/actual?baseCurrency=EUR&resultCurrency=USD&date=2024-01-15", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	if assert.NotNil(t, gotDate) {
+		assert.Equal(t, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), *gotDate)
+	}
+
+	var body GetCurrencyResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if assert.NotNil(t, body.Date) {
+		assert.Equal(t, "2024-01-15", *body.Date)
+	}
+
+	future := time.Now().UTC().AddDate(0, 0, 2).Format(currency.RateDateLayout)
+	req = httptest.NewRequest(http.MethodGet, "/v1/currencies/actual?baseCurrency=EUR&resultCurrency=USD&date="+future, nil)
+	res = httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.Contains(t, res.Body.String(), "InvalidRateDate")
+}
+
+func TestCreateRate_WithDate(t *testing.T) {
+	var gotDate *time.Time
+	currencyService := &mockService{
+		createFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, date *time.Time, clientId string, idem string, callbackUrl *string) (*currency.CurrencyRate, error) {
+			gotDate = date
+			return &currency.CurrencyRate{Id: "qid-1", BaseCurrency: base, ResultCurrency: result, RateDate: date, IdempotencyKey: idem, Status: currency.CurrencyRateStatusPending}, nil
+		},
+	}
+	mux := setupMux(currencyService)
+
+	dto := CreateRateRequest{BaseCurrency: currency.USD, ResultCurrency: currency.EUR, Date: "2023-12-29"}
+	body := new(bytes.Buffer)
+	json.NewEncoder(body).Encode(dto)
+	req := httptest.NewRequest(http.MethodPost, "/v1/currencies", body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", "idem-1")
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	if assert.NotNil(t, gotDate) {
+		assert.Equal(t, "2023-12-29", gotDate.Format(currency.RateDateLayout))
+	}
+}
+
+func TestGetActualCurrencyHandler_AllowInverse(t *testing.T) {
+	rate := decimal.RequireFromString("0.8")
+	completed := fixedTime()
+	var gotOpts application.ActualRateOptions
+	currencyService := &mockService{
+		getActualFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, opts application.ActualRateOptions) (*currency.CurrencyRate, error) {
+			gotOpts = opts
+			return &currency.CurrencyRate{BaseCurrency: base, ResultCurrency: result, Status: currency.CurrencyRateStatusCompleted, Rate: &rate, Inverted: true, CompletedAt: &completed}, nil
+		},
+	}
+	mux := setupMux(currencyService)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/currencies/actual?baseCurrency=USD&resultCurrency=EUR&allowInverse=true", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.True(t, gotOpts.AllowInverse)
+	assert.Contains(t, res.Body.String(), `"inverted":true`)
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/currencies/actual?baseCurrency=USD&resultCurrency=EUR&allowInverse=maybe", nil)
+	res = httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+}
+
+func TestGetActualCurrencyHandler_Freshness(t *testing.T) {
+	rate := decimal.RequireFromString("1.1")
+	completed := time.Now().Add(-2 * time.Hour)
+	var gotOpts application.ActualRateOptions
+	currencyService := &mockService{
+		getActualFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, opts application.ActualRateOptions) (*currency.CurrencyRate, error) {
+			gotOpts = opts
+			if opts.StalePolicy == currency.StalePolicyError {
+				return nil, currency.ErrCurrencyRateStale(2 * time.Hour)
+			}
+			return &currency.CurrencyRate{BaseCurrency: base, ResultCurrency: result, Status: currency.CurrencyRateStatusCompleted, Rate: &rate, Stale: true, CompletedAt: &completed}, nil
+		},
+	}
+	mux := setupMux(currencyService)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/currencies/actual?baseCurrency=USD&resultCurrency=EUR&maxAge=60", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, time.Minute, *gotOpts.MaxAge)
+	assert.Equal(t, currency.StalePolicyAllow, gotOpts.StalePolicy)
+
+	var body GetCurrencyResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	assert.True(t, body.Stale)
+	assert.InDelta(t, 7200, body.AgeSeconds, 5)
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/currencies/actual?baseCurrency=USD&resultCurrency=EUR&maxAge=60&stalePolicy=error", nil)
+	res = httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+	assert.Contains(t, res.Body.String(), "CurrencyRateStale")
+
+	for _, query := range []string{"maxAge=-1", "maxAge=abc", "maxAge=31622401", "maxAge=9223372036854775807", "stalePolicy=ignore"} {
+		req = httptest.NewRequest(http.MethodGet, "/v1/currencies/actual?baseCurrency=USD&resultCurrency=EUR&"+query, nil)
+		res = httptest.NewRecorder()
+
+		mux.ServeHTTP(res, req)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code, query)
+	}
+}
+
+func TestGetActualCurrencyHandler_Spread(t *testing.T) {
+	rate := decimal.RequireFromString("1.2")
+	completed := fixedTime()
+	currencyService := &mockService{
+		getActualFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, opts application.ActualRateOptions) (*currency.CurrencyRate, error) {
+			return &currency.CurrencyRate{BaseCurrency: base, ResultCurrency: result, Status: currency.CurrencyRateStatusCompleted, Rate: &rate, CompletedAt: &completed}, nil
+		},
+	}
+	var pricedPair string
+	pricingService := &mockPricingService{
+		priceFn: func(ctx context.Context, rate currency.CurrencyRate) (*pricing.PricedRate, error) {
+			pricedPair = string(rate.BaseCurrency) + string(rate.ResultCurrency)
+			priced := pricing.Apply(*rate.Rate, pricing.Spread{Scope: "EURUSD", BidBps: 50, AskBps: 100})
+			return &priced, nil
+		},
+	}
+	mux := setupMuxWithPricing(currencyService, pricingService)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/currencies/actual?baseCurrency=EUR&resultCurrency=USD", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	var body map[string]any
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+
+	assert.Equal(t, "EURUSD", pricedPair)
+	assert.Equal(t, "1.2", body["rate"])
+	assert.Equal(t, "1.2", body["mid"])
+	assert.Equal(t, "1.194", body["bid"])
+	assert.Equal(t, "1.212", body["ask"])
+	assert.Equal(t, "EURUSD", body["spreadScope"])
+	assert.EqualValues(t, 50, body["bidBps"])
+	assert.EqualValues(t, 100, body["askBps"])
+}
+
+func TestGetActualCurrencyHandler_PricingFail(t *testing.T) {
+	rate := decimal.RequireFromString("1.2")
+	completed := fixedTime()
+	currencyService := &mockService{
+		getActualFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, opts application.ActualRateOptions) (*currency.CurrencyRate, error) {
+			return &currency.CurrencyRate{BaseCurrency: base, ResultCurrency: result, Status: currency.CurrencyRateStatusCompleted, Rate: &rate, CompletedAt: &completed}, nil
+		},
+	}
+	pricingService := &mockPricingService{
+		priceFn: func(ctx context.Context, rate currency.CurrencyRate) (*pricing.PricedRate, error) {
+			return nil, error_utils.ErrInternalServerError("db down")
+		},
+	}
+	mux := setupMuxWithPricing(currencyService, pricingService)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/currencies/actual?baseCurrency=EUR&resultCurrency=USD", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusInternalServerError, res.Code)
+}
+
+func TestCreateRate_WithCallbackUrl(t *testing.T) {
+	var gotCallbackUrl *string
+	currencyService := &mockService{
+		createFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, date *time.Time, clientId string, idem string, callbackUrl *string) (*currency.CurrencyRate, error) {
+			gotCallbackUrl = callbackUrl
+			return &currency.CurrencyRate{Id: "qid-1", BaseCurrency: base, ResultCurrency: result, IdempotencyKey: idem, Status: currency.CurrencyRateStatusPending}, nil
+		},
+	}
+	mux := setupMux(currencyService)
+
+	for _, tc := range []struct {
+		callbackUrl string
+		code        int
+	}{
+		{callbackUrl: "https://hooks.example.com/rates", code: http.StatusOK},
+		{callbackUrl: "not a url", code: http.StatusBadRequest},
+	} {
+		gotCallbackUrl = nil
+		dto := CreateRateRequest{BaseCurrency: currency.USD, ResultCurrency: currency.EUR, CallbackUrl: tc.callbackUrl}
+		body := new(bytes.Buffer)
+		json.NewEncoder(body).Encode(dto)
+		req := httptest.NewRequest(http.MethodPost, "/v1/currencies", body)
+		req.Header.Set("Idempotency-Key", "idem-1")
+		res := httptest.NewRecorder()
+
+		mux.ServeHTTP(res, req)
+
+		assert.Equal(t, tc.code, res.Code, tc.callbackUrl)
+		if tc.code == http.StatusOK && assert.NotNil(t, gotCallbackUrl) {
+			assert.Equal(t, tc.callbackUrl, *gotCallbackUrl)
+		}
+	}
+}
+
+func TestGetRateById_WaitCompleted(t *testing.T) {
+	rate := decimal.RequireFromString("1.11")
+	completed := fixedTime()
+	var waited time.Duration
+	currencyService := &mockService{
+		getByIdFn: func(ctx context.Context, id string) (*currency.CurrencyRate, error) {
+			return &currency.CurrencyRate{Id: id, BaseCurrency: currency.USD, ResultCurrency: currency.EUR, Status: currency.CurrencyRateStatusCompleted, Rate: &rate, CompletedAt: &completed}, nil
+		},
+	}
+	waitService := &mockWaitService{
+		waitFn: func(ctx context.Context, id string, timeout time.Duration) (bool, error) {
+			waited = timeout
+			return true, nil
+		},
+	}
+	mux := setupMuxWithWait(currencyService, &mockPricingService{}, waitService)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/currencies/id-1?wait=600", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, 30*time.Second, waited, "wait should be capped")
+	var body GetCurrencyResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	assert.Equal(t, "id-1", body.Id)
+	assert.True(t, rate.Equal(body.Rate.Value))
+}
+
+func TestGetRateById_WaitTimeout(t *testing.T) {
+	currencyService := &mockService{}
+	waitService := &mockWaitService{
+		waitFn: func(ctx context.Context, id string, timeout time.Duration) (bool, error) {
+			return false, nil
+		},
+	}
+	mux := setupMuxWithWait(currencyService, &mockPricingService{}, waitService)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/currencies/id-1?wait=5", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusAccepted, res.Code)
+	assert.Equal(t, "/v1/currencies/id-1/status", res.Header().Get("Location"))
+	assert.Equal(t, "10", res.Header().Get("Retry-After"))
+	var body CreateRateResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	assert.Equal(t, "id-1", body.Id)
+}
+
+func TestGetRateById_InvalidWait(t *testing.T) {
+	mux := setupMuxWithWait(&mockService{}, &mockPricingService{}, &mockWaitService{})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/currencies/id-1?wait=-1", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+}
+
+func TestCreateRate_PreferWait(t *testing.T) {
+	rate := decimal.RequireFromString("1.11")
+	completed := fixedTime()
+	var waited time.Duration
+	currencyService := &mockService{
+		createFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, date *time.Time, clientId string, idem string, callbackUrl *string) (*currency.CurrencyRate, error) {
+			return &currency.CurrencyRate{Id: "qid-1", BaseCurrency: base, ResultCurrency: result, Status: currency.CurrencyRateStatusPending}, nil
+		},
+		getByIdFn: func(ctx context.Context, id string) (*currency.CurrencyRate, error) {
+			return &currency.CurrencyRate{Id: id, BaseCurrency: currency.USD, ResultCurrency: currency.EUR, Status: currency.CurrencyRateStatusCompleted, Rate: &rate, CompletedAt: &completed}, nil
+		},
+	}
+	waitService := &mockWaitService{
+		waitFn: func(ctx context.Context, id string, timeout time.Duration) (bool, error) {
+			waited = timeout
+			return true, nil
+		},
+	}
+	mux := setupMuxWithWait(currencyService, &mockPricingService{}, waitService)
+
+	body := new(bytes.Buffer)
+	json.NewEncoder(body).Encode(CreateRateRequest{BaseCurrency: currency.USD, ResultCurrency: currency.EUR})
+	req := httptest.NewRequest(http.MethodPost, "/v1/currencies", body)
+	req.Header.Set("Idempotency-Key", "idem-1")
+	req.Header.Set("Prefer", "respond-async, wait=10")
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, 10*time.Second, waited)
+	assert.Equal(t, "wait=10", res.Header().Get("Preference-Applied"))
+	var resDto GetCurrencyResponse
+	if err := json.NewDecoder(res.Body).Decode(&resDto); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	assert.Equal(t, "qid-1", resDto.Id)
+	assert.True(t, rate.Equal(resDto.Mid.Value))
+}
+
+func TestCreateRate_PreferWaitRateFormat(t *testing.T) {
+	rate := decimal.RequireFromString("17.123456789012345678")
+	completed := fixedTime()
+	created := 0
+	currencyService := &mockService{
+		createFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, date *time.Time, clientId string, idem string, callbackUrl *string) (*currency.CurrencyRate, error) {
+			created++
+			return &currency.CurrencyRate{Id: "qid-1", BaseCurrency: base, ResultCurrency: result, Status: currency.CurrencyRateStatusPending}, nil
+		},
+		getByIdFn: func(ctx context.Context, id string) (*currency.CurrencyRate, error) {
+			return &currency.CurrencyRate{Id: id, BaseCurrency: currency.USD, ResultCurrency: currency.EUR, Status: currency.CurrencyRateStatusCompleted, Rate: &rate, CompletedAt: &completed}, nil
+		},
+	}
+	waitService := &mockWaitService{
+		waitFn: func(ctx context.Context, id string, timeout time.Duration) (bool, error) {
+			return true, nil
+		},
+	}
+	mux := setupMuxWithWait(currencyService, &mockPricingService{}, waitService)
+
+	tests := []struct {
+		name     string
+		query    string
+		status   int
+		expected string
+	}{
+		{"default_string", "", http.StatusOK, `"rate":"17.123456789012345678"`},
+		{"number", "?rateFormat=number", http.StatusOK, `"rate":17.123456789012345678`},
+		{"invalid", "?rateFormat=float", http.StatusBadRequest, `"code":"ValidationError"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := new(bytes.Buffer)
+			json.NewEncoder(body).Encode(CreateRateRequest{BaseCurrency: currency.USD, ResultCurrency: currency.EUR})
+			req := httptest.NewRequest(http.MethodPost, "/v1/currencies"+tt.query, body)
+			req.Header.Set("Idempotency-Key", "idem-1")
+			req.Header.Set("Prefer", "wait=10")
+			res := httptest.NewRecorder()
+
+			mux.ServeHTTP(res, req)
+
+			assert.Equal(t, tt.status, res.Code)
+			assert.Contains(t, res.Body.String(), tt.expected)
+		})
+	}
+
+	assert.Equal(t, 2, created, "an invalid rateFormat is rejected before the rate is created")
+}
+
+func TestCreateRate_PreferWaitFailed(t *testing.T) {
+	currencyService := &mockService{
+		createFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, date *time.Time, clientId string, idem string, callbackUrl *string) (*currency.CurrencyRate, error) {
+			return &currency.CurrencyRate{Id: "qid-1", BaseCurrency: base, ResultCurrency: result, Status: currency.CurrencyRateStatusPending}, nil
+		},
+		getByIdFn: func(ctx context.Context, id string) (*currency.CurrencyRate, error) {
+			return nil, currency.ErrCurrencyRateFetchFailed(nil)
+		},
+	}
+	waitService := &mockWaitService{
+		waitFn: func(ctx context.Context, id string, timeout time.Duration) (bool, error) {
+			return true, nil
+		},
+	}
+	mux := setupMuxWithWait(currencyService, &mockPricingService{}, waitService)
+
+	body := new(bytes.Buffer)
+	json.NewEncoder(body).Encode(CreateRateRequest{BaseCurrency: currency.USD, ResultCurrency: currency.EUR})
+	req := httptest.NewRequest(http.MethodPost, "/v1/currencies", body)
+	req.Header.Set("Idempotency-Key", "idem-1")
+	req.Header.Set("Prefer", "wait=10")
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+}
+
+func TestGetCurrencyById_FailedWithReason(t *testing.T) {
+	currencyService := &mockService{
+		getByIdFn: func(ctx context.Context, id string) (*currency.CurrencyRate, error) {
+			return nil, currency.ErrCurrencyRateFetchFailed(&currency.RateFailure{
+				Code:    currency.RateFailurePairNotSupported,
+				Message: "currency pair not found",
+			})
+		},
+	}
+	mux := setupMux(currencyService)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/currencies/qid-1", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+
+	var resDto struct {
+		Code    string                      `json:"code"`
+		Details currency.RateFailureDetails `json:"details"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&resDto); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	assert.Equal(t, "CurrencyRateFetchFailed", resDto.Code)
+	assert.Equal(t, currency.RateFailurePairNotSupported, resDto.Details.FailureCode)
+	assert.Equal(t, "currency pair not found", resDto.Details.FailureMessage)
+	assert.False(t, resDto.Details.Permanent)
+}
+
+func TestParsePreferWait(t *testing.T) {
+	cases := map[string]time.Duration{
+		"":                      0,
+		"wait=5":                5 * time.Second,
+		"respond-async, wait=7": 7 * time.Second,
+		"Wait = 3":              3 * time.Second,
+		"wait=abc":              0,
+		"return=minimal":        0,
+	}
+
+	for header, expected := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/v1/currencies", nil)
+		if header != "" {
+			req.Header.Set("Prefer", header)
+		}
+
+		assert.Equal(t, expected, parsePreferWait(req), header)
+	}
+}
+
+func TestGetRateMatrix_MissingCells(t *testing.T) {
+	rate := decimal.RequireFromString("1.0845")
+	completed := fixedTime()
+	var requested []currency.CurrencyCode
+	currencyService := &mockService{
+		matrixFn: func(ctx context.Context, codes []currency.CurrencyCode, date *time.Time) (*currency.RateMatrix, error) {
+			requested = codes
+			return &currency.RateMatrix{Codes: codes, Cells: []currency.RateMatrixCell{
+				{BaseCurrency: currency.USD, ResultCurrency: currency.EUR, Rate: &currency.CurrencyRate{BaseCurrency: currency.USD, ResultCurrency: currency.EUR, Rate: &rate, CompletedAt: &completed}},
+				{BaseCurrency: currency.EUR, ResultCurrency: currency.USD},
+			}}, nil
+		},
+	}
+	mux := setupMux(currencyService)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/currencies/matrix?codes=USD,EUR", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, []currency.CurrencyCode{currency.USD, currency.EUR}, requested)
+
+	var body map[string]any
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	cells := body["cells"].([]any)
+	available := cells[0].(map[string]any)
+	missing := cells[1].(map[string]any)
+
+	assert.Equal(t, true, available["available"])
+	assert.Equal(t, "1.0845", available["rate"])
+	assert.Equal(t, "2024-10-02T15:04:05Z", available["completedAt"])
+	assert.Equal(t, false, missing["available"])
+	assert.Contains(t, missing, "rate")
+	assert.Nil(t, missing["rate"])
+	assert.Nil(t, missing["completedAt"])
+}
+
+func TestGetRateMatrix_InvalidCodes(t *testing.T) {
+	currencyService := &mockService{
+		matrixFn: func(ctx context.Context, codes []currency.CurrencyCode, date *time.Time) (*currency.RateMatrix, error) {
+			return nil, currency.ErrInvalidRateMatrixCodes()
+		},
+	}
+	mux := setupMux(currencyService)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/currencies/matrix?codes=USD", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+}
+
+func TestListRates_Filters(t *testing.T) {
+	var received currency.RateListQuery
+	rate := decimal.RequireFromString("1.11")
+	next := &currency.RateCursor{SortField: currency.RateSortUpdatedAt, Value: fixedTime(), Id: "id-2"}
+	currencyService := &mockService{
+		listFn: func(ctx context.Context, query currency.RateListQuery) (*currency.RatePage, error) {
+			received = query
+			return &currency.RatePage{
+				Items: []currency.CurrencyRate{
+					{Id: "id-1", IdempotencyKey: "idem-1", BaseCurrency: currency.USD, ResultCurrency: currency.EUR, Status: currency.CurrencyRateStatusPending, CreatedAt: fixedTime(), UpdatedAt: fixedTime()},
+					{Id: "id-2", BaseCurrency: currency.USD, ResultCurrency: currency.EUR, Status: currency.CurrencyRateStatusCompleted, Rate: &rate},
+				},
+				Next: next,
+			}, nil
+		},
+	}
+	mux := setupMux(currencyService)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/currencies?status=PENDING,processing&baseCurrency=USD&createdFrom=2024-10-01T00:00:00Z&sort=updatedAt&order=asc&limit=2", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, []currency.CurrencyRateStatus{currency.CurrencyRateStatusPending, currency.CurrencyRateStatusProcessing}, received.Filter.Statuses)
+	assert.Equal(t, currency.USD, received.Filter.BaseCurrency)
+	assert.Equal(t, time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC), *received.Filter.CreatedFrom)
+	assert.Nil(t, received.Filter.CreatedTo)
+	assert.Equal(t, currency.RateSortUpdatedAt, received.SortField)
+	assert.False(t, received.Desc)
+	assert.Equal(t, 2, received.Limit)
+
+	var body ListRatesResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	assert.Len(t, body.Items, 2)
+	assert.Nil(t, body.Items[0].Rate)
+	assert.Equal(t, "1.11", body.Items[1].Rate.Value.String())
+
+	cursor, err := currency.ParseRateCursor(*body.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, "id-2", cursor.Id)
+	assert.True(t, next.Value.Equal(cursor.Value))
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/currencies?sort=updatedAt&order=asc&cursor="+*body.NextCursor, nil)
+	res = httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "id-2", received.Cursor.Id)
+}
+
+func TestListRates_InvalidQuery(t *testing.T) {
+	mux := setupMux(&mockService{})
+
+	for _, query := range []string{
+		"status=DONE",
+		"baseCurrency=ABC",
+		"createdFrom=yesterday",
+		"sort=rate",
+		"order=up",
+		"limit=0",
+		"cursor=not-a-cursor",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/v1/currencies?"+query, nil)
+		res := httptest.NewRecorder()
+
+		mux.ServeHTTP(res, req)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code, query)
+	}
+}
+
+func TestCancelRate_Success(t *testing.T) {
+	var cancelled string
+	currencyService := &mockService{
+		cancelFn: func(ctx context.Context, id string) (*currency.CurrencyRate, error) {
+			cancelled = id
+			return &currency.CurrencyRate{Id: id, Status: currency.CurrencyRateStatusCancelled}, nil
+		},
+	}
+	mux := setupMux(currencyService)
+
+	req := httptest.NewRequest(http.MethodDelete, "/v1/currencies/id-1", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusNoContent, res.Code)
+	assert.Equal(t, "id-1", cancelled)
+}
+
+func TestCancelRate_Conflict(t *testing.T) {
+	currencyService := &mockService{
+		cancelFn: func(ctx context.Context, id string) (*currency.CurrencyRate, error) {
+			return nil, currency.ErrCurrencyRateNotCancellable(currency.CurrencyRateStatusProcessing)
+		},
+	}
+	mux := setupMux(currencyService)
+
+	req := httptest.NewRequest(http.MethodDelete, "/v1/currencies/id-1", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusConflict, res.Code)
+	var body http_server.HttpErrorResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	assert.Equal(t, "CurrencyRateNotCancellable", body.Code)
+	assert.Contains(t, body.Message, "PROCESSING")
+}
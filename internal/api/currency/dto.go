@@ -2,30 +2,315 @@ package currency
 
 import (
 	"currency-rate-app/internal/domains/currency"
+	"currency-rate-app/internal/domains/pricing"
+	"encoding/json"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 type CreateRateRequest struct {
 	BaseCurrency   currency.CurrencyCode `json:"baseCurrency" validate:"required,len=3"`
 	ResultCurrency currency.CurrencyCode `json:"resultCurrency" validate:"required,len=3"`
+	Date           string                `json:"date,omitempty" validate:"omitempty,datetime=2006-01-02" example:"2024-10-02"`
+	CallbackUrl    string                `json:"callbackUrl,omitempty" validate:"omitempty,url,max=2048" example:"https://hooks.example.com/rates"`
 }
 
 type CreateRateResponse struct {
 	Id string `json:"id"`
 }
 
+type RateFormat string
+
+const (
+	RateFormatString RateFormat = "string"
+	RateFormatNumber RateFormat = "number"
+)
+
+func (f RateFormat) IsValid() bool {
+	return f == RateFormatString || f == RateFormatNumber
+}
+
+// RateValue is encoded as a JSON string to keep every digit of the decimal.
+// Clients that still expect a JSON number opt in with ?rateFormat=number.
+type RateValue struct {
+	Value    decimal.Decimal
+	AsNumber bool
+}
+
+func (v RateValue) MarshalJSON() ([]byte, error) {
+	if v.AsNumber {
+		return []byte(v.Value.String()), nil
+	}
+
+	return json.Marshal(v.Value.String())
+}
+
+func (v *RateValue) UnmarshalJSON(data []byte) error {
+	v.AsNumber = len(data) > 0 && data[0] != '"'
+
+	return v.Value.UnmarshalJSON(data)
+}
+
 type GetCurrencyResponse struct {
-	BaseCurrency   currency.CurrencyCode `json:"baseCurrency"`
-	ResultCurrency currency.CurrencyCode `json:"resultCurrency"`
-	Rate           float64               `json:"rate"`
-	CompletedAt    time.Time             `json:"completedAt"`
+	Id             string                 `json:"id,omitempty"`
+	BaseCurrency   currency.CurrencyCode  `json:"baseCurrency"`
+	ResultCurrency currency.CurrencyCode  `json:"resultCurrency"`
+	Date           *string                `json:"date,omitempty" example:"2024-10-02"`
+	Rate           RateValue              `json:"rate" swaggertype:"string" example:"1.0845"`
+	Mid            RateValue              `json:"mid" swaggertype:"string" example:"1.0845"`
+	Bid            RateValue              `json:"bid" swaggertype:"string" example:"1.08179875"`
+	Ask            RateValue              `json:"ask" swaggertype:"string" example:"1.08721125"`
+	SpreadScope    string                 `json:"spreadScope" example:"EURUSD"`
+	BidBps         int                    `json:"bidBps" example:"25"`
+	AskBps         int                    `json:"askBps" example:"25"`
+	Derived        bool                   `json:"derived"`
+	PivotCurrency  *currency.CurrencyCode `json:"pivotCurrency,omitempty"`
+	Provider       *string                `json:"provider,omitempty" example:"frankfurter"`
+	Inverted       bool                   `json:"inverted"`
+	Stale          bool                   `json:"stale"`
+	AgeSeconds     int64                  `json:"ageSeconds"`
+	CompletedAt    time.Time              `json:"completedAt"`
 }
 
-func ToGetCurrencyResponse(currencyRate currency.CurrencyRate) *GetCurrencyResponse {
+// ToGetCurrencyResponse keeps rate equal to mid for clients that predate spreads.
+func ToGetCurrencyResponse(currencyRate currency.CurrencyRate, priced pricing.PricedRate, format RateFormat) *GetCurrencyResponse {
+	asNumber := format == RateFormatNumber
+
 	return &GetCurrencyResponse{
 		BaseCurrency:   currencyRate.BaseCurrency,
 		ResultCurrency: currencyRate.ResultCurrency,
-		Rate:           *currencyRate.Rate,
+		Date:           currency.FormatRateDate(currencyRate.RateDate),
+		Rate:           RateValue{Value: priced.Mid, AsNumber: asNumber},
+		Mid:            RateValue{Value: priced.Mid, AsNumber: asNumber},
+		Bid:            RateValue{Value: priced.Bid, AsNumber: asNumber},
+		Ask:            RateValue{Value: priced.Ask, AsNumber: asNumber},
+		SpreadScope:    priced.Spread.Scope,
+		BidBps:         priced.Spread.BidBps,
+		AskBps:         priced.Spread.AskBps,
+		Derived:        currencyRate.PivotCurrency != nil,
+		PivotCurrency:  currencyRate.PivotCurrency,
+		Provider:       currencyRate.Provider,
+		Inverted:       currencyRate.Inverted,
+		Stale:          currencyRate.Stale,
+		AgeSeconds:     int64(currencyRate.Age(time.Now()).Seconds()),
 		CompletedAt:    currencyRate.CompletedAt.UTC(),
 	}
 }
+
+// RateMatrixCellResponse keeps missing pairs in the matrix with available
+// false and null rate fields.
+type RateMatrixCellResponse struct {
+	BaseCurrency   currency.CurrencyCode  `json:"baseCurrency"`
+	ResultCurrency currency.CurrencyCode  `json:"resultCurrency"`
+	Available      bool                   `json:"available"`
+	Rate           *RateValue             `json:"rate" swaggertype:"string" example:"1.0845"`
+	PivotCurrency  *currency.CurrencyCode `json:"pivotCurrency,omitempty"`
+	Stale          bool                   `json:"stale"`
+	AgeSeconds     *int64                 `json:"ageSeconds"`
+	CompletedAt    *time.Time             `json:"completedAt"`
+}
+
+type GetRateMatrixResponse struct {
+	Codes []currency.CurrencyCode  `json:"codes"`
+	Date  *string                  `json:"date,omitempty" example:"2024-10-02"`
+	Cells []RateMatrixCellResponse `json:"cells"`
+}
+
+func ToGetRateMatrixResponse(matrix *currency.RateMatrix, rateDate *time.Time, format RateFormat) *GetRateMatrixResponse {
+	now := time.Now()
+	cells := make([]RateMatrixCellResponse, 0, len(matrix.Cells))
+
+	for _, cell := range matrix.Cells {
+		res := RateMatrixCellResponse{
+			BaseCurrency:   cell.BaseCurrency,
+			ResultCurrency: cell.ResultCurrency,
+		}
+
+		if rate := cell.Rate; rate != nil {
+			ageSeconds := int64(rate.Age(now).Seconds())
+			completedAt := rate.CompletedAt.UTC()
+
+			res.Available = true
+			res.Rate = &RateValue{Value: *rate.Rate, AsNumber: format == RateFormatNumber}
+			res.PivotCurrency = rate.PivotCurrency
+			res.Stale = rate.Stale
+			res.AgeSeconds = &ageSeconds
+			res.CompletedAt = &completedAt
+		}
+
+		cells = append(cells, res)
+	}
+
+	return &GetRateMatrixResponse{
+		Codes: matrix.Codes,
+		Date:  currency.FormatRateDate(rateDate),
+		Cells: cells,
+	}
+}
+
+type RateListItemResponse struct {
+	Id                  string                      `json:"id"`
+	IdempotencyKey      string                      `json:"idempotencyKey"`
+	BaseCurrency        currency.CurrencyCode       `json:"baseCurrency"`
+	ResultCurrency      currency.CurrencyCode       `json:"resultCurrency"`
+	Date                *string                     `json:"date,omitempty" example:"2024-10-02"`
+	Status              currency.CurrencyRateStatus `json:"status"`
+	Rate                *RateValue                  `json:"rate,omitempty" swaggertype:"string" example:"1.0845"`
+	PivotCurrency       *currency.CurrencyCode      `json:"pivotCurrency,omitempty"`
+	Provider            *string                     `json:"provider,omitempty" example:"frankfurter"`
+	CallbackUrl         *string                     `json:"callbackUrl,omitempty"`
+	Attempts            int                         `json:"attempts"`
+	NextAttemptAt       *time.Time                  `json:"nextAttemptAt,omitempty"`
+	WorkerId            *string                     `json:"workerId,omitempty"`
+	ProcessingStartedAt *time.Time                  `json:"processingStartedAt,omitempty"`
+	FailureCode         *currency.RateFailureCode   `json:"failureCode,omitempty"`
+	FailureMessage      *string                     `json:"failureMessage,omitempty"`
+	CreatedAt           time.Time                   `json:"createdAt"`
+	UpdatedAt           time.Time                   `json:"updatedAt"`
+	CompletedAt         *time.Time                  `json:"completedAt,omitempty"`
+}
+
+type ListRatesResponse struct {
+	Items []RateListItemResponse `json:"items"`
+	// NextCursor is absent on the last page.
+	NextCursor *string `json:"nextCursor,omitempty"`
+}
+
+func ToListRatesResponse(page *currency.RatePage, format RateFormat) *ListRatesResponse {
+	items := make([]RateListItemResponse, 0, len(page.Items))
+
+	for _, rate := range page.Items {
+		item := RateListItemResponse{
+			Id:             rate.Id,
+			IdempotencyKey: rate.IdempotencyKey,
+			BaseCurrency:   rate.BaseCurrency,
+			ResultCurrency: rate.ResultCurrency,
+			Date:           currency.FormatRateDate(rate.RateDate),
+			Status:         rate.Status,
+			PivotCurrency:  rate.PivotCurrency,
+			Provider:       rate.Provider,
+			CallbackUrl:    rate.CallbackUrl,
+			Attempts:       rate.Attempts,
+			WorkerId:       rate.WorkerId,
+			CreatedAt:      rate.CreatedAt.UTC(),
+			UpdatedAt:      rate.UpdatedAt.UTC(),
+		}
+
+		if rate.Rate != nil {
+			item.Rate = &RateValue{Value: *rate.Rate, AsNumber: format == RateFormatNumber}
+		}
+
+		if rate.NextAttemptAt != nil {
+			nextAttemptAt := rate.NextAttemptAt.UTC()
+			item.NextAttemptAt = &nextAttemptAt
+		}
+
+		if rate.Failure != nil {
+			item.FailureCode = &rate.Failure.Code
+			item.FailureMessage = &rate.Failure.Message
+		}
+
+		if rate.ProcessingStartedAt != nil {
+			processingStartedAt := rate.ProcessingStartedAt.UTC()
+			item.ProcessingStartedAt = &processingStartedAt
+		}
+
+		if rate.CompletedAt != nil {
+			completedAt := rate.CompletedAt.UTC()
+			item.CompletedAt = &completedAt
+		}
+
+		items = append(items, item)
+	}
+
+	res := &ListRatesResponse{Items: items}
+
+	if page.Next != nil {
+		cursor := page.Next.Encode()
+		res.NextCursor = &cursor
+	}
+
+	return res
+}
+
+// RateStatusResponse is the progress of a rate request, without the rate
+// itself: it is answered with 200 in every status.
+type RateStatusResponse struct {
+	Id                  string                      `json:"id"`
+	Status              currency.CurrencyRateStatus `json:"status"`
+	Final               bool                        `json:"final"`
+	Attempts            int                         `json:"attempts"`
+	NextAttemptAt       *time.Time                  `json:"nextAttemptAt,omitempty"`
+	ProcessingStartedAt *time.Time                  `json:"processingStartedAt,omitempty"`
+	FailureCode         *currency.RateFailureCode   `json:"failureCode,omitempty"`
+	FailureMessage      *string                     `json:"failureMessage,omitempty"`
+	CreatedAt           time.Time                   `json:"createdAt"`
+	UpdatedAt           time.Time                   `json:"updatedAt"`
+	CompletedAt         *time.Time                  `json:"completedAt,omitempty"`
+}
+
+func ToRateStatusResponse(rate currency.CurrencyRate) *RateStatusResponse {
+	res := &RateStatusResponse{
+		Id:        rate.Id,
+		Status:    rate.Status,
+		Final:     rate.Status.IsFinal(),
+		Attempts:  rate.Attempts,
+		CreatedAt: rate.CreatedAt.UTC(),
+		UpdatedAt: rate.UpdatedAt.UTC(),
+	}
+
+	if rate.NextAttemptAt != nil {
+		nextAttemptAt := rate.NextAttemptAt.UTC()
+		res.NextAttemptAt = &nextAttemptAt
+	}
+
+	if rate.ProcessingStartedAt != nil {
+		processingStartedAt := rate.ProcessingStartedAt.UTC()
+		res.ProcessingStartedAt = &processingStartedAt
+	}
+
+	if rate.Failure != nil {
+		res.FailureCode = &rate.Failure.Code
+		res.FailureMessage = &rate.Failure.Message
+	}
+
+	if rate.CompletedAt != nil {
+		completedAt := rate.CompletedAt.UTC()
+		res.CompletedAt = &completedAt
+	}
+
+	return res
+}
+
+type CurrencyCodeResponse struct {
+	Code        currency.CurrencyCode `json:"code"`
+	Name        string                `json:"name"`
+	NumericCode string                `json:"numericCode"`
+	MinorUnits  *int                  `json:"minorUnits"`
+}
+
+type GetCurrencyCodesResponse struct {
+	Items []CurrencyCodeResponse `json:"items"`
+}
+
+func ToGetCurrencyCodesResponse(items []currency.CurrencyInfo) *GetCurrencyCodesResponse {
+	res := make([]CurrencyCodeResponse, 0, len(items))
+
+	for _, item := range items {
+		var minorUnits *int
+
+		if item.MinorUnits != currency.MinorUnitsNotApplicable {
+			minorUnits = &item.MinorUnits
+		}
+
+		res = append(res, CurrencyCodeResponse{
+			Code:        item.Code,
+			Name:        item.Name,
+			NumericCode: item.NumericCode,
+			MinorUnits:  minorUnits,
+		})
+	}
+
+	return &GetCurrencyCodesResponse{Items: res}
+}
@@ -0,0 +1,234 @@
+package deadletter
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"currency-rate-app/internal/application"
+	"currency-rate-app/internal/common/auth"
+	error_utils "currency-rate-app/internal/common/error-utils"
+	http_server "currency-rate-app/internal/common/http-server"
+	"currency-rate-app/internal/common/validation"
+	"currency-rate-app/internal/domains/currency"
+)
+
+type DeadLetterController struct {
+	service application.RateDeadLetterService
+}
+
+func NewDeadLetterController(
+	mux *http.ServeMux,
+	service application.RateDeadLetterService,
+) *DeadLetterController {
+	controller := &DeadLetterController{service: service}
+
+	mux.HandleFunc("GET /v1/admin/rates/failed", func(w http.ResponseWriter, r *http.Request) {
+		controller.listFailedRatesHandler(w, r)
+	})
+	mux.HandleFunc("POST /v1/admin/rates/requeue", func(w http.ResponseWriter, r *http.Request) {
+		controller.requeueFailedRatesHandler(w, r)
+	})
+	mux.HandleFunc("POST /v1/admin/rates/{id}/requeue", func(w http.ResponseWriter, r *http.Request) {
+		controller.requeueRateHandler(w, r)
+	})
+	mux.HandleFunc("POST /v1/admin/rates/{id}/archive", func(w http.ResponseWriter, r *http.Request) {
+		controller.archiveRateHandler(w, r)
+	})
+	mux.HandleFunc("GET /v1/admin/rates/{id}/actions", func(w http.ResponseWriter, r *http.Request) {
+		controller.getRateAdminActionsHandler(w, r)
+	})
+
+	return controller
+}
+
+// @Summary      List failed rates
+// @Description  Dead-letter list: FAILED rate requests that aren't archived, with the failure reason, latest failures first. Paginated with `nextCursor`
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer admin token"
+// @Param        X-Admin-Actor header string true "Who makes the request"
+// @Success 200  {object} ListFailedRatesResponse
+// @Param        baseCurrency   query      string  false  "Base currency"
+// @Param        resultCurrency   query      string  false  "Result currency"
+// @Param        failedFrom   query      string  false  "Failed at or after, RFC 3339"
+// @Param        failedTo   query      string  false  "Failed before, RFC 3339"
+// @Param        limit   query      int  false  "Page size, 50 by default, up to 200"
+// @Param        cursor   query      string  false  "nextCursor of the previous page"
+// @Router       /v1/admin/rates/failed [get]
+func (c *DeadLetterController) listFailedRatesHandler(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	filter := currency.FailedRateFilter{
+		BaseCurrency:   currency.CurrencyCode(values.Get("baseCurrency")),
+		ResultCurrency: currency.CurrencyCode(values.Get("resultCurrency")),
+	}
+
+	for name, target := range map[string]**time.Time{
+		"failedFrom": &filter.FailedFrom,
+		"failedTo":   &filter.FailedTo,
+	} {
+		value := values.Get(name)
+
+		if value == "" {
+			continue
+		}
+
+		parsed, err := time.Parse(time.RFC3339, value)
+
+		if err != nil {
+			http_server.SendErrorResponse(w, currency.ErrInvalidFailedRateFilter(name+" should be an RFC 3339 time"))
+
+			return
+		}
+
+		*target = &parsed
+	}
+
+	var limit int
+
+	if value := values.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+
+		if err != nil || parsed <= 0 {
+			http_server.SendErrorResponse(w, currency.ErrInvalidFailedRateFilter("limit should be a positive number"))
+
+			return
+		}
+
+		limit = parsed
+	}
+
+	var cursor *currency.RateCursor
+
+	if value := values.Get("cursor"); value != "" {
+		parsed, err := currency.ParseRateCursor(value)
+
+		if err != nil {
+			http_server.SendErrorResponse(w, err)
+
+			return
+		}
+
+		cursor = parsed
+	}
+
+	page, err := c.service.ListFailedRates(r.Context(), filter, cursor, limit)
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	http_server.SendSuccessResponse(w, ToListFailedRatesResponse(page))
+}
+
+// @Summary      Requeue failed rates
+// @Description  Return FAILED rates matching the filter to PENDING with a fresh attempts budget, oldest failures first. Every requeue is recorded with the actor
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer admin token"
+// @Success 200  {object} RequeueFailedRatesResponse
+// @Param        X-Admin-Actor header string true "Who makes the change"
+// @Param        request   body      RequeueFailedRatesRequest  true  "Body"
+// @Router       /v1/admin/rates/requeue [post]
+func (c *DeadLetterController) requeueFailedRatesHandler(w http.ResponseWriter, r *http.Request) {
+	var dto RequeueFailedRatesRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		http_server.SendErrorResponse(w, error_utils.ErrValidationError(err.Error()))
+
+		return
+	}
+
+	if err := validation.GetValidator().Struct(&dto); err != nil {
+		http_server.SendErrorResponse(w, error_utils.ErrValidationError(err.Error()))
+
+		return
+	}
+
+	actor, _ := auth.ActorFromContext(r.Context())
+
+	rates, err := c.service.RequeueFailedRates(r.Context(), dto.Filter(), dto.Limit, actor)
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	http_server.SendSuccessResponse(w, ToRequeueFailedRatesResponse(rates))
+}
+
+// @Summary      Requeue failed rate
+// @Description  Return a FAILED rate to PENDING with a fresh attempts budget. Archived rates can't be requeued
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer admin token"
+// @Success 200  {object} DeadLetterRateResponse
+// @Param        id        path      string  true  "Id"
+// @Param        X-Admin-Actor header string true "Who makes the change"
+// @Router       /v1/admin/rates/{id}/requeue [post]
+func (c *DeadLetterController) requeueRateHandler(w http.ResponseWriter, r *http.Request) {
+	actor, _ := auth.ActorFromContext(r.Context())
+
+	rate, err := c.service.RequeueRate(r.Context(), r.PathValue("id"), actor)
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	http_server.SendSuccessResponse(w, ToDeadLetterRateResponse(*rate))
+}
+
+// @Summary      Archive failed rate
+// @Description  Take a FAILED rate off the dead-letter list for good, it stays FAILED
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer admin token"
+// @Success 200  {object} DeadLetterRateResponse
+// @Param        id        path      string  true  "Id"
+// @Param        X-Admin-Actor header string true "Who makes the change"
+// @Router       /v1/admin/rates/{id}/archive [post]
+func (c *DeadLetterController) archiveRateHandler(w http.ResponseWriter, r *http.Request) {
+	actor, _ := auth.ActorFromContext(r.Context())
+
+	rate, err := c.service.ArchiveRate(r.Context(), r.PathValue("id"), actor)
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	http_server.SendSuccessResponse(w, ToDeadLetterRateResponse(*rate))
+}
+
+// @Summary      Get admin actions of a rate
+// @Description  Requeues and archivation of a rate with their actors, newest first
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer admin token"
+// @Param        X-Admin-Actor header string true "Who makes the request"
+// @Success 200  {object} RateAdminActionsResponse
+// @Param        id        path      string  true  "Id"
+// @Router       /v1/admin/rates/{id}/actions [get]
+func (c *DeadLetterController) getRateAdminActionsHandler(w http.ResponseWriter, r *http.Request) {
+	actions, err := c.service.GetRateAdminActions(r.Context(), r.PathValue("id"))
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	http_server.SendSuccessResponse(w, ToRateAdminActionsResponse(actions))
+}
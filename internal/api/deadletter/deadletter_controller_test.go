@@ -0,0 +1,203 @@
+package deadletter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"currency-rate-app/internal/common/auth"
+	"currency-rate-app/internal/domains/currency"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockDeadLetterService struct {
+	listFn        func(ctx context.Context, filter currency.FailedRateFilter, cursor *currency.RateCursor, limit int) (*currency.RatePage, error)
+	requeueFn     func(ctx context.Context, id string, actor string) (*currency.CurrencyRate, error)
+	requeueManyFn func(ctx context.Context, filter currency.FailedRateFilter, limit int, actor string) ([]currency.CurrencyRate, error)
+	archiveFn     func(ctx context.Context, id string, actor string) (*currency.CurrencyRate, error)
+	actionsFn     func(ctx context.Context, id string) ([]currency.RateAdminAction, error)
+}
+
+func (m *mockDeadLetterService) ListFailedRates(ctx context.Context, filter currency.FailedRateFilter, cursor *currency.RateCursor, limit int) (*currency.RatePage, error) {
+	return m.listFn(ctx, filter, cursor, limit)
+}
+
+func (m *mockDeadLetterService) RequeueRate(ctx context.Context, id string, actor string) (*currency.CurrencyRate, error) {
+	return m.requeueFn(ctx, id, actor)
+}
+
+func (m *mockDeadLetterService) RequeueFailedRates(ctx context.Context, filter currency.FailedRateFilter, limit int, actor string) ([]currency.CurrencyRate, error) {
+	return m.requeueManyFn(ctx, filter, limit, actor)
+}
+
+func (m *mockDeadLetterService) ArchiveRate(ctx context.Context, id string, actor string) (*currency.CurrencyRate, error) {
+	return m.archiveFn(ctx, id, actor)
+}
+
+func (m *mockDeadLetterService) GetRateAdminActions(ctx context.Context, id string) ([]currency.RateAdminAction, error) {
+	return m.actionsFn(ctx, id)
+}
+
+func setupMux(service *mockDeadLetterService) *http.ServeMux {
+	mux := http.NewServeMux()
+	NewDeadLetterController(mux, service)
+	return mux
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestListFailedRates_Success(t *testing.T) {
+	var receivedFilter currency.FailedRateFilter
+	var receivedLimit int
+	service := &mockDeadLetterService{
+		listFn: func(ctx context.Context, filter currency.FailedRateFilter, cursor *currency.RateCursor, limit int) (*currency.RatePage, error) {
+			receivedFilter = filter
+			receivedLimit = limit
+			return &currency.RatePage{
+				Items: []currency.CurrencyRate{{
+					Id:             "rate-1",
+					BaseCurrency:   currency.USD,
+					ResultCurrency: currency.EUR,
+					Status:         currency.CurrencyRateStatusFailed,
+					Attempts:       5,
+					Failure:        &currency.RateFailure{Code: currency.RateFailurePairNotSupported, Message: "currency pair not found"},
+				}},
+				Next: &currency.RateCursor{SortField: currency.RateSortUpdatedAt, Desc: true, Id: "rate-1"},
+			}, nil
+		},
+	}
+	mux := setupMux(service)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/admin/rates/failed?baseCurrency=USD&failedFrom=2024-10-02T00:00:00Z&limit=10", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, currency.USD, receivedFilter.BaseCurrency)
+	assert.Equal(t, time.Date(2024, 10, 2, 0, 0, 0, 0, time.UTC), *receivedFilter.FailedFrom)
+	assert.Nil(t, receivedFilter.FailedTo)
+	assert.Equal(t, 10, receivedLimit)
+
+	var resDto ListFailedRatesResponse
+	if err := json.NewDecoder(res.Body).Decode(&resDto); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	assert.Equal(t, currency.RateFailurePairNotSupported, *resDto.Items[0].FailureCode)
+	assert.Equal(t, "currency pair not found", *resDto.Items[0].FailureMessage)
+	assert.Equal(t, 5, resDto.Items[0].Attempts)
+	assert.NotNil(t, resDto.NextCursor)
+}
+
+func TestListFailedRates_InvalidTime(t *testing.T) {
+	mux := setupMux(&mockDeadLetterService{})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/admin/rates/failed?failedTo=yesterday", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+}
+
+func TestRequeueFailedRates_Success(t *testing.T) {
+	var receivedFilter currency.FailedRateFilter
+	var receivedActor string
+	service := &mockDeadLetterService{
+		requeueManyFn: func(ctx context.Context, filter currency.FailedRateFilter, limit int, actor string) ([]currency.CurrencyRate, error) {
+			receivedFilter = filter
+			receivedActor = actor
+			return []currency.CurrencyRate{{Id: "rate-1"}, {Id: "rate-2"}}, nil
+		},
+	}
+	mux := setupMux(service)
+
+	body := new(bytes.Buffer)
+	json.NewEncoder(body).Encode(RequeueFailedRatesRequest{BaseCurrency: currency.USD, ResultCurrency: currency.MXN})
+	req := httptest.NewRequest(http.MethodPost, "/v1/admin/rates/requeue", body)
+	req = req.WithContext(auth.WithActor(req.Context(), "alice"))
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, currency.MXN, receivedFilter.ResultCurrency)
+	assert.Equal(t, "alice", receivedActor)
+
+	var resDto RequeueFailedRatesResponse
+	if err := json.NewDecoder(res.Body).Decode(&resDto); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	assert.Equal(t, 2, resDto.Requeued)
+	assert.Equal(t, []string{"rate-1", "rate-2"}, resDto.Ids)
+}
+
+func TestRequeueRate_NotFailed(t *testing.T) {
+	service := &mockDeadLetterService{
+		requeueFn: func(ctx context.Context, id string, actor string) (*currency.CurrencyRate, error) {
+			return nil, currency.ErrCurrencyRateNotFailed(currency.CurrencyRateStatusCompleted)
+		},
+	}
+	mux := setupMux(service)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/admin/rates/rate-1/requeue", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusConflict, res.Code)
+}
+
+func TestArchiveRate_Success(t *testing.T) {
+	archivedAt := time.Date(2024, 10, 2, 12, 0, 0, 0, time.UTC)
+	var receivedId string
+	service := &mockDeadLetterService{
+		archiveFn: func(ctx context.Context, id string, actor string) (*currency.CurrencyRate, error) {
+			receivedId = id
+			return &currency.CurrencyRate{Id: id, Status: currency.CurrencyRateStatusFailed, ArchivedAt: &archivedAt}, nil
+		},
+	}
+	mux := setupMux(service)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/admin/rates/rate-1/archive", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "rate-1", receivedId)
+
+	var resDto DeadLetterRateResponse
+	if err := json.NewDecoder(res.Body).Decode(&resDto); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	assert.Equal(t, archivedAt, *resDto.ArchivedAt)
+}
+
+func TestGetRateAdminActions_Success(t *testing.T) {
+	service := &mockDeadLetterService{
+		actionsFn: func(ctx context.Context, id string) ([]currency.RateAdminAction, error) {
+			return []currency.RateAdminAction{{Id: "action-1", RateId: id, Action: currency.RateAdminActionRequeue, Actor: "alice"}}, nil
+		},
+	}
+	mux := setupMux(service)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/admin/rates/rate-1/actions", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	var resDto RateAdminActionsResponse
+	if err := json.NewDecoder(res.Body).Decode(&resDto); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	assert.Equal(t, currency.RateAdminActionRequeue, resDto.Items[0].Action)
+	assert.Equal(t, "alice", resDto.Items[0].Actor)
+}
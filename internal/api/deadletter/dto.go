@@ -0,0 +1,133 @@
+package deadletter
+
+import (
+	"time"
+
+	"currency-rate-app/internal/domains/currency"
+)
+
+type DeadLetterRateResponse struct {
+	Id             string                      `json:"id"`
+	IdempotencyKey string                      `json:"idempotencyKey"`
+	BaseCurrency   currency.CurrencyCode       `json:"baseCurrency"`
+	ResultCurrency currency.CurrencyCode       `json:"resultCurrency"`
+	Date           *string                     `json:"date,omitempty" example:"2024-10-02"`
+	Status         currency.CurrencyRateStatus `json:"status"`
+	Attempts       int                         `json:"attempts"`
+	FailureCode    *currency.RateFailureCode   `json:"failureCode,omitempty"`
+	FailureMessage *string                     `json:"failureMessage,omitempty"`
+	CallbackUrl    *string                     `json:"callbackUrl,omitempty"`
+	ArchivedAt     *time.Time                  `json:"archivedAt,omitempty"`
+	CreatedAt      time.Time                   `json:"createdAt"`
+	UpdatedAt      time.Time                   `json:"updatedAt"`
+}
+
+type ListFailedRatesResponse struct {
+	Items []DeadLetterRateResponse `json:"items"`
+	// NextCursor is absent on the last page.
+	NextCursor *string `json:"nextCursor,omitempty"`
+}
+
+// RequeueFailedRatesRequest selects FAILED rates to requeue, empty fields
+// don't filter. The failure time is the last update of the rate.
+type RequeueFailedRatesRequest struct {
+	BaseCurrency   currency.CurrencyCode `json:"baseCurrency,omitempty" example:"USD"`
+	ResultCurrency currency.CurrencyCode `json:"resultCurrency,omitempty" example:"EUR"`
+	FailedFrom     *time.Time            `json:"failedFrom,omitempty"`
+	FailedTo       *time.Time            `json:"failedTo,omitempty"`
+	Limit          int                   `json:"limit,omitempty" validate:"min=0" example:"100"`
+}
+
+type RequeueFailedRatesResponse struct {
+	Requeued int      `json:"requeued"`
+	Ids      []string `json:"ids"`
+}
+
+type RateAdminActionResponse struct {
+	Id        string                       `json:"id"`
+	Action    currency.RateAdminActionType `json:"action"`
+	Actor     string                       `json:"actor"`
+	CreatedAt time.Time                    `json:"createdAt"`
+}
+
+type RateAdminActionsResponse struct {
+	Items []RateAdminActionResponse `json:"items"`
+}
+
+func (r RequeueFailedRatesRequest) Filter() currency.FailedRateFilter {
+	return currency.FailedRateFilter{
+		BaseCurrency:   r.BaseCurrency,
+		ResultCurrency: r.ResultCurrency,
+		FailedFrom:     r.FailedFrom,
+		FailedTo:       r.FailedTo,
+	}
+}
+
+func ToDeadLetterRateResponse(rate currency.CurrencyRate) *DeadLetterRateResponse {
+	res := &DeadLetterRateResponse{
+		Id:             rate.Id,
+		IdempotencyKey: rate.IdempotencyKey,
+		BaseCurrency:   rate.BaseCurrency,
+		ResultCurrency: rate.ResultCurrency,
+		Date:           currency.FormatRateDate(rate.RateDate),
+		Status:         rate.Status,
+		Attempts:       rate.Attempts,
+		CallbackUrl:    rate.CallbackUrl,
+		CreatedAt:      rate.CreatedAt.UTC(),
+		UpdatedAt:      rate.UpdatedAt.UTC(),
+	}
+
+	if rate.Failure != nil {
+		res.FailureCode = &rate.Failure.Code
+		res.FailureMessage = &rate.Failure.Message
+	}
+
+	if rate.ArchivedAt != nil {
+		archivedAt := rate.ArchivedAt.UTC()
+		res.ArchivedAt = &archivedAt
+	}
+
+	return res
+}
+
+func ToListFailedRatesResponse(page *currency.RatePage) *ListFailedRatesResponse {
+	items := make([]DeadLetterRateResponse, 0, len(page.Items))
+
+	for _, rate := range page.Items {
+		items = append(items, *ToDeadLetterRateResponse(rate))
+	}
+
+	res := &ListFailedRatesResponse{Items: items}
+
+	if page.Next != nil {
+		next := page.Next.Encode()
+		res.NextCursor = &next
+	}
+
+	return res
+}
+
+func ToRequeueFailedRatesResponse(rates []currency.CurrencyRate) *RequeueFailedRatesResponse {
+	ids := make([]string, 0, len(rates))
+
+	for _, rate := range rates {
+		ids = append(ids, rate.Id)
+	}
+
+	return &RequeueFailedRatesResponse{Requeued: len(ids), Ids: ids}
+}
+
+func ToRateAdminActionsResponse(actions []currency.RateAdminAction) *RateAdminActionsResponse {
+	items := make([]RateAdminActionResponse, 0, len(actions))
+
+	for _, action := range actions {
+		items = append(items, RateAdminActionResponse{
+			Id:        action.Id,
+			Action:    action.Action,
+			Actor:     action.Actor,
+			CreatedAt: action.CreatedAt.UTC(),
+		})
+	}
+
+	return &RateAdminActionsResponse{Items: items}
+}
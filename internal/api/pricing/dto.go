@@ -0,0 +1,75 @@
+package pricing
+
+import (
+	"currency-rate-app/internal/domains/pricing"
+	"time"
+)
+
+type SetSpreadRequest struct {
+	BidBps *int `json:"bidBps" validate:"required,min=0,max=9999" example:"25"`
+	AskBps *int `json:"askBps" validate:"required,min=0,max=10000" example:"25"`
+}
+
+type SpreadResponse struct {
+	Scope     string    `json:"scope" example:"EURUSD"`
+	BidBps    int       `json:"bidBps"`
+	AskBps    int       `json:"askBps"`
+	UpdatedBy string    `json:"updatedBy"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type ListSpreadsResponse struct {
+	Items []SpreadResponse `json:"items"`
+}
+
+type SpreadChangeResponse struct {
+	Id        string               `json:"id"`
+	Scope     string               `json:"scope"`
+	Action    pricing.SpreadAction `json:"action"`
+	BidBps    *int                 `json:"bidBps,omitempty"`
+	AskBps    *int                 `json:"askBps,omitempty"`
+	Actor     string               `json:"actor"`
+	CreatedAt time.Time            `json:"createdAt"`
+}
+
+type SpreadHistoryResponse struct {
+	Items []SpreadChangeResponse `json:"items"`
+}
+
+func ToSpreadResponse(spread pricing.Spread) *SpreadResponse {
+	return &SpreadResponse{
+		Scope:     spread.Scope,
+		BidBps:    spread.BidBps,
+		AskBps:    spread.AskBps,
+		UpdatedBy: spread.UpdatedBy,
+		UpdatedAt: spread.UpdatedAt.UTC(),
+	}
+}
+
+func ToListSpreadsResponse(spreads []pricing.Spread) *ListSpreadsResponse {
+	items := make([]SpreadResponse, 0, len(spreads))
+
+	for _, spread := range spreads {
+		items = append(items, *ToSpreadResponse(spread))
+	}
+
+	return &ListSpreadsResponse{Items: items}
+}
+
+func ToSpreadHistoryResponse(changes []pricing.SpreadChange) *SpreadHistoryResponse {
+	items := make([]SpreadChangeResponse, 0, len(changes))
+
+	for _, change := range changes {
+		items = append(items, SpreadChangeResponse{
+			Id:        change.Id,
+			Scope:     change.Scope,
+			Action:    change.Action,
+			BidBps:    change.BidBps,
+			AskBps:    change.AskBps,
+			Actor:     change.Actor,
+			CreatedAt: change.CreatedAt.UTC(),
+		})
+	}
+
+	return &SpreadHistoryResponse{Items: items}
+}
@@ -0,0 +1,142 @@
+package pricing
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"currency-rate-app/internal/application"
+	"currency-rate-app/internal/common/auth"
+	error_utils "currency-rate-app/internal/common/error-utils"
+	http_server "currency-rate-app/internal/common/http-server"
+	"currency-rate-app/internal/common/validation"
+)
+
+type SpreadController struct {
+	service application.PricingService
+}
+
+func NewSpreadController(
+	mux *http.ServeMux,
+	service application.PricingService,
+) *SpreadController {
+	controller := &SpreadController{service: service}
+
+	mux.HandleFunc("GET /v1/admin/spreads", func(w http.ResponseWriter, r *http.Request) {
+		controller.listSpreadsHandler(w, r)
+	})
+	mux.HandleFunc("PUT /v1/admin/spreads/{scope}", func(w http.ResponseWriter, r *http.Request) {
+		controller.setSpreadHandler(w, r)
+	})
+	mux.HandleFunc("DELETE /v1/admin/spreads/{scope}", func(w http.ResponseWriter, r *http.Request) {
+		controller.deleteSpreadHandler(w, r)
+	})
+	mux.HandleFunc("GET /v1/admin/spreads/{scope}/history", func(w http.ResponseWriter, r *http.Request) {
+		controller.getSpreadHistoryHandler(w, r)
+	})
+
+	return controller
+}
+
+// @Summary      List spreads
+// @Description  List configured bid/ask spreads
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer admin token"
+// @Param        X-Admin-Actor header string true "Who makes the request"
+// @Success 200  {object} ListSpreadsResponse
+// @Router       /v1/admin/spreads [get]
+func (c *SpreadController) listSpreadsHandler(w http.ResponseWriter, r *http.Request) {
+	spreads, err := c.service.ListSpreads(r.Context())
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	http_server.SendSuccessResponse(w, ToListSpreadsResponse(spreads))
+}
+
+// @Summary      Set spread
+// @Description  Create or replace the spread for a pair (EURUSD) or a currency (MXN)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer admin token"
+// @Success 200  {object} SpreadResponse
+// @Param        scope        path      string  true  "Pair or currency code"
+// @Param        X-Admin-Actor header string true "Who makes the change"
+// @Param        request   body      SetSpreadRequest  true  "Body"
+// @Router       /v1/admin/spreads/{scope} [put]
+func (c *SpreadController) setSpreadHandler(w http.ResponseWriter, r *http.Request) {
+	var dto SetSpreadRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		http_server.SendErrorResponse(w, error_utils.ErrValidationError(err.Error()))
+
+		return
+	}
+
+	if err := validation.GetValidator().Struct(&dto); err != nil {
+		http_server.SendErrorResponse(w, error_utils.ErrValidationError(err.Error()))
+
+		return
+	}
+
+	actor, _ := auth.ActorFromContext(r.Context())
+
+	spread, err := c.service.SetSpread(r.Context(), r.PathValue("scope"), *dto.BidBps, *dto.AskBps, actor)
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	http_server.SendSuccessResponse(w, ToSpreadResponse(*spread))
+}
+
+// @Summary      Delete spread
+// @Description  Delete the spread for a pair or a currency, the pair falls back to broader rules
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer admin token"
+// @Success 204
+// @Param        scope        path      string  true  "Pair or currency code"
+// @Param        X-Admin-Actor header string true "Who makes the change"
+// @Router       /v1/admin/spreads/{scope} [delete]
+func (c *SpreadController) deleteSpreadHandler(w http.ResponseWriter, r *http.Request) {
+	actor, _ := auth.ActorFromContext(r.Context())
+
+	if err := c.service.DeleteSpread(r.Context(), r.PathValue("scope"), actor); err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary      Get spread history
+// @Description  Get all changes of the spread for a pair or a currency, newest first
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer admin token"
+// @Param        X-Admin-Actor header string true "Who makes the request"
+// @Success 200  {object} SpreadHistoryResponse
+// @Param        scope        path      string  true  "Pair or currency code"
+// @Router       /v1/admin/spreads/{scope}/history [get]
+func (c *SpreadController) getSpreadHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	changes, err := c.service.GetSpreadHistory(r.Context(), r.PathValue("scope"))
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	http_server.SendSuccessResponse(w, ToSpreadHistoryResponse(changes))
+}
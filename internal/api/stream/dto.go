@@ -0,0 +1,30 @@
+package stream
+
+import (
+	"currency-rate-app/internal/domains/currency"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// RateEventResponse is the data of a "rate" event. Rate is the mid-market
+// rate, encoded as a string like everywhere else.
+type RateEventResponse struct {
+	BaseCurrency   currency.CurrencyCode  `json:"baseCurrency"`
+	ResultCurrency currency.CurrencyCode  `json:"resultCurrency"`
+	Date           *string                `json:"date,omitempty" example:"2024-10-02"`
+	Rate           decimal.Decimal        `json:"rate" swaggertype:"string" example:"1.0845"`
+	PivotCurrency  *currency.CurrencyCode `json:"pivotCurrency,omitempty"`
+	CompletedAt    time.Time              `json:"completedAt"`
+}
+
+func ToRateEventResponse(event currency.RateEvent) *RateEventResponse {
+	return &RateEventResponse{
+		BaseCurrency:   event.BaseCurrency,
+		ResultCurrency: event.ResultCurrency,
+		Date:           currency.FormatRateDate(event.RateDate),
+		Rate:           event.Rate,
+		PivotCurrency:  event.PivotCurrency,
+		CompletedAt:    event.CreatedAt.UTC(),
+	}
+}
@@ -0,0 +1,214 @@
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"currency-rate-app/internal/application"
+	error_utils "currency-rate-app/internal/common/error-utils"
+	http_server "currency-rate-app/internal/common/http-server"
+	"currency-rate-app/internal/domains/currency"
+)
+
+const (
+	maxStreamPairs  = 20
+	historyPageSize = 500
+	// heartbeatInterval keeps idle streams alive through proxies.
+	heartbeatInterval = 15 * time.Second
+)
+
+type StreamController struct {
+	service application.RateStreamService
+}
+
+func NewStreamController(
+	mux *http.ServeMux,
+	service application.RateStreamService,
+) *StreamController {
+	controller := &StreamController{service: service}
+
+	mux.HandleFunc("GET /v1/currencies/stream", func(w http.ResponseWriter, r *http.Request) {
+		controller.streamRatesHandler(w, r)
+	})
+
+	return controller
+}
+
+// @Summary      Stream rate updates
+// @Description  Server-Sent Events: a "rate" event every time a rate of one of the pairs is completed. Event ids grow, reconnecting with Last-Event-ID replays the events missed meanwhile
+// @Tags         currency
+// @Produce      text/event-stream
+// @Success 200  {object} RateEventResponse "data of every rate event"
+// @Param        pairs   query      string  true  "Comma separated pairs, e.g. EURUSD,USDMXN"
+// @Param        Last-Event-ID header int false "Id of the last received event, to resume the stream"
+// @Router       /v1/currencies/stream [get]
+func (c *StreamController) streamRatesHandler(w http.ResponseWriter, r *http.Request) {
+	pairs, err := parsePairs(r.URL.Query().Get("pairs"))
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	lastEventId, err := parseLastEventId(r.Header.Get("Last-Event-ID"))
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	// Subscribe before reading history, so nothing completed in between is lost.
+	events, unsubscribe := c.service.Subscribe(pairs)
+	defer unsubscribe()
+
+	// The first page is read before the stream starts, so a failure is still
+	// answered with a proper error.
+	var history []currency.RateEvent
+
+	if lastEventId != nil {
+		history, err = c.service.GetRateEventsAfter(r.Context(), *lastEventId, pairs, historyPageSize)
+
+		if err != nil {
+			http_server.SendErrorResponse(w, err)
+
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(w)
+	replayed := make(map[int64]struct{}, len(history))
+
+	if err := c.replayHistory(w, r, history, pairs, replayed); err != nil {
+		return
+	}
+
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			if _, ok := replayed[event.Id]; ok {
+				continue
+			}
+
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// replayHistory writes the stored events after Last-Event-ID page by page.
+// Their ids are kept in replayed, live events that were already sent are
+// skipped by id.
+func (c *StreamController) replayHistory(
+	w http.ResponseWriter,
+	r *http.Request,
+	page []currency.RateEvent,
+	pairs []currency.CurrencyPair,
+	replayed map[int64]struct{},
+) error {
+	for {
+		for _, event := range page {
+			if err := writeEvent(w, event); err != nil {
+				return err
+			}
+
+			replayed[event.Id] = struct{}{}
+		}
+
+		if len(page) < historyPageSize {
+			return nil
+		}
+
+		var err error
+
+		page, err = c.service.GetRateEventsAfter(r.Context(), page[len(page)-1].Id, pairs, historyPageSize)
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event currency.RateEvent) error {
+	data, err := json.Marshal(ToRateEventResponse(event))
+
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: rate\ndata: %s\n\n", event.Id, data)
+
+	return err
+}
+
+func parsePairs(value string) ([]currency.CurrencyPair, error) {
+	if value == "" {
+		return nil, error_utils.ErrValidationError("pairs is required")
+	}
+
+	items := strings.Split(value, ",")
+
+	if len(items) > maxStreamPairs {
+		return nil, error_utils.ErrValidationError(fmt.Sprintf("at most %d pairs can be streamed", maxStreamPairs))
+	}
+
+	pairs := make([]currency.CurrencyPair, 0, len(items))
+
+	for _, item := range items {
+		pair, err := currency.ParseCurrencyPair(strings.TrimSpace(item))
+
+		if err != nil {
+			return nil, err
+		}
+
+		pairs = append(pairs, pair)
+	}
+
+	return pairs, nil
+}
+
+func parseLastEventId(value string) (*int64, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	id, err := strconv.ParseInt(value, 10, 64)
+
+	if err != nil || id < 0 {
+		return nil, currency.ErrInvalidLastEventId()
+	}
+
+	return &id, nil
+}
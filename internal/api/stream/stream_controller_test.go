@@ -0,0 +1,129 @@
+package stream
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"currency-rate-app/internal/domains/currency"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockStreamService struct {
+	live      []currency.RateEvent
+	history   []currency.RateEvent
+	pairs     []currency.CurrencyPair
+	historyAt []int64
+}
+
+func (m *mockStreamService) Subscribe(pairs []currency.CurrencyPair) (<-chan currency.RateEvent, func()) {
+	m.pairs = pairs
+	events := make(chan currency.RateEvent, len(m.live))
+	for _, event := range m.live {
+		events <- event
+	}
+	close(events)
+	return events, func() {}
+}
+
+func (m *mockStreamService) GetRateEventsAfter(ctx context.Context, lastEventId int64, pairs []currency.CurrencyPair, limit int) ([]currency.RateEvent, error) {
+	m.historyAt = append(m.historyAt, lastEventId)
+	var res []currency.RateEvent
+	for _, event := range m.history {
+		if event.Id > lastEventId {
+			res = append(res, event)
+		}
+	}
+	return res, nil
+}
+
+func (m *mockStreamService) Publish(event currency.RateEvent) {}
+
+func (m *mockStreamService) Close() {}
+
+func rateEvent(id int64, rate string) currency.RateEvent {
+	return currency.RateEvent{
+		Id:             id,
+		BaseCurrency:   currency.EUR,
+		ResultCurrency: currency.USD,
+		Rate:           decimal.RequireFromString(rate),
+		CreatedAt:      time.Date(2024, 10, 2, 15, 4, 5, 0, time.UTC),
+	}
+}
+
+func setupMux(service *mockStreamService) *http.ServeMux {
+	mux := http.NewServeMux()
+	NewStreamController(mux, service)
+	return mux
+}
+
+func TestStreamRates_Live(t *testing.T) {
+	service := &mockStreamService{live: []currency.RateEvent{rateEvent(7, "1.0845")}}
+	mux := setupMux(service)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/currencies/stream?pairs=EURUSD,USDMXN", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "text/event-stream", res.Header().Get("Content-Type"))
+	assert.Equal(t, []currency.CurrencyPair{
+		{BaseCurrency: currency.EUR, ResultCurrency: currency.USD},
+		{BaseCurrency: currency.USD, ResultCurrency: currency.MXN},
+	}, service.pairs)
+	assert.Equal(t, "id: 7\nevent: rate\ndata: {\"baseCurrency\":\"EUR\",\"resultCurrency\":\"USD\",\"rate\":\"1.0845\",\"completedAt\":\"2024-10-02T15:04:05Z\"}\n\n", res.Body.String())
+	assert.Empty(t, service.historyAt, "history is read only on resume")
+}
+
+func TestStreamRates_ResumeFromLastEventId(t *testing.T) {
+	service := &mockStreamService{
+		history: []currency.RateEvent{rateEvent(3, "1.08"), rateEvent(5, "1.09")},
+		// 5 is both in history and live, it must be sent once.
+		live: []currency.RateEvent{rateEvent(5, "1.09"), rateEvent(6, "1.1")},
+	}
+	mux := setupMux(service)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/currencies/stream?pairs=EURUSD", nil)
+	req.Header.Set("Last-Event-ID", "2")
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, []int64{2}, service.historyAt)
+
+	body := res.Body.String()
+	assert.Equal(t, 3, strings.Count(body, "event: rate"))
+	assert.Less(t, strings.Index(body, "id: 3\n"), strings.Index(body, "id: 5\n"))
+	assert.Less(t, strings.Index(body, "id: 5\n"), strings.Index(body, "id: 6\n"))
+}
+
+func TestStreamRates_ValidationErrors(t *testing.T) {
+	cases := map[string]string{
+		"/v1/currencies/stream":               "",
+		"/v1/currencies/stream?pairs=EURUS":   "",
+		"/v1/currencies/stream?pairs=EUREUR":  "",
+		"/v1/currencies/stream?pairs=EURUSD":  "abc",
+		"/v1/currencies/stream?pairs=EURUSD,": "",
+	}
+
+	for url, lastEventId := range cases {
+		mux := setupMux(&mockStreamService{})
+
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		if lastEventId != "" {
+			req.Header.Set("Last-Event-ID", lastEventId)
+		}
+		res := httptest.NewRecorder()
+
+		mux.ServeHTTP(res, req)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code, url)
+	}
+}
@@ -0,0 +1,56 @@
+package subscription
+
+import (
+	"currency-rate-app/internal/domains/currency"
+	"currency-rate-app/internal/domains/subscription"
+	"time"
+)
+
+type CreateSubscriptionRequest struct {
+	BaseCurrency    currency.CurrencyCode `json:"baseCurrency" validate:"required,len=3"`
+	ResultCurrency  currency.CurrencyCode `json:"resultCurrency" validate:"required,len=3"`
+	IntervalSeconds int                   `json:"intervalSeconds" validate:"required" example:"300"`
+}
+
+type SubscriptionResponse struct {
+	Id              string                `json:"id"`
+	BaseCurrency    currency.CurrencyCode `json:"baseCurrency"`
+	ResultCurrency  currency.CurrencyCode `json:"resultCurrency"`
+	IntervalSeconds int64                 `json:"intervalSeconds"`
+	NextRunAt       time.Time             `json:"nextRunAt"`
+	LastEnqueuedAt  *time.Time            `json:"lastEnqueuedAt,omitempty"`
+	CreatedAt       time.Time             `json:"createdAt"`
+}
+
+type ListSubscriptionsResponse struct {
+	Items []SubscriptionResponse `json:"items"`
+}
+
+func ToSubscriptionResponse(sub subscription.Subscription) *SubscriptionResponse {
+	var lastEnqueuedAt *time.Time
+
+	if sub.LastEnqueuedAt != nil {
+		t := sub.LastEnqueuedAt.UTC()
+		lastEnqueuedAt = &t
+	}
+
+	return &SubscriptionResponse{
+		Id:              sub.Id,
+		BaseCurrency:    sub.BaseCurrency,
+		ResultCurrency:  sub.ResultCurrency,
+		IntervalSeconds: int64(sub.Interval.Seconds()),
+		NextRunAt:       sub.NextRunAt.UTC(),
+		LastEnqueuedAt:  lastEnqueuedAt,
+		CreatedAt:       sub.CreatedAt.UTC(),
+	}
+}
+
+func ToListSubscriptionsResponse(subs []subscription.Subscription) *ListSubscriptionsResponse {
+	items := make([]SubscriptionResponse, 0, len(subs))
+
+	for _, sub := range subs {
+		items = append(items, *ToSubscriptionResponse(sub))
+	}
+
+	return &ListSubscriptionsResponse{Items: items}
+}
@@ -0,0 +1,131 @@
+package subscription
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"currency-rate-app/internal/application"
+	error_utils "currency-rate-app/internal/common/error-utils"
+	http_server "currency-rate-app/internal/common/http-server"
+	"currency-rate-app/internal/common/validation"
+)
+
+type SubscriptionController struct {
+	service application.SubscriptionService
+}
+
+func NewSubscriptionController(
+	mux *http.ServeMux,
+	service application.SubscriptionService,
+) *SubscriptionController {
+	controller := &SubscriptionController{service: service}
+
+	mux.HandleFunc("POST /v1/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		controller.createSubscriptionHandler(w, r)
+	})
+	mux.HandleFunc("GET /v1/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		controller.listSubscriptionsHandler(w, r)
+	})
+	mux.HandleFunc("GET /v1/subscriptions/{id}", func(w http.ResponseWriter, r *http.Request) {
+		controller.getSubscriptionHandler(w, r)
+	})
+	mux.HandleFunc("DELETE /v1/subscriptions/{id}", func(w http.ResponseWriter, r *http.Request) {
+		controller.deleteSubscriptionHandler(w, r)
+	})
+
+	return controller
+}
+
+// @Summary      Create subscription
+// @Description  Subscribe to a pair, its latest rate is refreshed every intervalSeconds (60..604800)
+// @Tags         subscription
+// @Accept       json
+// @Produce      json
+// @Success 200  {object} SubscriptionResponse
+// @Param        request   body      CreateSubscriptionRequest  true  "Body"
+// @Router       /v1/subscriptions [post]
+func (c *SubscriptionController) createSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	var dto CreateSubscriptionRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		http_server.SendErrorResponse(w, error_utils.ErrValidationError(err.Error()))
+
+		return
+	}
+
+	if err := validation.GetValidator().Struct(&dto); err != nil {
+		http_server.SendErrorResponse(w, error_utils.ErrValidationError(err.Error()))
+
+		return
+	}
+
+	interval := time.Duration(dto.IntervalSeconds) * time.Second
+
+	sub, err := c.service.CreateSubscription(r.Context(), dto.BaseCurrency, dto.ResultCurrency, interval)
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	http_server.SendSuccessResponse(w, ToSubscriptionResponse(*sub))
+}
+
+// @Summary      List subscriptions
+// @Description  List all pair subscriptions
+// @Tags         subscription
+// @Accept       json
+// @Produce      json
+// @Success 200  {object} ListSubscriptionsResponse
+// @Router       /v1/subscriptions [get]
+func (c *SubscriptionController) listSubscriptionsHandler(w http.ResponseWriter, r *http.Request) {
+	subs, err := c.service.ListSubscriptions(r.Context())
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	http_server.SendSuccessResponse(w, ToListSubscriptionsResponse(subs))
+}
+
+// @Summary      Get subscription
+// @Description  Get subscription by id
+// @Tags         subscription
+// @Accept       json
+// @Produce      json
+// @Success 200  {object} SubscriptionResponse
+// @Param        id        path      string  true  "Id"
+// @Router       /v1/subscriptions/{id} [get]
+func (c *SubscriptionController) getSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	sub, err := c.service.GetSubscriptionById(r.Context(), r.PathValue("id"))
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	http_server.SendSuccessResponse(w, ToSubscriptionResponse(*sub))
+}
+
+// @Summary      Delete subscription
+// @Description  Delete subscription, rate requests already enqueued are still processed
+// @Tags         subscription
+// @Accept       json
+// @Produce      json
+// @Success 204
+// @Param        id        path      string  true  "Id"
+// @Router       /v1/subscriptions/{id} [delete]
+func (c *SubscriptionController) deleteSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	if err := c.service.DeleteSubscription(r.Context(), r.PathValue("id")); err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
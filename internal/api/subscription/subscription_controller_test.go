@@ -0,0 +1,121 @@
+package subscription
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"currency-rate-app/internal/application"
+	"currency-rate-app/internal/domains/currency"
+	"currency-rate-app/internal/domains/subscription"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockService struct {
+	application.SubscriptionService
+	createFn func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, interval time.Duration) (*subscription.Subscription, error)
+	listFn   func(ctx context.Context) ([]subscription.Subscription, error)
+	deleteFn func(ctx context.Context, id string) error
+}
+
+func (m *mockService) CreateSubscription(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, interval time.Duration) (*subscription.Subscription, error) {
+	return m.createFn(ctx, base, result, interval)
+}
+func (m *mockService) ListSubscriptions(ctx context.Context) ([]subscription.Subscription, error) {
+	return m.listFn(ctx)
+}
+func (m *mockService) DeleteSubscription(ctx context.Context, id string) error {
+	return m.deleteFn(ctx, id)
+}
+
+func setupMux(service application.SubscriptionService) *http.ServeMux {
+	mux := http.NewServeMux()
+	NewSubscriptionController(mux, service)
+	return mux
+}
+
+func TestCreateSubscription_Success(t *testing.T) {
+	var gotInterval time.Duration
+	service := &mockService{
+		createFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, interval time.Duration) (*subscription.Subscription, error) {
+			gotInterval = interval
+			return &subscription.Subscription{Id: "sub-1", BaseCurrency: base, ResultCurrency: result, Interval: interval}, nil
+		},
+	}
+	mux := setupMux(service)
+
+	body, _ := json.Marshal(CreateSubscriptionRequest{BaseCurrency: currency.EUR, ResultCurrency: currency.USD, IntervalSeconds: 300})
+	req := httptest.NewRequest(http.MethodPost, "/v1/subscriptions", bytes.NewReader(body))
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, 5*time.Minute, gotInterval)
+
+	var resp SubscriptionResponse
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	assert.Equal(t, "sub-1", resp.Id)
+	assert.EqualValues(t, 300, resp.IntervalSeconds)
+}
+
+func TestCreateSubscription_AlreadyExists(t *testing.T) {
+	service := &mockService{
+		createFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, interval time.Duration) (*subscription.Subscription, error) {
+			return nil, subscription.ErrSubscriptionAlreadyExists()
+		},
+	}
+	mux := setupMux(service)
+
+	body, _ := json.Marshal(CreateSubscriptionRequest{BaseCurrency: currency.EUR, ResultCurrency: currency.USD, IntervalSeconds: 300})
+	req := httptest.NewRequest(http.MethodPost, "/v1/subscriptions", bytes.NewReader(body))
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+}
+
+func TestListSubscriptions_Success(t *testing.T) {
+	service := &mockService{
+		listFn: func(ctx context.Context) ([]subscription.Subscription, error) {
+			return []subscription.Subscription{{Id: "sub-1", Interval: time.Minute}}, nil
+		},
+	}
+	mux := setupMux(service)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/subscriptions", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	var resp ListSubscriptionsResponse
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	assert.Len(t, resp.Items, 1)
+}
+
+func TestDeleteSubscription_NotFound(t *testing.T) {
+	service := &mockService{
+		deleteFn: func(ctx context.Context, id string) error {
+			return subscription.ErrSubscriptionNotFound()
+		},
+	}
+	mux := setupMux(service)
+
+	req := httptest.NewRequest(http.MethodDelete, "/v1/subscriptions/sub-1", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusNotFound, res.Code)
+}
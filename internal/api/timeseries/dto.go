@@ -0,0 +1,41 @@
+package timeseries
+
+import (
+	"currency-rate-app/internal/domains/currency"
+
+	"github.com/shopspring/decimal"
+)
+
+type TimeSeriesPointResponse struct {
+	Date               string          `json:"date" example:"2024-10-02"`
+	Rate               decimal.Decimal `json:"rate" swaggertype:"string" example:"1.0845"`
+	FilledFromProvider bool            `json:"filledFromProvider"`
+}
+
+type GetTimeSeriesResponse struct {
+	BaseCurrency   currency.CurrencyCode     `json:"baseCurrency"`
+	ResultCurrency currency.CurrencyCode     `json:"resultCurrency"`
+	From           string                    `json:"from" example:"2024-10-01"`
+	To             string                    `json:"to" example:"2024-10-31"`
+	Points         []TimeSeriesPointResponse `json:"points"`
+}
+
+func ToGetTimeSeriesResponse(series currency.TimeSeries) *GetTimeSeriesResponse {
+	points := make([]TimeSeriesPointResponse, 0, len(series.Points))
+
+	for _, p := range series.Points {
+		points = append(points, TimeSeriesPointResponse{
+			Date:               p.Date.Format(currency.RateDateLayout),
+			Rate:               p.Rate,
+			FilledFromProvider: p.FromProvider,
+		})
+	}
+
+	return &GetTimeSeriesResponse{
+		BaseCurrency:   series.BaseCurrency,
+		ResultCurrency: series.ResultCurrency,
+		From:           series.From.Format(currency.RateDateLayout),
+		To:             series.To.Format(currency.RateDateLayout),
+		Points:         points,
+	}
+}
@@ -0,0 +1,80 @@
+package timeseries
+
+import (
+	"net/http"
+
+	"currency-rate-app/internal/application"
+	http_server "currency-rate-app/internal/common/http-server"
+	"currency-rate-app/internal/domains/currency"
+)
+
+type TimeSeriesController struct {
+	service application.TimeSeriesService
+}
+
+func NewTimeSeriesController(
+	mux *http.ServeMux,
+	service application.TimeSeriesService,
+) *TimeSeriesController {
+	controller := &TimeSeriesController{service: service}
+
+	mux.HandleFunc("GET /v1/currencies/timeseries", func(w http.ResponseWriter, r *http.Request) {
+		controller.getTimeSeriesHandler(w, r)
+	})
+
+	return controller
+}
+
+// @Summary      Get currency rate time series
+// @Description  Get daily rates of a currency pair for a date range, gaps are filled from the rates provider
+// @Tags         currency
+// @Accept       json
+// @Produce      json
+// @Success 200  {object} GetTimeSeriesResponse
+// @Param        baseCurrency   query      currency.CurrencyCode  true  "Currency Code"
+// @Param        resultCurrency   query      currency.CurrencyCode  true  "Currency Code"
+// @Param        from   query      string  true  "First business date (YYYY-MM-DD)"
+// @Param        to   query      string  true  "Last business date (YYYY-MM-DD)"
+// @Router       /v1/currencies/timeseries [get]
+func (c *TimeSeriesController) getTimeSeriesHandler(w http.ResponseWriter, r *http.Request) {
+	baseCurrency := currency.CurrencyCode(r.URL.Query().Get("baseCurrency"))
+	resultCurrency := currency.CurrencyCode(r.URL.Query().Get("resultCurrency"))
+
+	if err := currency.ValidateCurrencyPair(baseCurrency, resultCurrency); err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	from, err := currency.ParseRateDate(r.URL.Query().Get("from"))
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	to, err := currency.ParseRateDate(r.URL.Query().Get("to"))
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	if from == nil || to == nil {
+		http_server.SendErrorResponse(w, currency.ErrInvalidDateRange())
+
+		return
+	}
+
+	series, err := c.service.GetTimeSeries(r.Context(), baseCurrency, resultCurrency, *from, *to)
+
+	if err != nil {
+		http_server.SendErrorResponse(w, err)
+
+		return
+	}
+
+	http_server.SendSuccessResponse(w, ToGetTimeSeriesResponse(*series))
+}
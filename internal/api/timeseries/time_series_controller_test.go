@@ -0,0 +1,129 @@
+package timeseries
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"currency-rate-app/internal/domains/currency"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockService struct {
+	getFn func(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, from time.Time, to time.Time) (*currency.TimeSeries, error)
+}
+
+func (m *mockService) GetTimeSeries(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, from time.Time, to time.Time) (*currency.TimeSeries, error) {
+	return m.getFn(ctx, baseCurrency, resultCurrency, from, to)
+}
+
+func setupMux(service *mockService) *http.ServeMux {
+	mux := http.NewServeMux()
+	NewTimeSeriesController(mux, service)
+	return mux
+}
+
+func day(value string) time.Time {
+	d, _ := time.Parse(currency.RateDateLayout, value)
+	return d
+}
+
+func TestGetTimeSeries_Success(t *testing.T) {
+	var gotFrom, gotTo time.Time
+	service := &mockService{
+		getFn: func(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, from time.Time, to time.Time) (*currency.TimeSeries, error) {
+			gotFrom, gotTo = from, to
+			return &currency.TimeSeries{
+				BaseCurrency:   baseCurrency,
+				ResultCurrency: resultCurrency,
+				From:           from,
+				To:             to,
+				Points: []currency.TimeSeriesPoint{
+					{Date: day("2024-01-04"), Rate: decimal.RequireFromString("0.91")},
+					{Date: day("2024-01-05"), Rate: decimal.RequireFromString("0.915"), FromProvider: true, Provider: "Frankfurter"},
+				},
+			}, nil
+		},
+	}
+	mux := setupMux(service)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/currencies/timeseries?baseCurrency=USD&resultCurrency=EUR&from=2024-01-04&to=2024-01-07", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, day("2024-01-04"), gotFrom)
+	assert.Equal(t, day("2024-01-07"), gotTo)
+
+	var body GetTimeSeriesResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+
+	assert.Equal(t, currency.USD, body.BaseCurrency)
+	assert.Equal(t, currency.EUR, body.ResultCurrency)
+	assert.Equal(t, "2024-01-04", body.From)
+	assert.Equal(t, "2024-01-07", body.To)
+	assert.Len(t, body.Points, 2)
+	assert.Equal(t, "2024-01-04", body.Points[0].Date)
+	assert.Equal(t, "0.91", body.Points[0].Rate.String())
+	assert.False(t, body.Points[0].FilledFromProvider)
+	assert.Equal(t, "2024-01-05", body.Points[1].Date)
+	assert.True(t, body.Points[1].FilledFromProvider)
+}
+
+func TestGetTimeSeries_ValidationErrors(t *testing.T) {
+	service := &mockService{
+		getFn: func(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, from time.Time, to time.Time) (*currency.TimeSeries, error) {
+			t.Error("invalid requests shouldn't reach the service")
+			return nil, nil
+		},
+	}
+	mux := setupMux(service)
+
+	tests := []struct {
+		name  string
+		query string
+		code  string
+	}{
+		{"invalid_currency", "baseCurrency=AAA&resultCurrency=EUR&from=2024-01-01&to=2024-01-31", "InvalidCurrencyCode"},
+		{"invalid_from", "baseCurrency=USD&resultCurrency=EUR&from=01.01.2024&to=2024-01-31", "InvalidRateDate"},
+		{"future_to", "baseCurrency=USD&resultCurrency=EUR&from=2024-01-01&to=2999-01-01", "InvalidRateDate"},
+		{"missing_to", "baseCurrency=USD&resultCurrency=EUR&from=2024-01-01", "InvalidDateRange"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/currencies/timeseries?"+tt.query, nil)
+			res := httptest.NewRecorder()
+
+			mux.ServeHTTP(res, req)
+
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+			assert.Contains(t, res.Body.String(), tt.code)
+		})
+	}
+}
+
+func TestGetTimeSeries_ServiceError(t *testing.T) {
+	service := &mockService{
+		getFn: func(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, from time.Time, to time.Time) (*currency.TimeSeries, error) {
+			return nil, currency.ErrInvalidDateRange()
+		},
+	}
+	mux := setupMux(service)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/currencies/timeseries?baseCurrency=USD&resultCurrency=EUR&from=2024-02-01&to=2024-01-01", nil)
+	res := httptest.NewRecorder()
+
+	mux.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.Contains(t, res.Body.String(), "InvalidDateRange")
+}
@@ -0,0 +1,138 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"currency-rate-app/internal/common/utils"
+	"currency-rate-app/internal/domains/alert"
+	"currency-rate-app/internal/domains/currency"
+	"currency-rate-app/internal/infrastructure/db"
+	"currency-rate-app/internal/infrastructure/http/webhook"
+
+	"github.com/shopspring/decimal"
+)
+
+const alertFiredEvent = "alert.fired"
+
+// AlertDeliveryService sends the firings recorded by AlertService. Like
+// CallbackDeliveryService each run makes one attempt per due firing and the
+// retries follow the same policy, so rates processing never waits on a
+// receiver.
+type AlertDeliveryService struct {
+	repo   db.AlertRepository
+	sender webhook.Sender
+	config CallbackDeliveryConfig
+	now    func() time.Time
+}
+
+func NewAlertDeliveryService(repo db.AlertRepository, sender webhook.Sender, config CallbackDeliveryConfig) *AlertDeliveryService {
+	return &AlertDeliveryService{repo: repo, sender: sender, config: config, now: time.Now}
+}
+
+type alertFiredPayload struct {
+	Event          string                `json:"event"`
+	FiringId       string                `json:"firingId"`
+	RuleId         string                `json:"ruleId"`
+	BaseCurrency   currency.CurrencyCode `json:"baseCurrency"`
+	ResultCurrency currency.CurrencyCode `json:"resultCurrency"`
+	Condition      alert.Condition       `json:"condition"`
+	Threshold      decimal.Decimal       `json:"threshold"`
+	WindowSeconds  int64                 `json:"windowSeconds,omitempty"`
+	Rate           decimal.Decimal       `json:"rate"`
+	ReferenceRate  *decimal.Decimal      `json:"referenceRate,omitempty"`
+	FiredAt        time.Time             `json:"firedAt"`
+}
+
+func (s *AlertDeliveryService) DeliverDue(ctx context.Context, batchSize int) {
+	firings, err := s.repo.FetchDueFirings(ctx, s.now(), s.config.Lease, batchSize)
+
+	if err != nil {
+		slog.ErrorContext(ctx, "Alert firings fetch failed", slog.String("error", err.Error()))
+
+		return
+	}
+
+	var wg sync.WaitGroup
+
+	for _, firing := range firings {
+		f := firing
+
+		wg.Go(func() {
+			defer utils.HandleRecover()
+
+			s.deliver(ctx, f)
+		})
+	}
+
+	wg.Wait()
+}
+
+func (s *AlertDeliveryService) deliver(ctx context.Context, firing alert.AlertFiring) {
+	attempt := firing.Attempts + 1
+	sendErr := s.send(ctx, firing)
+	now := s.now()
+
+	status := alert.DeliveryStatusDelivered
+	nextAttemptAt := now
+
+	var lastError *string
+
+	if sendErr != nil {
+		msg := sendErr.Error()
+		lastError = &msg
+
+		status = alert.DeliveryStatusPending
+		nextAttemptAt = now.Add(utils.Backoff(attempt, s.config.InitialBackoff, s.config.MaxBackoff))
+
+		if attempt >= s.config.MaxAttempts {
+			status = alert.DeliveryStatusFailed
+		}
+
+		slog.WarnContext(
+			ctx,
+			"Alert delivery failed",
+			slog.String("ruleId", firing.RuleId),
+			slog.String("firingId", firing.Id),
+			slog.Int("attempt", attempt),
+			slog.String("error", msg),
+		)
+	}
+
+	if err := s.repo.UpdateFiringDelivery(ctx, firing.Id, status, attempt, lastError, nextAttemptAt); err != nil {
+		slog.ErrorContext(ctx, "Alert delivery save failed", slog.String("firingId", firing.Id), slog.String("error", err.Error()))
+	}
+}
+
+func (s *AlertDeliveryService) send(ctx context.Context, firing alert.AlertFiring) error {
+	rule, err := s.repo.GetAlertRuleById(ctx, firing.RuleId)
+
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(alertFiredPayload{
+		Event:          alertFiredEvent,
+		FiringId:       firing.Id,
+		RuleId:         rule.Id,
+		BaseCurrency:   rule.BaseCurrency,
+		ResultCurrency: rule.ResultCurrency,
+		Condition:      rule.Condition,
+		Threshold:      rule.Threshold,
+		WindowSeconds:  int64(rule.Window.Seconds()),
+		Rate:           firing.Rate,
+		ReferenceRate:  firing.ReferenceRate,
+		FiredAt:        firing.FiredAt.UTC(),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	_, err = s.sender.Send(ctx, rule.TargetUrl, alertFiredEvent, body)
+
+	return err
+}
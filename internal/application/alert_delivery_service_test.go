@@ -0,0 +1,107 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"currency-rate-app/internal/domains/alert"
+	"currency-rate-app/internal/domains/currency"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordedFiringDelivery struct {
+	firingId      string
+	status        alert.DeliveryStatus
+	attempts      int
+	lastError     *string
+	nextAttemptAt time.Time
+}
+
+func (m *mockAlertRepository) FetchDueFirings(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]alert.AlertFiring, error) {
+	return m.due, nil
+}
+
+func (m *mockAlertRepository) GetAlertRuleById(ctx context.Context, id string) (*alert.AlertRule, error) {
+	for _, rule := range m.rules {
+		if rule.Id == id {
+			return &rule, nil
+		}
+	}
+	return nil, alert.ErrAlertRuleNotFound()
+}
+
+func (m *mockAlertRepository) UpdateFiringDelivery(ctx context.Context, firingId string, status alert.DeliveryStatus, attempts int, lastError *string, nextAttemptAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deliveries = append(m.deliveries, recordedFiringDelivery{firingId: firingId, status: status, attempts: attempts, lastError: lastError, nextAttemptAt: nextAttemptAt})
+	return nil
+}
+
+func newTestAlertDeliveryService(repo *mockAlertRepository, sender *capturingSender, now time.Time) *AlertDeliveryService {
+	repo.rules = []alert.AlertRule{{
+		Id:             "rule-1",
+		BaseCurrency:   currency.EUR,
+		ResultCurrency: currency.USD,
+		Condition:      alert.ConditionAbove,
+		Threshold:      decimal.RequireFromString("1.1"),
+		TargetUrl:      "https://hooks.example.com/a",
+	}}
+
+	svc := NewAlertDeliveryService(repo, sender, CallbackDeliveryConfig{
+		MaxAttempts:    3,
+		InitialBackoff: 10 * time.Second,
+		MaxBackoff:     time.Minute,
+		Lease:          time.Minute,
+	})
+	svc.now = func() time.Time { return now }
+
+	return svc
+}
+
+func TestAlertDeliveryService_Delivers(t *testing.T) {
+	now := time.Date(2024, 10, 2, 12, 0, 0, 0, time.UTC)
+	repo := &mockAlertRepository{due: []alert.AlertFiring{{Id: "firing-1", RuleId: "rule-1", Rate: decimal.RequireFromString("1.2"), FiredAt: now}}}
+	sender := &capturingSender{}
+
+	newTestAlertDeliveryService(repo, sender, now).DeliverDue(context.Background(), 10)
+
+	if assert.Len(t, sender.bodies, 1) {
+		var payload alertFiredPayload
+		assert.NoError(t, json.Unmarshal(sender.bodies[0], &payload))
+		assert.Equal(t, alertFiredEvent, payload.Event)
+		assert.Equal(t, "firing-1", payload.FiringId)
+		assert.Equal(t, currency.EUR, payload.BaseCurrency)
+		assert.Equal(t, "1.2", payload.Rate.String())
+	}
+
+	assert.Equal(t, []recordedFiringDelivery{{firingId: "firing-1", status: alert.DeliveryStatusDelivered, attempts: 1, nextAttemptAt: now}}, repo.deliveries)
+}
+
+func TestAlertDeliveryService_RetriesThenFails(t *testing.T) {
+	now := time.Date(2024, 10, 2, 12, 0, 0, 0, time.UTC)
+	repo := &mockAlertRepository{due: []alert.AlertFiring{
+		{Id: "firing-1", RuleId: "rule-1", FiredAt: now},
+		{Id: "firing-2", RuleId: "rule-1", Attempts: 2, FiredAt: now},
+	}}
+	sender := &capturingSender{err: errors.New("connection refused")}
+
+	newTestAlertDeliveryService(repo, sender, now).DeliverDue(context.Background(), 10)
+
+	byId := make(map[string]recordedFiringDelivery, len(repo.deliveries))
+	for _, d := range repo.deliveries {
+		byId[d.firingId] = d
+	}
+
+	assert.Equal(t, alert.DeliveryStatusPending, byId["firing-1"].status)
+	assert.Equal(t, 1, byId["firing-1"].attempts)
+	assert.True(t, byId["firing-1"].nextAttemptAt.After(now))
+	assert.Equal(t, "connection refused", *byId["firing-1"].lastError)
+
+	assert.Equal(t, alert.DeliveryStatusFailed, byId["firing-2"].status)
+	assert.Equal(t, 3, byId["firing-2"].attempts)
+}
@@ -0,0 +1,129 @@
+package application
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	http_client "currency-rate-app/internal/common/http-client"
+	"currency-rate-app/internal/domains/alert"
+	"currency-rate-app/internal/domains/currency"
+	"currency-rate-app/internal/infrastructure/db"
+
+	"github.com/shopspring/decimal"
+)
+
+// AlertEvaluator is notified by ProcessRatesService about every saved latest rate.
+type AlertEvaluator interface {
+	EvaluateRate(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, rate decimal.Decimal)
+}
+
+type AlertService interface {
+	AlertEvaluator
+	CreateAlertRule(ctx context.Context, rule alert.AlertRule) (*alert.AlertRule, error)
+	ListAlertRules(ctx context.Context) ([]alert.AlertRule, error)
+	GetAlertRuleById(ctx context.Context, id string) (*alert.AlertRule, error)
+	DeleteAlertRule(ctx context.Context, id string) error
+	GetAlertFirings(ctx context.Context, ruleId string) ([]alert.AlertFiring, error)
+}
+
+type alertServiceImpl struct {
+	repo db.AlertRepository
+	now  func() time.Time
+}
+
+func NewAlertService(repo db.AlertRepository) AlertService {
+	return &alertServiceImpl{repo: repo, now: time.Now}
+}
+
+func (s *alertServiceImpl) CreateAlertRule(ctx context.Context, rule alert.AlertRule) (*alert.AlertRule, error) {
+	if err := alert.ValidateAlertRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := http_client.ValidatePublicUrl(rule.TargetUrl); err != nil {
+		return nil, alert.ErrInvalidAlertTargetUrl()
+	}
+
+	return s.repo.CreateAlertRule(ctx, rule)
+}
+
+func (s *alertServiceImpl) ListAlertRules(ctx context.Context) ([]alert.AlertRule, error) {
+	return s.repo.ListAlertRules(ctx)
+}
+
+func (s *alertServiceImpl) GetAlertRuleById(ctx context.Context, id string) (*alert.AlertRule, error) {
+	return s.repo.GetAlertRuleById(ctx, id)
+}
+
+func (s *alertServiceImpl) DeleteAlertRule(ctx context.Context, id string) error {
+	return s.repo.DeleteAlertRule(ctx, id)
+}
+
+func (s *alertServiceImpl) GetAlertFirings(ctx context.Context, ruleId string) ([]alert.AlertFiring, error) {
+	if _, err := s.repo.GetAlertRuleById(ctx, ruleId); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetAlertFirings(ctx, ruleId)
+}
+
+// EvaluateRate fires the rules of the pair whose condition now holds and
+// re-arms the ones whose condition no longer does. Firings are only recorded
+// here, AlertDeliveryService sends them. Errors are logged, a broken rule must
+// not stop rates processing.
+func (s *alertServiceImpl) EvaluateRate(
+	ctx context.Context,
+	baseCurrency currency.CurrencyCode,
+	resultCurrency currency.CurrencyCode,
+	rate decimal.Decimal,
+) {
+	rules, err := s.repo.GetAlertRulesByPair(ctx, baseCurrency, resultCurrency)
+
+	if err != nil {
+		slog.ErrorContext(ctx, "Alert rules fetch failed", slog.String("error", err.Error()))
+
+		return
+	}
+
+	for _, rule := range rules {
+		if err := s.evaluateRule(ctx, rule, rate); err != nil {
+			slog.ErrorContext(
+				ctx,
+				"Alert rule evaluation failed",
+				slog.String("ruleId", rule.Id),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+func (s *alertServiceImpl) evaluateRule(ctx context.Context, rule alert.AlertRule, rate decimal.Decimal) error {
+	var reference *decimal.Decimal
+
+	if rule.Condition == alert.ConditionChangePercent {
+		res, err := s.repo.GetReferenceRate(ctx, rule.BaseCurrency, rule.ResultCurrency, s.now().Add(-rule.Window))
+
+		if err != nil {
+			return err
+		}
+
+		reference = res
+	}
+
+	if !rule.Holds(rate, reference) {
+		if rule.Triggered {
+			return s.repo.ResetAlertRule(ctx, rule.Id)
+		}
+
+		return nil
+	}
+
+	if rule.Triggered {
+		return nil
+	}
+
+	_, err := s.repo.FireAlertRule(ctx, rule.Id, rate, reference)
+
+	return err
+}
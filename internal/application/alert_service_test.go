@@ -0,0 +1,117 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"currency-rate-app/internal/domains/alert"
+	"currency-rate-app/internal/domains/currency"
+	"currency-rate-app/internal/infrastructure/db"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockAlertRepository struct {
+	db.AlertRepository
+	mu         sync.Mutex
+	rules      []alert.AlertRule
+	reference  *decimal.Decimal
+	since      time.Time
+	fired      []string
+	reset      []string
+	due        []alert.AlertFiring
+	deliveries []recordedFiringDelivery
+}
+
+func (m *mockAlertRepository) GetAlertRulesByPair(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode) ([]alert.AlertRule, error) {
+	return m.rules, nil
+}
+
+func (m *mockAlertRepository) GetReferenceRate(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, since time.Time) (*decimal.Decimal, error) {
+	m.since = since
+	return m.reference, nil
+}
+
+func (m *mockAlertRepository) FireAlertRule(ctx context.Context, ruleId string, rate decimal.Decimal, reference *decimal.Decimal) (*alert.AlertFiring, error) {
+	m.fired = append(m.fired, ruleId)
+	return &alert.AlertFiring{Id: "firing-" + ruleId, RuleId: ruleId, Rate: rate, ReferenceRate: reference}, nil
+}
+
+func (m *mockAlertRepository) ResetAlertRule(ctx context.Context, ruleId string) error {
+	m.reset = append(m.reset, ruleId)
+	return nil
+}
+
+func TestEvaluateRate_FiresOnceAndResets(t *testing.T) {
+	repo := &mockAlertRepository{
+		rules: []alert.AlertRule{
+			{Id: "above", Condition: alert.ConditionAbove, Threshold: decimal.RequireFromString("1.1"), TargetUrl: "https://hooks.example.com/a"},
+			{Id: "already", Condition: alert.ConditionAbove, Threshold: decimal.RequireFromString("1.1"), Triggered: true},
+			{Id: "below", Condition: alert.ConditionBelow, Threshold: decimal.RequireFromString("1.0"), Triggered: true},
+		},
+	}
+	svc := NewAlertService(repo)
+
+	svc.EvaluateRate(context.Background(), currency.EUR, currency.USD, decimal.RequireFromString("1.2"))
+
+	assert.Equal(t, []string{"above"}, repo.fired)
+	assert.Equal(t, []string{"below"}, repo.reset)
+}
+
+func TestEvaluateRate_ChangePercent(t *testing.T) {
+	now := time.Date(2024, 10, 2, 12, 0, 0, 0, time.UTC)
+	reference := decimal.RequireFromString("1.00")
+	repo := &mockAlertRepository{
+		rules: []alert.AlertRule{
+			{Id: "move", Condition: alert.ConditionChangePercent, Threshold: decimal.RequireFromString("2"), Window: time.Hour},
+		},
+		reference: &reference,
+	}
+	svc := NewAlertService(repo).(*alertServiceImpl)
+	svc.now = func() time.Time { return now }
+
+	svc.EvaluateRate(context.Background(), currency.EUR, currency.USD, decimal.RequireFromString("1.01"))
+	assert.Empty(t, repo.fired)
+	assert.Equal(t, now.Add(-time.Hour), repo.since)
+
+	svc.EvaluateRate(context.Background(), currency.EUR, currency.USD, decimal.RequireFromString("0.97"))
+	assert.Equal(t, []string{"move"}, repo.fired)
+}
+
+func TestCreateAlertRule_Validation(t *testing.T) {
+	svc := NewAlertService(&mockAlertRepository{})
+	base := alert.AlertRule{
+		BaseCurrency:   currency.EUR,
+		ResultCurrency: currency.USD,
+		Condition:      alert.ConditionAbove,
+		Threshold:      decimal.RequireFromString("1.1"),
+		TargetUrl:      "https://hooks.example.com/a",
+	}
+
+	invalid := map[string]func(r *alert.AlertRule){
+		"InvalidAlertCondition":  func(r *alert.AlertRule) { r.Condition = "CROSS" },
+		"InvalidAlertThreshold":  func(r *alert.AlertRule) { r.Threshold = decimal.Zero },
+		"InvalidAlertWindow":     func(r *alert.AlertRule) { r.Window = time.Hour },
+		"InvalidAlertTargetUrl":  func(r *alert.AlertRule) { r.TargetUrl = "ftp://hooks.example.com" },
+		"CurrenciesShouldDiffer": func(r *alert.AlertRule) { r.ResultCurrency = currency.EUR },
+	}
+
+	for code, mutate := range invalid {
+		rule := base
+		mutate(&rule)
+
+		_, err := svc.CreateAlertRule(context.Background(), rule)
+
+		if assert.Error(t, err, code) {
+			assert.Contains(t, err.Error(), code)
+		}
+	}
+
+	changeRule := base
+	changeRule.Condition = alert.ConditionChangePercent
+	_, err := svc.CreateAlertRule(context.Background(), changeRule)
+	assert.Equal(t, alert.ErrInvalidAlertWindow(), err)
+}
@@ -0,0 +1,165 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"currency-rate-app/internal/common/utils"
+	"currency-rate-app/internal/domains/callback"
+	"currency-rate-app/internal/domains/currency"
+	"currency-rate-app/internal/infrastructure/db"
+	"currency-rate-app/internal/infrastructure/http/webhook"
+
+	"github.com/shopspring/decimal"
+)
+
+type CallbackDeliveryConfig struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Lease is how long a fetched delivery is hidden from other instances. It
+	// must be longer than one attempt, including the HTTP timeout.
+	Lease time.Duration
+}
+
+// CallbackDeliveryService sends the callbacks scheduled by the currency
+// repository. Each run makes one attempt per due delivery, the retries with
+// backoff are spread over the following runs.
+type CallbackDeliveryService struct {
+	repo         db.CallbackRepository
+	currencyRepo db.CurrencyRepository
+	sender       webhook.Sender
+	config       CallbackDeliveryConfig
+	now          func() time.Time
+}
+
+func NewCallbackDeliveryService(
+	repo db.CallbackRepository,
+	currencyRepo db.CurrencyRepository,
+	sender webhook.Sender,
+	config CallbackDeliveryConfig,
+) *CallbackDeliveryService {
+	return &CallbackDeliveryService{repo: repo, currencyRepo: currencyRepo, sender: sender, config: config, now: time.Now}
+}
+
+type rateCallbackPayload struct {
+	Event          callback.Event              `json:"event"`
+	DeliveryId     string                      `json:"deliveryId"`
+	Id             string                      `json:"id"`
+	BaseCurrency   currency.CurrencyCode       `json:"baseCurrency"`
+	ResultCurrency currency.CurrencyCode       `json:"resultCurrency"`
+	Date           *string                     `json:"date,omitempty"`
+	Status         currency.CurrencyRateStatus `json:"status"`
+	Rate           *decimal.Decimal            `json:"rate,omitempty"`
+	PivotCurrency  *currency.CurrencyCode      `json:"pivotCurrency,omitempty"`
+	CompletedAt    *time.Time                  `json:"completedAt,omitempty"`
+	FailureCode    *currency.RateFailureCode   `json:"failureCode,omitempty"`
+	FailureMessage *string                     `json:"failureMessage,omitempty"`
+}
+
+func (s *CallbackDeliveryService) DeliverDue(ctx context.Context, batchSize int) {
+	deliveries, err := s.repo.FetchDueDeliveries(ctx, s.now(), s.config.Lease, batchSize)
+
+	if err != nil {
+		slog.ErrorContext(ctx, "Callbacks fetch failed", slog.String("error", err.Error()))
+
+		return
+	}
+
+	var wg sync.WaitGroup
+
+	for _, delivery := range deliveries {
+		d := delivery
+
+		wg.Go(func() {
+			defer utils.HandleRecover()
+
+			s.deliver(ctx, d)
+		})
+	}
+
+	wg.Wait()
+}
+
+func (s *CallbackDeliveryService) deliver(ctx context.Context, delivery callback.Delivery) {
+	attempt := callback.Attempt{Number: delivery.Attempts + 1}
+	startedAt := s.now()
+
+	sendErr := s.send(ctx, delivery)
+
+	attempt.CreatedAt = s.now()
+	attempt.Duration = attempt.CreatedAt.Sub(startedAt)
+
+	status := callback.DeliveryStatusDelivered
+	nextAttemptAt := attempt.CreatedAt
+
+	if sendErr != nil {
+		msg := sendErr.Error()
+		attempt.Error = &msg
+
+		var statusErr *webhook.StatusError
+		if errors.As(sendErr, &statusErr) {
+			attempt.StatusCode = &statusErr.StatusCode
+		}
+
+		status = callback.DeliveryStatusPending
+		nextAttemptAt = attempt.CreatedAt.Add(utils.Backoff(attempt.Number, s.config.InitialBackoff, s.config.MaxBackoff))
+
+		if attempt.Number >= s.config.MaxAttempts {
+			status = callback.DeliveryStatusFailed
+		}
+
+		slog.WarnContext(
+			ctx,
+			"Callback delivery failed",
+			slog.String("deliveryId", delivery.Id),
+			slog.String("rateId", delivery.RateId),
+			slog.Int("attempt", attempt.Number),
+			slog.String("error", msg),
+		)
+	}
+
+	if err := s.repo.RecordAttempt(ctx, delivery.Id, attempt, status, nextAttemptAt); err != nil {
+		slog.ErrorContext(ctx, "Callback attempt save failed", slog.String("deliveryId", delivery.Id), slog.String("error", err.Error()))
+	}
+}
+
+func (s *CallbackDeliveryService) send(ctx context.Context, delivery callback.Delivery) error {
+	rate, err := s.currencyRepo.GetRateById(ctx, delivery.RateId)
+
+	if err != nil {
+		return err
+	}
+
+	payload := rateCallbackPayload{
+		Event:          delivery.Event,
+		DeliveryId:     delivery.Id,
+		Id:             rate.Id,
+		BaseCurrency:   rate.BaseCurrency,
+		ResultCurrency: rate.ResultCurrency,
+		Date:           currency.FormatRateDate(rate.RateDate),
+		Status:         rate.Status,
+		Rate:           rate.Rate,
+		PivotCurrency:  rate.PivotCurrency,
+		CompletedAt:    rate.CompletedAt,
+	}
+
+	if rate.Failure != nil {
+		payload.FailureCode = &rate.Failure.Code
+		payload.FailureMessage = &rate.Failure.Message
+	}
+
+	body, err := json.Marshal(payload)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = s.sender.Send(ctx, delivery.Url, string(delivery.Event), body)
+
+	return err
+}
@@ -0,0 +1,142 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"currency-rate-app/internal/common/utils"
+	"currency-rate-app/internal/domains/callback"
+	"currency-rate-app/internal/domains/currency"
+	"currency-rate-app/internal/infrastructure/http/webhook"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordedAttempt struct {
+	deliveryId    string
+	attempt       callback.Attempt
+	status        callback.DeliveryStatus
+	nextAttemptAt time.Time
+}
+
+type mockCallbackRepository struct {
+	mu         sync.Mutex
+	due        []callback.Delivery
+	lease      time.Duration
+	attempts   []recordedAttempt
+	deliveries []callback.Delivery
+}
+
+func (m *mockCallbackRepository) FetchDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]callback.Delivery, error) {
+	m.lease = lease
+	return m.due, nil
+}
+
+func (m *mockCallbackRepository) RecordAttempt(ctx context.Context, deliveryId string, attempt callback.Attempt, status callback.DeliveryStatus, nextAttemptAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.attempts = append(m.attempts, recordedAttempt{deliveryId: deliveryId, attempt: attempt, status: status, nextAttemptAt: nextAttemptAt})
+	return nil
+}
+
+func (m *mockCallbackRepository) GetDeliveriesByRateId(ctx context.Context, rateId string) ([]callback.Delivery, error) {
+	return m.deliveries, nil
+}
+
+type capturingSender struct {
+	mu     sync.Mutex
+	bodies [][]byte
+	err    error
+}
+
+func (s *capturingSender) Send(ctx context.Context, targetUrl string, event string, body []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bodies = append(s.bodies, body)
+	return 1, s.err
+}
+
+func newTestCallbackDeliveryService(repo *mockCallbackRepository, sender webhook.Sender, now time.Time) *CallbackDeliveryService {
+	rate := decimal.RequireFromString("0.91")
+	completed := now.Add(-time.Minute)
+	currencyRepo := &mockCurrencyRepository{
+		getRateByIdFunc: func(ctx context.Context, id string) (*currency.CurrencyRate, error) {
+			return &currency.CurrencyRate{Id: id, BaseCurrency: currency.USD, ResultCurrency: currency.EUR, Status: currency.CurrencyRateStatusCompleted, Rate: &rate, CompletedAt: &completed}, nil
+		},
+	}
+
+	svc := NewCallbackDeliveryService(repo, currencyRepo, sender, CallbackDeliveryConfig{
+		MaxAttempts:    3,
+		InitialBackoff: 10 * time.Second,
+		MaxBackoff:     time.Minute,
+		Lease:          time.Minute,
+	})
+	svc.now = func() time.Time { return now }
+
+	return svc
+}
+
+func TestDeliverDue_Delivered(t *testing.T) {
+	now := testTime()
+	repo := &mockCallbackRepository{
+		due: []callback.Delivery{{Id: "d1", RateId: "r1", Url: "https://hooks.example.com", Event: callback.EventRateCompleted}},
+	}
+	sender := &capturingSender{}
+	svc := newTestCallbackDeliveryService(repo, sender, now)
+
+	svc.DeliverDue(context.Background(), 10)
+
+	assert.Equal(t, time.Minute, repo.lease)
+	assert.Len(t, repo.attempts, 1)
+	assert.Equal(t, callback.DeliveryStatusDelivered, repo.attempts[0].status)
+	assert.Equal(t, 1, repo.attempts[0].attempt.Number)
+
+	var payload map[string]any
+	assert.NoError(t, json.Unmarshal(sender.bodies[0], &payload))
+	assert.Equal(t, "currency_rate.completed", payload["event"])
+	assert.Equal(t, "d1", payload["deliveryId"])
+	assert.Equal(t, "r1", payload["id"])
+	assert.Equal(t, "0.91", payload["rate"])
+	assert.Equal(t, "COMPLETED", payload["status"])
+}
+
+func TestDeliverDue_RetriesWithBackoffThenFails(t *testing.T) {
+	now := testTime()
+	sender := &capturingSender{err: &webhook.StatusError{StatusCode: 503, Status: "503 Service Unavailable"}}
+
+	cases := []struct {
+		previousAttempts int
+		status           callback.DeliveryStatus
+		delay            time.Duration
+	}{
+		{previousAttempts: 0, status: callback.DeliveryStatusPending, delay: 10 * time.Second},
+		{previousAttempts: 1, status: callback.DeliveryStatusPending, delay: 20 * time.Second},
+		{previousAttempts: 2, status: callback.DeliveryStatusFailed, delay: 40 * time.Second},
+	}
+
+	for _, tc := range cases {
+		repo := &mockCallbackRepository{
+			due: []callback.Delivery{{Id: "d1", RateId: "r1", Url: "https://hooks.example.com", Event: callback.EventRateCompleted, Attempts: tc.previousAttempts}},
+		}
+		svc := newTestCallbackDeliveryService(repo, sender, now)
+
+		svc.DeliverDue(context.Background(), 10)
+
+		recorded := repo.attempts[0]
+		assert.Equal(t, tc.status, recorded.status)
+		assert.Equal(t, tc.previousAttempts+1, recorded.attempt.Number)
+		assert.Equal(t, now.Add(tc.delay), recorded.nextAttemptAt)
+		assert.Equal(t, 503, *recorded.attempt.StatusCode)
+		assert.NotNil(t, recorded.attempt.Error)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 10*time.Second, utils.Backoff(1, 10*time.Second, time.Hour))
+	assert.Equal(t, 80*time.Second, utils.Backoff(4, 10*time.Second, time.Hour))
+	assert.Equal(t, time.Hour, utils.Backoff(30, 10*time.Second, time.Hour))
+}
@@ -0,0 +1,29 @@
+package application
+
+import (
+	"context"
+
+	"currency-rate-app/internal/domains/callback"
+	"currency-rate-app/internal/infrastructure/db"
+)
+
+type CallbackService interface {
+	GetRateCallbacks(ctx context.Context, rateId string) ([]callback.Delivery, error)
+}
+
+type callbackServiceImpl struct {
+	repo         db.CallbackRepository
+	currencyRepo db.CurrencyRepository
+}
+
+func NewCallbackService(repo db.CallbackRepository, currencyRepo db.CurrencyRepository) CallbackService {
+	return &callbackServiceImpl{repo: repo, currencyRepo: currencyRepo}
+}
+
+func (s *callbackServiceImpl) GetRateCallbacks(ctx context.Context, rateId string) ([]callback.Delivery, error) {
+	if _, err := s.currencyRepo.GetRateById(ctx, rateId); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetDeliveriesByRateId(ctx, rateId)
+}
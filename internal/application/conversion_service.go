@@ -0,0 +1,38 @@
+package application
+
+import (
+	"context"
+	"currency-rate-app/internal/domains/currency"
+
+	"github.com/shopspring/decimal"
+)
+
+type ConversionService interface {
+	Convert(ctx context.Context, from currency.CurrencyCode, to currency.CurrencyCode, amount decimal.Decimal, mode currency.RoundingMode) (*currency.Conversion, error)
+}
+
+type conversionServiceImpl struct {
+	currencyService CurrencyService
+}
+
+func NewConversionService(
+	currencyService CurrencyService,
+) ConversionService {
+	return &conversionServiceImpl{currencyService: currencyService}
+}
+
+func (s *conversionServiceImpl) Convert(
+	ctx context.Context,
+	from currency.CurrencyCode,
+	to currency.CurrencyCode,
+	amount decimal.Decimal,
+	mode currency.RoundingMode,
+) (*currency.Conversion, error) {
+	rate, err := s.currencyService.GetActualRate(ctx, from, to, ActualRateOptions{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return currency.Convert(amount, *rate, mode)
+}
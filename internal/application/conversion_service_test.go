@@ -0,0 +1,72 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"currency-rate-app/internal/domains/currency"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConversionService_Convert(t *testing.T) {
+	ctx := context.Background()
+	completedAt := testTime()
+
+	tests := []struct {
+		name     string
+		to       currency.CurrencyCode
+		rate     string
+		amount   string
+		mode     currency.RoundingMode
+		expected string
+	}{
+		{"half_even_down", currency.EUR, "0.5", "0.05", currency.RoundingModeHalfEven, "0.02"},
+		{"half_even_up", currency.EUR, "0.5", "0.07", currency.RoundingModeHalfEven, "0.04"},
+		{"half_up", currency.EUR, "0.5", "0.05", currency.RoundingModeHalfUp, "0.03"},
+		{"down", currency.EUR, "0.5", "0.07", currency.RoundingModeDown, "0.03"},
+		{"zero_minor_units", "JPY", "151.237", "10", currency.RoundingModeHalfEven, "1512"},
+		{"three_minor_units", "KWD", "0.30712", "123.45", currency.RoundingModeHalfEven, "37.914"},
+		{"trailing_zeros", currency.EUR, "2", "50", currency.RoundingModeHalfEven, "100.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate := decimal.RequireFromString(tt.rate)
+			repo := &mockRepo{
+				getActualFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, date *time.Time) (*currency.CurrencyRate, error) {
+					return &currency.CurrencyRate{BaseCurrency: base, ResultCurrency: result, Rate: &rate, CompletedAt: &completedAt, Status: currency.CurrencyRateStatusCompleted}, nil
+				},
+			}
+			service := NewConversionService(NewCurrencyService(repo, FreshnessConfig{}, NewRateCompletionHub()))
+
+			res, err := service.Convert(ctx, currency.USD, tt.to, decimal.RequireFromString(tt.amount), tt.mode)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, res.FormattedAmount())
+			assert.Equal(t, tt.rate, res.Rate.String())
+			assert.Equal(t, completedAt, res.RateCompletedAt)
+		})
+	}
+}
+
+func TestConversionService_ConvertValidation(t *testing.T) {
+	ctx := context.Background()
+	rate := decimal.RequireFromString("1.1")
+	completedAt := testTime()
+
+	repo := &mockRepo{
+		getActualFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, date *time.Time) (*currency.CurrencyRate, error) {
+			return &currency.CurrencyRate{BaseCurrency: base, ResultCurrency: result, Rate: &rate, CompletedAt: &completedAt, Status: currency.CurrencyRateStatusCompleted}, nil
+		},
+	}
+	service := NewConversionService(NewCurrencyService(repo, FreshnessConfig{}, NewRateCompletionHub()))
+
+	_, err := service.Convert(ctx, currency.USD, currency.EUR, decimal.RequireFromString("-1"), currency.RoundingModeHalfEven)
+	assert.Equal(t, currency.ErrInvalidAmount(), err)
+
+	_, err = service.Convert(ctx, currency.USD, currency.EUR, decimal.RequireFromString("1"), "ceiling")
+	assert.Equal(t, currency.ErrInvalidRoundingMode(), err)
+}
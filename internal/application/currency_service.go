@@ -3,32 +3,142 @@ package application
 import (
 	"context"
 	error_utils "currency-rate-app/internal/common/error-utils"
+	http_client "currency-rate-app/internal/common/http-client"
+	"currency-rate-app/internal/domains/callback"
 	"currency-rate-app/internal/domains/currency"
 	"currency-rate-app/internal/infrastructure/db"
+	"errors"
+	"fmt"
+	"time"
 )
 
+type ActualRateOptions struct {
+	// RateDate selects rates for a business date, nil means the latest rates.
+	RateDate *time.Time
+	// AllowInverse lets the opposite pair answer with 1/rate when it is fresher
+	// than any direct rate.
+	AllowInverse bool
+	// MaxAge overrides the configured freshness limit for the pair.
+	MaxAge *time.Duration
+	// StalePolicy decides what to do with a rate older than the limit,
+	// defaults to StalePolicyAllow.
+	StalePolicy currency.StalePolicy
+}
+
+type FreshnessConfig struct {
+	// DefaultMaxAge applies to pairs without their own limit, zero disables
+	// the check.
+	DefaultMaxAge time.Duration
+	// MaxAgeByPair is keyed by concatenated codes, e.g. "EURUSD".
+	MaxAgeByPair map[string]time.Duration
+}
+
+func (c FreshnessConfig) maxAgeFor(baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode) time.Duration {
+	if maxAge, ok := c.MaxAgeByPair[string(baseCurrency)+string(resultCurrency)]; ok {
+		return maxAge
+	}
+
+	return c.DefaultMaxAge
+}
+
 type CurrencyService interface {
-	GetActualRate(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode) (*currency.CurrencyRate, error)
+	GetActualRate(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, opts ActualRateOptions) (*currency.CurrencyRate, error)
 	GetCompletedRateById(ctx context.Context, id string) (*currency.CurrencyRate, error)
-	CreateRate(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, idempotencyKey string) (*currency.CurrencyRate, error)
+	GetRateById(ctx context.Context, id string) (*currency.CurrencyRate, error)
+	CreateRate(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, rateDate *time.Time, clientId string, idempotencyKey string, callbackUrl *string) (*currency.CurrencyRate, error)
+	GetRateMatrix(ctx context.Context, codes []currency.CurrencyCode, rateDate *time.Time) (*currency.RateMatrix, error)
+	ListRates(ctx context.Context, query currency.RateListQuery) (*currency.RatePage, error)
+	CancelRate(ctx context.Context, id string) (*currency.CurrencyRate, error)
 }
 
 type currencyServiceImpl struct {
-	repo db.CurrencyRepository
+	repo        db.CurrencyRepository
+	freshness   FreshnessConfig
+	completions RateCompletionNotifier
 }
 
 func NewCurrencyService(
 	repo db.CurrencyRepository,
+	freshness FreshnessConfig,
+	completions RateCompletionNotifier,
 ) CurrencyService {
-	return &currencyServiceImpl{repo: repo}
+	return &currencyServiceImpl{repo: repo, freshness: freshness, completions: completions}
 }
 
 func (s *currencyServiceImpl) GetActualRate(
 	ctx context.Context,
 	baseCurrency currency.CurrencyCode,
 	resultCurrency currency.CurrencyCode,
+	opts ActualRateOptions,
 ) (*currency.CurrencyRate, error) {
-	return s.repo.GetActualRateByCurrency(ctx, baseCurrency, resultCurrency)
+	rate, err := s.getFreshestRate(ctx, baseCurrency, resultCurrency, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Rates for a past business date never change, so only the latest rates
+	// can go stale.
+	if opts.RateDate != nil {
+		return rate, nil
+	}
+
+	maxAge := s.freshness.maxAgeFor(baseCurrency, resultCurrency)
+
+	if opts.MaxAge != nil {
+		maxAge = *opts.MaxAge
+	}
+
+	if maxAge <= 0 {
+		return rate, nil
+	}
+
+	age := rate.Age(time.Now())
+
+	if age <= maxAge {
+		return rate, nil
+	}
+
+	if opts.StalePolicy == currency.StalePolicyError {
+		return nil, currency.ErrCurrencyRateStale(age)
+	}
+
+	rate.Stale = true
+
+	return rate, nil
+}
+
+func (s *currencyServiceImpl) getFreshestRate(
+	ctx context.Context,
+	baseCurrency currency.CurrencyCode,
+	resultCurrency currency.CurrencyCode,
+	opts ActualRateOptions,
+) (*currency.CurrencyRate, error) {
+	direct, err := s.repo.GetActualRateByCurrency(ctx, baseCurrency, resultCurrency, opts.RateDate)
+
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+
+	if !opts.AllowInverse {
+		return direct, err
+	}
+
+	opposite, oppositeErr := s.repo.GetActualRateByCurrency(ctx, resultCurrency, baseCurrency, opts.RateDate)
+
+	if oppositeErr != nil && !isNotFound(oppositeErr) {
+		return nil, oppositeErr
+	}
+
+	if opposite == nil || opposite.Rate == nil || opposite.Rate.IsZero() {
+		return direct, err
+	}
+
+	if direct != nil && !opposite.CompletedAt.After(*direct.CompletedAt) {
+		return direct, nil
+	}
+
+	return invertRate(opposite), nil
 }
 
 func (s *currencyServiceImpl) GetCompletedRateById(
@@ -46,7 +156,9 @@ func (s *currencyServiceImpl) GetCompletedRateById(
 		case val.Status == currency.CurrencyRateStatusPending || val.Status == currency.CurrencyRateStatusProcessing:
 			return nil, currency.ErrCurrencyRateNotCompletedYet()
 		case val.Status == currency.CurrencyRateStatusFailed:
-			return nil, currency.ErrCurrencyRateFetchFailed()
+			return nil, currency.ErrCurrencyRateFetchFailed(val.Failure)
+		case val.Status == currency.CurrencyRateStatusCancelled:
+			return nil, currency.ErrCurrencyRateCancelled()
 		default:
 			return nil, error_utils.ErrInternalServerError("inconsistent entity state")
 		}
@@ -55,11 +167,146 @@ func (s *currencyServiceImpl) GetCompletedRateById(
 	return val, nil
 }
 
+// GetRateById answers the request in any status, for clients polling its progress.
+func (s *currencyServiceImpl) GetRateById(ctx context.Context, id string) (*currency.CurrencyRate, error) {
+	return s.repo.GetRateById(ctx, id)
+}
+
 func (s *currencyServiceImpl) CreateRate(
 	ctx context.Context,
 	baseCurrency currency.CurrencyCode,
 	resultCurrency currency.CurrencyCode,
+	rateDate *time.Time,
+	clientId string,
 	idempotencyKey string,
+	callbackUrl *string,
 ) (*currency.CurrencyRate, error) {
-	return s.repo.CreateRate(ctx, baseCurrency, resultCurrency, idempotencyKey)
+	if callbackUrl != nil {
+		if err := http_client.ValidatePublicUrl(*callbackUrl); err != nil {
+			return nil, callback.ErrInvalidCallbackUrl(err.Error())
+		}
+	}
+
+	return s.repo.CreateRate(ctx, baseCurrency, resultCurrency, rateDate, clientId, idempotencyKey, callbackUrl)
+}
+
+// GetRateMatrix answers every ordered pair of the codes from one query. Pairs
+// without a rate stay in the matrix with a nil rate, stale latest rates are
+// marked rather than rejected.
+func (s *currencyServiceImpl) GetRateMatrix(
+	ctx context.Context,
+	codes []currency.CurrencyCode,
+	rateDate *time.Time,
+) (*currency.RateMatrix, error) {
+	if err := currency.ValidateRateMatrixCodes(codes); err != nil {
+		return nil, err
+	}
+
+	rates, err := s.repo.GetActualRatesByCurrencies(ctx, codes, rateDate)
+
+	if err != nil {
+		return nil, err
+	}
+
+	byPair := make(map[currency.CurrencyPair]*currency.CurrencyRate, len(rates))
+
+	for i := range rates {
+		byPair[currency.CurrencyPair{BaseCurrency: rates[i].BaseCurrency, ResultCurrency: rates[i].ResultCurrency}] = &rates[i]
+	}
+
+	now := time.Now()
+	cells := make([]currency.RateMatrixCell, 0, len(codes)*(len(codes)-1))
+
+	for _, base := range codes {
+		for _, result := range codes {
+			if base == result {
+				continue
+			}
+
+			rate := byPair[currency.CurrencyPair{BaseCurrency: base, ResultCurrency: result}]
+
+			if rate != nil && rateDate == nil {
+				maxAge := s.freshness.maxAgeFor(base, result)
+				rate.Stale = maxAge > 0 && rate.Age(now) > maxAge
+			}
+
+			cells = append(cells, currency.RateMatrixCell{BaseCurrency: base, ResultCurrency: result, Rate: rate})
+		}
+	}
+
+	return &currency.RateMatrix{Codes: codes, Cells: cells}, nil
+}
+
+// CancelRate withdraws a PENDING request. Requests already picked up by the
+// worker or finished are answered with CurrencyRateNotCancellable.
+func (s *currencyServiceImpl) CancelRate(ctx context.Context, id string) (*currency.CurrencyRate, error) {
+	rate, err := s.repo.CancelRate(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.completions.NotifyFinal([]string{rate.Id})
+
+	return rate, nil
+}
+
+func (s *currencyServiceImpl) ListRates(ctx context.Context, query currency.RateListQuery) (*currency.RatePage, error) {
+	if query.SortField == "" {
+		query.SortField = currency.RateSortCreatedAt
+	}
+
+	if query.Limit == 0 {
+		query.Limit = currency.DefaultRateListLimit
+	}
+
+	if query.Limit < 0 || query.Limit > currency.MaxRateListLimit {
+		return nil, currency.ErrInvalidRateListQuery(fmt.Sprintf("limit should be from 1 to %d", currency.MaxRateListLimit))
+	}
+
+	if cursor := query.Cursor; cursor != nil && (cursor.SortField != query.SortField || cursor.Desc != query.Desc) {
+		return nil, currency.ErrInvalidRateCursor()
+	}
+
+	// One extra row tells whether there is a next page.
+	limit := query.Limit
+	query.Limit++
+
+	rates, err := s.repo.ListRates(ctx, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	page := &currency.RatePage{Items: rates}
+
+	if len(rates) > limit {
+		last := rates[limit-1]
+		page.Items = rates[:limit]
+		page.Next = &currency.RateCursor{
+			SortField: query.SortField,
+			Desc:      query.Desc,
+			Value:     last.SortValue(query.SortField),
+			Id:        last.Id,
+		}
+	}
+
+	return page, nil
+}
+
+func invertRate(rate *currency.CurrencyRate) *currency.CurrencyRate {
+	inverse := currency.InverseRate(*rate.Rate)
+
+	res := *rate
+	res.BaseCurrency, res.ResultCurrency = rate.ResultCurrency, rate.BaseCurrency
+	res.Rate = &inverse
+	res.Inverted = true
+
+	return &res
+}
+
+func isNotFound(err error) bool {
+	var customErr *error_utils.CustomError
+
+	return errors.As(err, &customErr) && customErr.ErrorType == error_utils.ErrorCodeNotFound
 }
@@ -9,50 +9,101 @@ import (
 	error_utils "currency-rate-app/internal/common/error-utils"
 	"currency-rate-app/internal/domains/currency"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 )
 
 type mockRepo struct {
-	getActualFn               func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode) (*currency.CurrencyRate, error)
+	getActualFn               func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, date *time.Time) (*currency.CurrencyRate, error)
 	getByIdFn                 func(ctx context.Context, id string) (*currency.CurrencyRate, error)
-	createFn                  func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, idem string) (*currency.CurrencyRate, error)
-	updateRateStatusByIds     func(ctx context.Context, ids []string, status currency.CurrencyRateStatus) error
-	saveRatesByIds            func(ctx context.Context, ids []string, rate float64) error
+	cancelRateFn              func(ctx context.Context, id string) (*currency.CurrencyRate, error)
+	listRatesFn               func(ctx context.Context, query currency.RateListQuery) ([]currency.CurrencyRate, error)
+	getActualRatesFn          func(ctx context.Context, codes []currency.CurrencyCode, date *time.Time) ([]currency.CurrencyRate, error)
+	createFn                  func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, date *time.Time, clientId string, idem string, callbackUrl *string) (*currency.CurrencyRate, error)
+	failRatesByIds            func(ctx context.Context, ids []string, failure currency.RateFailure) error
+	saveRatesByIds            func(ctx context.Context, ids []string, quote currency.RateQuote) error
 	fetchAndMarkForProcessing func(ctx context.Context, limit int) ([]currency.CurrencyRate, error)
+	getRateHistory            func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, from time.Time, to time.Time) ([]currency.TimeSeriesPoint, error)
+	saveRateHistory           func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, points []currency.TimeSeriesPoint) error
+	createRateBatch           func(ctx context.Context, clientId string, idempotencyKey string, requestHash string, items []currency.RateBatchItem) (*currency.RateBatch, error)
+	getRateBatchById          func(ctx context.Context, id string) (*currency.RateBatch, error)
+	requeueRateFn             func(ctx context.Context, id string, actor string) (*currency.CurrencyRate, error)
+	requeueFailedRatesFn      func(ctx context.Context, filter currency.FailedRateFilter, limit int, actor string) ([]currency.CurrencyRate, error)
+	archiveRateFn             func(ctx context.Context, id string, actor string) (*currency.CurrencyRate, error)
+	getRateAdminActionsFn     func(ctx context.Context, id string) ([]currency.RateAdminAction, error)
 }
 
-func (m *mockRepo) GetActualRateByCurrency(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode) (*currency.CurrencyRate, error) {
-	return m.getActualFn(ctx, base, result)
+func (m *mockRepo) GetActualRateByCurrency(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, date *time.Time) (*currency.CurrencyRate, error) {
+	return m.getActualFn(ctx, base, result, date)
 }
 func (m *mockRepo) GetRateById(ctx context.Context, id string) (*currency.CurrencyRate, error) {
 	return m.getByIdFn(ctx, id)
 }
-func (m *mockRepo) CreateRate(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, idem string) (*currency.CurrencyRate, error) {
-	return m.createFn(ctx, base, result, idem)
+func (m *mockRepo) GetActualRatesByCurrencies(ctx context.Context, codes []currency.CurrencyCode, date *time.Time) ([]currency.CurrencyRate, error) {
+	return m.getActualRatesFn(ctx, codes, date)
 }
-func (m *mockRepo) UpdateRateStatusByIds(ctx context.Context, ids []string, status currency.CurrencyRateStatus) error {
-	return m.updateRateStatusByIds(ctx, ids, status)
+func (m *mockRepo) CancelRate(ctx context.Context, id string) (*currency.CurrencyRate, error) {
+	return m.cancelRateFn(ctx, id)
 }
-func (m *mockRepo) SaveRatesByIds(ctx context.Context, ids []string, rate float64) error {
-	return m.saveRatesByIds(ctx, ids, rate)
+func (m *mockRepo) ListRates(ctx context.Context, query currency.RateListQuery) ([]currency.CurrencyRate, error) {
+	return m.listRatesFn(ctx, query)
 }
-func (m *mockRepo) FetchAndMarkForProcessing(ctx context.Context, limit int) ([]currency.CurrencyRate, error) {
+func (m *mockRepo) CreateRate(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, date *time.Time, clientId string, idem string, callbackUrl *string) (*currency.CurrencyRate, error) {
+	return m.createFn(ctx, base, result, date, clientId, idem, callbackUrl)
+}
+func (m *mockRepo) FailRatesByIds(ctx context.Context, lease currency.RateLease, ids []string, failure currency.RateFailure) (int64, error) {
+	return int64(len(ids)), m.failRatesByIds(ctx, ids, failure)
+}
+func (m *mockRepo) SaveRatesByIds(ctx context.Context, lease currency.RateLease, ids []string, quote currency.RateQuote) (int64, error) {
+	return int64(len(ids)), m.saveRatesByIds(ctx, ids, quote)
+}
+func (m *mockRepo) RetryRatesByIds(ctx context.Context, lease currency.RateLease, ids []string, nextAttemptAt time.Time, failure currency.RateFailure) (int64, error) {
+	return int64(len(ids)), nil
+}
+func (m *mockRepo) FetchAndMarkForProcessing(ctx context.Context, workerId string, limit int) ([]currency.CurrencyRate, error) {
 	return m.fetchAndMarkForProcessing(ctx, limit)
 }
+func (m *mockRepo) ReclaimExpiredRates(ctx context.Context, startedBefore time.Time, maxAttempts int, limit int) ([]currency.CurrencyRate, error) {
+	return nil, nil
+}
+func (m *mockRepo) GetRateHistory(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, from time.Time, to time.Time) ([]currency.TimeSeriesPoint, error) {
+	return m.getRateHistory(ctx, base, result, from, to)
+}
+func (m *mockRepo) SaveRateHistory(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, points []currency.TimeSeriesPoint) error {
+	return m.saveRateHistory(ctx, base, result, points)
+}
+func (m *mockRepo) CreateRateBatch(ctx context.Context, clientId string, idempotencyKey string, requestHash string, items []currency.RateBatchItem) (*currency.RateBatch, error) {
+	return m.createRateBatch(ctx, clientId, idempotencyKey, requestHash, items)
+}
+func (m *mockRepo) GetRateBatchById(ctx context.Context, id string) (*currency.RateBatch, error) {
+	return m.getRateBatchById(ctx, id)
+}
+func (m *mockRepo) RequeueRate(ctx context.Context, id string, actor string) (*currency.CurrencyRate, error) {
+	return m.requeueRateFn(ctx, id, actor)
+}
+func (m *mockRepo) RequeueFailedRates(ctx context.Context, filter currency.FailedRateFilter, limit int, actor string) ([]currency.CurrencyRate, error) {
+	return m.requeueFailedRatesFn(ctx, filter, limit, actor)
+}
+func (m *mockRepo) ArchiveRate(ctx context.Context, id string, actor string) (*currency.CurrencyRate, error) {
+	return m.archiveRateFn(ctx, id, actor)
+}
+func (m *mockRepo) GetRateAdminActions(ctx context.Context, id string) ([]currency.RateAdminAction, error) {
+	return m.getRateAdminActionsFn(ctx, id)
+}
 
 func TestCurrencyService_GetActualRate(t *testing.T) {
 	ctx := context.Background()
-	rate := 1.23
+	rate := decimal.RequireFromString("1.23")
 	completedAt := testTime()
 
 	repo := &mockRepo{
-		getActualFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode) (*currency.CurrencyRate, error) {
+		getActualFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, date *time.Time) (*currency.CurrencyRate, error) {
 			return &currency.CurrencyRate{BaseCurrency: base, ResultCurrency: result, Rate: &rate, CompletedAt: &completedAt, Status: currency.CurrencyRateStatusCompleted}, nil
 		},
 	}
-	service := NewCurrencyService(repo)
+	service := NewCurrencyService(repo, FreshnessConfig{}, NewRateCompletionHub())
 
-	res, err := service.GetActualRate(ctx, currency.USD, currency.EUR)
+	res, err := service.GetActualRate(ctx, currency.USD, currency.EUR, ActualRateOptions{})
 
 	assert.Nil(t, err, "unexpected error")
 	assert.Equal(t, res.BaseCurrency, currency.USD)
@@ -60,9 +111,119 @@ func TestCurrencyService_GetActualRate(t *testing.T) {
 	assert.Equal(t, *res.Rate, rate)
 }
 
+func TestCurrencyService_GetActualRate_Inverse(t *testing.T) {
+	ctx := context.Background()
+	older := testTime()
+	newer := older.Add(time.Hour)
+
+	rateFor := func(base currency.CurrencyCode, result currency.CurrencyCode, rate string, completedAt time.Time) *currency.CurrencyRate {
+		r := decimal.RequireFromString(rate)
+		return &currency.CurrencyRate{BaseCurrency: base, ResultCurrency: result, Rate: &r, CompletedAt: &completedAt, Status: currency.CurrencyRateStatusCompleted}
+	}
+
+	tests := []struct {
+		name         string
+		allowInverse bool
+		direct       *currency.CurrencyRate
+		opposite     *currency.CurrencyRate
+		expectErr    error
+		expectRate   string
+		inverted     bool
+	}{
+		{"direct_only", true, rateFor(currency.USD, currency.EUR, "0.9", older), nil, nil, "0.9", false},
+		{"opposite_only", true, nil, rateFor(currency.EUR, currency.USD, "1.25", older), nil, "0.8", true},
+		{"opposite_fresher", true, rateFor(currency.USD, currency.EUR, "0.9", older), rateFor(currency.EUR, currency.USD, "1.25", newer), nil, "0.8", true},
+		{"direct_fresher", true, rateFor(currency.USD, currency.EUR, "0.9", newer), rateFor(currency.EUR, currency.USD, "1.25", older), nil, "0.9", false},
+		{"same_time_prefers_direct", true, rateFor(currency.USD, currency.EUR, "0.9", older), rateFor(currency.EUR, currency.USD, "1.25", older), nil, "0.9", false},
+		{"disabled", false, nil, rateFor(currency.EUR, currency.USD, "1.25", older), currency.ErrCurrencyRateNotFound(), "", false},
+		{"none", true, nil, nil, currency.ErrCurrencyRateNotFound(), "", false},
+		{"precise_inverse", true, nil, rateFor(currency.EUR, currency.USD, "3", older), nil, "0.333333333333333333", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockRepo{
+				getActualFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, date *time.Time) (*currency.CurrencyRate, error) {
+					model := tt.direct
+					if base == currency.EUR {
+						model = tt.opposite
+					}
+					if model == nil {
+						return nil, currency.ErrCurrencyRateNotFound()
+					}
+					return model, nil
+				},
+			}
+			service := NewCurrencyService(repo, FreshnessConfig{}, NewRateCompletionHub())
+
+			res, err := service.GetActualRate(ctx, currency.USD, currency.EUR, ActualRateOptions{AllowInverse: tt.allowInverse})
+
+			if tt.expectErr != nil {
+				assert.Equal(t, tt.expectErr, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, currency.USD, res.BaseCurrency)
+			assert.Equal(t, currency.EUR, res.ResultCurrency)
+			assert.Equal(t, tt.expectRate, res.Rate.String())
+			assert.Equal(t, tt.inverted, res.Inverted)
+		})
+	}
+}
+
+func TestCurrencyService_GetActualRate_Freshness(t *testing.T) {
+	ctx := context.Background()
+	rate := decimal.RequireFromString("1.1")
+	hour := time.Hour
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		config    FreshnessConfig
+		opts      ActualRateOptions
+		age       time.Duration
+		expectErr string
+		stale     bool
+	}{
+		{"no_limit", FreshnessConfig{}, ActualRateOptions{}, 30 * 24 * time.Hour, "", false},
+		{"default_fresh", FreshnessConfig{DefaultMaxAge: time.Hour}, ActualRateOptions{}, time.Minute, "", false},
+		{"default_stale_allow", FreshnessConfig{DefaultMaxAge: time.Hour}, ActualRateOptions{}, 2 * time.Hour, "", true},
+		{"default_stale_error", FreshnessConfig{DefaultMaxAge: time.Hour}, ActualRateOptions{StalePolicy: currency.StalePolicyError}, 2 * time.Hour, "CurrencyRateStale", false},
+		{"pair_override", FreshnessConfig{DefaultMaxAge: time.Hour, MaxAgeByPair: map[string]time.Duration{"USDEUR": 3 * time.Hour}}, ActualRateOptions{StalePolicy: currency.StalePolicyError}, 2 * time.Hour, "", false},
+		{"client_max_age", FreshnessConfig{DefaultMaxAge: 3 * time.Hour}, ActualRateOptions{MaxAge: &hour, StalePolicy: currency.StalePolicyError}, 2 * time.Hour, "CurrencyRateStale", false},
+		{"historical_date_never_stale", FreshnessConfig{DefaultMaxAge: time.Hour}, ActualRateOptions{RateDate: &day, StalePolicy: currency.StalePolicyError}, 2 * time.Hour, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			completedAt := time.Now().Add(-tt.age)
+			repo := &mockRepo{
+				getActualFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, date *time.Time) (*currency.CurrencyRate, error) {
+					return &currency.CurrencyRate{BaseCurrency: base, ResultCurrency: result, Rate: &rate, CompletedAt: &completedAt, Status: currency.CurrencyRateStatusCompleted}, nil
+				},
+			}
+			service := NewCurrencyService(repo, tt.config, NewRateCompletionHub())
+
+			res, err := service.GetActualRate(ctx, currency.USD, currency.EUR, tt.opts)
+
+			if tt.expectErr != "" {
+				var customErr *error_utils.CustomError
+				if assert.ErrorAs(t, err, &customErr) {
+					assert.Equal(t, tt.expectErr, customErr.Code)
+				}
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.stale, res.Stale)
+		})
+	}
+}
+
 func TestCurrencyService_GetCompletedRateById(t *testing.T) {
 	ctx := context.Background()
-	rate := 2.5
+	rate := decimal.RequireFromString("2.5")
 	completedAt := testTime()
 
 	tests := []struct {
@@ -74,7 +235,8 @@ func TestCurrencyService_GetCompletedRateById(t *testing.T) {
 		{"completed", &currency.CurrencyRate{Status: currency.CurrencyRateStatusCompleted, Rate: &rate, CompletedAt: &completedAt}, nil, nil},
 		{"pending", &currency.CurrencyRate{Status: currency.CurrencyRateStatusPending}, nil, currency.ErrCurrencyRateNotCompletedYet()},
 		{"processing", &currency.CurrencyRate{Status: currency.CurrencyRateStatusProcessing}, nil, currency.ErrCurrencyRateNotCompletedYet()},
-		{"failed", &currency.CurrencyRate{Status: currency.CurrencyRateStatusFailed}, nil, currency.ErrCurrencyRateFetchFailed()},
+		{"failed", &currency.CurrencyRate{Status: currency.CurrencyRateStatusFailed}, nil, currency.ErrCurrencyRateFetchFailed(nil)},
+		{"failed_with_reason", &currency.CurrencyRate{Status: currency.CurrencyRateStatusFailed, Failure: &currency.RateFailure{Code: currency.RateFailureProviderTimeout, Message: "timeout"}}, nil, currency.ErrCurrencyRateFetchFailed(&currency.RateFailure{Code: currency.RateFailureProviderTimeout, Message: "timeout"})},
 		{"unknown_status", &currency.CurrencyRate{Status: "SOMETHING"}, nil, error_utils.ErrInternalServerError("inconsistent entity state")},
 		{"repo_error", nil, errors.New("query_error"), errors.New("query_error")},
 	}
@@ -90,7 +252,7 @@ func TestCurrencyService_GetCompletedRateById(t *testing.T) {
 				},
 			}
 
-			service := NewCurrencyService(repo)
+			service := NewCurrencyService(repo, FreshnessConfig{}, NewRateCompletionHub())
 			res, err := service.GetCompletedRateById(ctx, "id")
 
 			if tt.expectErr != nil {
@@ -108,13 +270,13 @@ func TestCurrencyService_CreateRate(t *testing.T) {
 	ctx := context.Background()
 
 	repo := &mockRepo{
-		createFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, idem string) (*currency.CurrencyRate, error) {
+		createFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, date *time.Time, clientId string, idem string, callbackUrl *string) (*currency.CurrencyRate, error) {
 			return &currency.CurrencyRate{Id: "qid", BaseCurrency: base, ResultCurrency: result, IdempotencyKey: idem, Status: currency.CurrencyRateStatusPending}, nil
 		},
 	}
 
-	service := NewCurrencyService(repo)
-	res, err := service.CreateRate(ctx, currency.USD, currency.MXN, "idem")
+	service := NewCurrencyService(repo, FreshnessConfig{}, NewRateCompletionHub())
+	res, err := service.CreateRate(ctx, currency.USD, currency.MXN, nil, "client-a", "idem", nil)
 
 	assert.Nil(t, err)
 	assert.Equal(t, "qid", res.Id)
@@ -123,6 +285,192 @@ func TestCurrencyService_CreateRate(t *testing.T) {
 	assert.Equal(t, currency.MXN, res.ResultCurrency)
 }
 
+func TestCurrencyService_CreateRate_CallbackUrl(t *testing.T) {
+	ctx := context.Background()
+
+	var stored *string
+	repo := &mockRepo{
+		createFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, date *time.Time, clientId string, idem string, callbackUrl *string) (*currency.CurrencyRate, error) {
+			stored = callbackUrl
+			return &currency.CurrencyRate{Id: "qid", CallbackUrl: callbackUrl}, nil
+		},
+	}
+
+	service := NewCurrencyService(repo, FreshnessConfig{}, NewRateCompletionHub())
+
+	for _, target := range []string{"http://127.0.0.1:8000/hook", "http://169.254.169.254/latest", "http://localhost/hook", "file:///etc/passwd"} {
+		_, err := service.CreateRate(ctx, currency.USD, currency.MXN, nil, "client-a", "idem", &target)
+
+		var customErr *error_utils.CustomError
+		if assert.ErrorAs(t, err, &customErr, target) {
+			assert.Equal(t, "InvalidCallbackUrl", customErr.Code)
+		}
+	}
+	assert.Nil(t, stored)
+
+	target := "https://hooks.example.com/rates"
+	_, err := service.CreateRate(ctx, currency.USD, currency.MXN, nil, "client-a", "idem", &target)
+
+	assert.Nil(t, err)
+	assert.Equal(t, &target, stored)
+}
+
 func testTime() (t time.Time) {
 	return time.Date(2024, 10, 2, 15, 4, 5, 0, time.UTC)
 }
+
+func TestCurrencyService_GetRateMatrix(t *testing.T) {
+	rate := decimal.RequireFromString("1.0845")
+	fresh := time.Now().Add(-time.Minute)
+	old := time.Now().Add(-2 * time.Hour)
+	var queried []currency.CurrencyCode
+
+	repo := &mockRepo{
+		getActualRatesFn: func(ctx context.Context, codes []currency.CurrencyCode, date *time.Time) ([]currency.CurrencyRate, error) {
+			queried = codes
+			return []currency.CurrencyRate{
+				{BaseCurrency: currency.EUR, ResultCurrency: currency.USD, Status: currency.CurrencyRateStatusCompleted, Rate: &rate, CompletedAt: &fresh},
+				{BaseCurrency: currency.USD, ResultCurrency: currency.MXN, Status: currency.CurrencyRateStatusCompleted, Rate: &rate, CompletedAt: &old},
+			}, nil
+		},
+	}
+	service := NewCurrencyService(repo, FreshnessConfig{DefaultMaxAge: time.Hour}, NewRateCompletionHub())
+	codes := []currency.CurrencyCode{currency.USD, currency.EUR, currency.MXN}
+
+	matrix, err := service.GetRateMatrix(context.Background(), codes, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, codes, queried, "all cells should come from one query")
+	assert.Len(t, matrix.Cells, 6)
+
+	cells := make(map[string]currency.RateMatrixCell)
+	for _, cell := range matrix.Cells {
+		cells[string(cell.BaseCurrency)+string(cell.ResultCurrency)] = cell
+	}
+
+	assert.NotNil(t, cells["EURUSD"].Rate)
+	assert.False(t, cells["EURUSD"].Rate.Stale)
+	assert.True(t, cells["USDMXN"].Rate.Stale)
+	assert.Nil(t, cells["USDEUR"].Rate, "missing pairs stay in the matrix")
+	assert.Equal(t, currency.USD, matrix.Cells[0].BaseCurrency)
+	assert.Equal(t, currency.EUR, matrix.Cells[0].ResultCurrency)
+}
+
+func TestCurrencyService_GetRateMatrix_InvalidCodes(t *testing.T) {
+	service := NewCurrencyService(&mockRepo{}, FreshnessConfig{}, NewRateCompletionHub())
+
+	cases := [][]currency.CurrencyCode{
+		{currency.USD},
+		{currency.USD, currency.USD},
+		{currency.USD, "ABC"},
+	}
+
+	for _, codes := range cases {
+		_, err := service.GetRateMatrix(context.Background(), codes, nil)
+
+		assert.Error(t, err, codes)
+	}
+}
+
+func TestCurrencyService_ListRates_NextCursor(t *testing.T) {
+	created := time.Date(2024, 10, 2, 15, 4, 5, 0, time.UTC)
+	var received currency.RateListQuery
+
+	repo := &mockRepo{
+		listRatesFn: func(ctx context.Context, query currency.RateListQuery) ([]currency.CurrencyRate, error) {
+			received = query
+			return []currency.CurrencyRate{
+				{Id: "3", CreatedAt: created.Add(2 * time.Second)},
+				{Id: "2", CreatedAt: created.Add(time.Second)},
+				{Id: "1", CreatedAt: created},
+			}, nil
+		},
+	}
+	service := NewCurrencyService(repo, FreshnessConfig{}, NewRateCompletionHub())
+
+	page, err := service.ListRates(context.Background(), currency.RateListQuery{Desc: true, Limit: 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, received.Limit, "one extra row should be asked for")
+	assert.Equal(t, currency.RateSortCreatedAt, received.SortField)
+	assert.Len(t, page.Items, 2)
+	assert.Equal(t, &currency.RateCursor{SortField: currency.RateSortCreatedAt, Desc: true, Value: created.Add(time.Second), Id: "2"}, page.Next)
+}
+
+func TestCurrencyService_ListRates_LastPage(t *testing.T) {
+	repo := &mockRepo{
+		listRatesFn: func(ctx context.Context, query currency.RateListQuery) ([]currency.CurrencyRate, error) {
+			return []currency.CurrencyRate{{Id: "1"}}, nil
+		},
+	}
+	service := NewCurrencyService(repo, FreshnessConfig{}, NewRateCompletionHub())
+
+	page, err := service.ListRates(context.Background(), currency.RateListQuery{})
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+	assert.Nil(t, page.Next)
+}
+
+func TestCurrencyService_ListRates_InvalidQuery(t *testing.T) {
+	service := NewCurrencyService(&mockRepo{}, FreshnessConfig{}, NewRateCompletionHub())
+
+	_, err := service.ListRates(context.Background(), currency.RateListQuery{Limit: currency.MaxRateListLimit + 1})
+	assert.Error(t, err)
+
+	_, err = service.ListRates(context.Background(), currency.RateListQuery{
+		SortField: currency.RateSortUpdatedAt,
+		Cursor:    &currency.RateCursor{SortField: currency.RateSortCreatedAt, Id: "1"},
+	})
+	assert.Equal(t, "InvalidRateCursor", err.(*error_utils.CustomError).Code)
+}
+
+func TestCurrencyService_CancelRate(t *testing.T) {
+	repo := &mockRepo{
+		cancelRateFn: func(ctx context.Context, id string) (*currency.CurrencyRate, error) {
+			return &currency.CurrencyRate{Id: id, Status: currency.CurrencyRateStatusCancelled}, nil
+		},
+	}
+	hub := NewRateCompletionHub()
+	waiter, unsubscribe := hub.Subscribe("1")
+	defer unsubscribe()
+
+	service := NewCurrencyService(repo, FreshnessConfig{}, hub)
+
+	rate, err := service.CancelRate(context.Background(), "1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, currency.CurrencyRateStatusCancelled, rate.Status)
+
+	select {
+	case <-waiter:
+	default:
+		t.Fatal("waiters of the cancelled rate should be notified")
+	}
+}
+
+func TestCurrencyService_CancelRate_NotPending(t *testing.T) {
+	repo := &mockRepo{
+		cancelRateFn: func(ctx context.Context, id string) (*currency.CurrencyRate, error) {
+			return nil, currency.ErrCurrencyRateNotCancellable(currency.CurrencyRateStatusProcessing)
+		},
+	}
+	service := NewCurrencyService(repo, FreshnessConfig{}, NewRateCompletionHub())
+
+	_, err := service.CancelRate(context.Background(), "1")
+
+	assert.Equal(t, error_utils.ErrorCodeConflict, err.(*error_utils.CustomError).ErrorType)
+}
+
+func TestCurrencyService_GetCompletedRateById_Cancelled(t *testing.T) {
+	repo := &mockRepo{
+		getByIdFn: func(ctx context.Context, id string) (*currency.CurrencyRate, error) {
+			return &currency.CurrencyRate{Id: id, Status: currency.CurrencyRateStatusCancelled}, nil
+		},
+	}
+	service := NewCurrencyService(repo, FreshnessConfig{}, NewRateCompletionHub())
+
+	_, err := service.GetCompletedRateById(context.Background(), "1")
+
+	assert.Equal(t, "CurrencyRateCancelled", err.(*error_utils.CustomError).Code)
+}
@@ -0,0 +1,34 @@
+package application
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"currency-rate-app/internal/common/idempotency"
+)
+
+// IdempotencyCleanupService deletes expired idempotency keys, including keys
+// left in flight past their lock timeout.
+type IdempotencyCleanupService struct {
+	store idempotency.Store
+	now   func() time.Time
+}
+
+func NewIdempotencyCleanupService(store idempotency.Store) *IdempotencyCleanupService {
+	return &IdempotencyCleanupService{store: store, now: time.Now}
+}
+
+func (s *IdempotencyCleanupService) DeleteExpired(ctx context.Context, batchSize int) {
+	deleted, err := s.store.DeleteExpired(ctx, s.now(), batchSize)
+
+	if err != nil {
+		slog.ErrorContext(ctx, "Idempotency keys cleanup failed", slog.String("error", err.Error()))
+
+		return
+	}
+
+	if deleted > 0 {
+		slog.DebugContext(ctx, "Expired idempotency keys deleted", slog.Int64("count", deleted))
+	}
+}
@@ -0,0 +1,106 @@
+package application
+
+import (
+	"context"
+
+	"currency-rate-app/internal/domains/currency"
+	"currency-rate-app/internal/domains/pricing"
+	"currency-rate-app/internal/infrastructure/db"
+)
+
+type PricingService interface {
+	PriceRate(ctx context.Context, rate currency.CurrencyRate) (*pricing.PricedRate, error)
+	ListSpreads(ctx context.Context) ([]pricing.Spread, error)
+	SetSpread(ctx context.Context, scope string, bidBps int, askBps int, actor string) (*pricing.Spread, error)
+	DeleteSpread(ctx context.Context, scope string, actor string) error
+	GetSpreadHistory(ctx context.Context, scope string) ([]pricing.SpreadChange, error)
+}
+
+type pricingServiceImpl struct {
+	repo          db.SpreadRepository
+	defaultSpread pricing.Spread
+}
+
+// NewPricingService prices rates with spreads stored through the admin API.
+// Pairs without a matching rule get the configured default spread.
+func NewPricingService(
+	repo db.SpreadRepository,
+	defaultBidBps int,
+	defaultAskBps int,
+) PricingService {
+	return &pricingServiceImpl{
+		repo: repo,
+		defaultSpread: pricing.Spread{
+			Scope:  pricing.DefaultScope,
+			BidBps: defaultBidBps,
+			AskBps: defaultAskBps,
+		},
+	}
+}
+
+func (s *pricingServiceImpl) PriceRate(ctx context.Context, rate currency.CurrencyRate) (*pricing.PricedRate, error) {
+	scopes := pricing.ScopesFor(rate.BaseCurrency, rate.ResultCurrency)
+
+	spreads, err := s.repo.GetSpreadsByScopes(ctx, scopes)
+
+	if err != nil {
+		return nil, err
+	}
+
+	byScope := make(map[string]pricing.Spread, len(spreads))
+	for _, spread := range spreads {
+		byScope[spread.Scope] = spread
+	}
+
+	spread := s.defaultSpread
+
+	for _, scope := range scopes {
+		if found, ok := byScope[scope]; ok {
+			spread = found
+
+			break
+		}
+	}
+
+	priced := pricing.Apply(*rate.Rate, spread)
+
+	return &priced, nil
+}
+
+func (s *pricingServiceImpl) ListSpreads(ctx context.Context) ([]pricing.Spread, error) {
+	return s.repo.ListSpreads(ctx)
+}
+
+func (s *pricingServiceImpl) SetSpread(
+	ctx context.Context,
+	scope string,
+	bidBps int,
+	askBps int,
+	actor string,
+) (*pricing.Spread, error) {
+	if err := pricing.ValidateScope(scope); err != nil {
+		return nil, err
+	}
+
+	if err := pricing.ValidateSpread(bidBps, askBps); err != nil {
+		return nil, err
+	}
+
+	return s.repo.SetSpread(ctx, scope, bidBps, askBps, actor)
+}
+
+func (s *pricingServiceImpl) DeleteSpread(ctx context.Context, scope string, actor string) error {
+	if err := pricing.ValidateScope(scope); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteSpread(ctx, scope, actor)
+}
+
+func (s *pricingServiceImpl) GetSpreadHistory(ctx context.Context, scope string) ([]pricing.SpreadChange, error) {
+	if err := pricing.ValidateScope(scope); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetSpreadHistory(ctx, scope)
+}
@@ -0,0 +1,96 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"currency-rate-app/internal/domains/currency"
+	"currency-rate-app/internal/domains/pricing"
+	"currency-rate-app/internal/infrastructure/db"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSpreadRepository struct {
+	db.SpreadRepository
+	getByScopesFn func(ctx context.Context, scopes []string) ([]pricing.Spread, error)
+	setFn         func(ctx context.Context, scope string, bidBps int, askBps int, actor string) (*pricing.Spread, error)
+}
+
+func (m *mockSpreadRepository) GetSpreadsByScopes(ctx context.Context, scopes []string) ([]pricing.Spread, error) {
+	return m.getByScopesFn(ctx, scopes)
+}
+
+func (m *mockSpreadRepository) SetSpread(ctx context.Context, scope string, bidBps int, askBps int, actor string) (*pricing.Spread, error) {
+	return m.setFn(ctx, scope, bidBps, askBps, actor)
+}
+
+func pricedRate(t *testing.T, spreads []pricing.Spread) *pricing.PricedRate {
+	t.Helper()
+
+	var requested []string
+	repo := &mockSpreadRepository{
+		getByScopesFn: func(ctx context.Context, scopes []string) ([]pricing.Spread, error) {
+			requested = scopes
+			return spreads, nil
+		},
+	}
+	svc := NewPricingService(repo, 10, 20)
+
+	mid := decimal.RequireFromString("2")
+	res, err := svc.PriceRate(context.Background(), currency.CurrencyRate{BaseCurrency: currency.EUR, ResultCurrency: currency.USD, Rate: &mid})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"EURUSD", "USD", "EUR"}, requested)
+
+	return res
+}
+
+func TestPriceRate_DefaultSpread(t *testing.T) {
+	res := pricedRate(t, nil)
+
+	assert.Equal(t, pricing.DefaultScope, res.Spread.Scope)
+	assert.Equal(t, "2", res.Mid.String())
+	assert.Equal(t, "1.998", res.Bid.String())
+	assert.Equal(t, "2.004", res.Ask.String())
+}
+
+func TestPriceRate_PairWinsOverCurrency(t *testing.T) {
+	res := pricedRate(t, []pricing.Spread{
+		{Scope: "USD", BidBps: 100, AskBps: 100},
+		{Scope: "EURUSD", BidBps: 50, AskBps: 0},
+	})
+
+	assert.Equal(t, "EURUSD", res.Spread.Scope)
+	assert.Equal(t, "1.99", res.Bid.String())
+	assert.Equal(t, "2", res.Ask.String())
+}
+
+func TestPriceRate_ResultCurrencyWinsOverBase(t *testing.T) {
+	res := pricedRate(t, []pricing.Spread{
+		{Scope: "EUR", BidBps: 1, AskBps: 1},
+		{Scope: "USD", BidBps: 100, AskBps: 100},
+	})
+
+	assert.Equal(t, "USD", res.Spread.Scope)
+}
+
+func TestSetSpread_Validation(t *testing.T) {
+	repo := &mockSpreadRepository{
+		setFn: func(ctx context.Context, scope string, bidBps int, askBps int, actor string) (*pricing.Spread, error) {
+			return &pricing.Spread{Scope: scope, BidBps: bidBps, AskBps: askBps, UpdatedBy: actor}, nil
+		},
+	}
+	svc := NewPricingService(repo, 0, 0)
+
+	_, err := svc.SetSpread(context.Background(), "EURAAA", 10, 10, "alice")
+	assert.Equal(t, pricing.ErrInvalidSpreadScope(), err)
+
+	_, err = svc.SetSpread(context.Background(), "EURUSD", 10000, 10, "alice")
+	assert.Equal(t, pricing.ErrInvalidSpread(), err)
+
+	res, err := svc.SetSpread(context.Background(), "EURUSD", 10, 15, "alice")
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", res.UpdatedBy)
+}
@@ -2,64 +2,184 @@ package application
 
 import (
 	"context"
+	"net/http"
+	"sync"
 	"testing"
 	"time"
 
 	"currency-rate-app/internal/domains/currency"
+	rates_api "currency-rate-app/internal/infrastructure/http/rates-api"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 )
 
 type mockCurrencyRepository struct {
 	fetchAndMarkForProcessingFunc func(ctx context.Context, limit int) ([]currency.CurrencyRate, error)
-	updateRateStatusByIdsFunc     func(ctx context.Context, ids []string, status currency.CurrencyRateStatus) error
-	saveRatesByIdsFunc            func(ctx context.Context, ids []string, rate float64) error
+	failRatesByIdsFunc            func(ctx context.Context, ids []string, failure currency.RateFailure) error
+	saveRatesByIdsFunc            func(ctx context.Context, ids []string, quote currency.RateQuote) error
+	retryRatesByIdsFunc           func(ctx context.Context, ids []string, nextAttemptAt time.Time, failure currency.RateFailure) error
+	reclaimExpiredRatesFunc       func(ctx context.Context, startedBefore time.Time, maxAttempts int, limit int) ([]currency.CurrencyRate, error)
+	getRateByIdFunc               func(ctx context.Context, id string) (*currency.CurrencyRate, error)
+	// heldLease is the lease the rates are held by, updates under any other
+	// lease match no rates.
+	heldLease *currency.RateLease
 }
 
-func (m *mockCurrencyRepository) GetActualRateByCurrency(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode) (*currency.CurrencyRate, error) {
+func (m *mockCurrencyRepository) leaseLost(lease currency.RateLease) bool {
+	return m.heldLease != nil && *m.heldLease != lease
+}
+
+func (m *mockCurrencyRepository) GetActualRateByCurrency(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, rateDate *time.Time) (*currency.CurrencyRate, error) {
 	return nil, nil
 }
 
 func (m *mockCurrencyRepository) GetRateById(ctx context.Context, id string) (*currency.CurrencyRate, error) {
+	if m.getRateByIdFunc != nil {
+		return m.getRateByIdFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *mockCurrencyRepository) GetActualRatesByCurrencies(ctx context.Context, codes []currency.CurrencyCode, rateDate *time.Time) ([]currency.CurrencyRate, error) {
+	return nil, nil
+}
+
+func (m *mockCurrencyRepository) CancelRate(ctx context.Context, id string) (*currency.CurrencyRate, error) {
 	return nil, nil
 }
 
-func (m *mockCurrencyRepository) CreateRate(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, idempotencyKey string) (*currency.CurrencyRate, error) {
+func (m *mockCurrencyRepository) ListRates(ctx context.Context, query currency.RateListQuery) ([]currency.CurrencyRate, error) {
 	return nil, nil
 }
 
-func (m *mockCurrencyRepository) UpdateRateStatusByIds(ctx context.Context, ids []string, status currency.CurrencyRateStatus) error {
-	if m.updateRateStatusByIdsFunc != nil {
-		return m.updateRateStatusByIdsFunc(ctx, ids, status)
+func (m *mockCurrencyRepository) CreateRate(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, rateDate *time.Time, clientId string, idempotencyKey string, callbackUrl *string) (*currency.CurrencyRate, error) {
+	return nil, nil
+}
+
+func (m *mockCurrencyRepository) FailRatesByIds(ctx context.Context, lease currency.RateLease, ids []string, failure currency.RateFailure) (int64, error) {
+	if m.leaseLost(lease) {
+		return 0, nil
 	}
-	return nil
+	if m.failRatesByIdsFunc != nil {
+		if err := m.failRatesByIdsFunc(ctx, ids, failure); err != nil {
+			return 0, err
+		}
+	}
+	return int64(len(ids)), nil
 }
 
-func (m *mockCurrencyRepository) SaveRatesByIds(ctx context.Context, ids []string, rate float64) error {
+func (m *mockCurrencyRepository) SaveRatesByIds(ctx context.Context, lease currency.RateLease, ids []string, quote currency.RateQuote) (int64, error) {
+	if m.leaseLost(lease) {
+		return 0, nil
+	}
 	if m.saveRatesByIdsFunc != nil {
-		return m.saveRatesByIdsFunc(ctx, ids, rate)
+		if err := m.saveRatesByIdsFunc(ctx, ids, quote); err != nil {
+			return 0, err
+		}
 	}
-	return nil
+	return int64(len(ids)), nil
+}
+
+func (m *mockCurrencyRepository) RetryRatesByIds(ctx context.Context, lease currency.RateLease, ids []string, nextAttemptAt time.Time, failure currency.RateFailure) (int64, error) {
+	if m.leaseLost(lease) {
+		return 0, nil
+	}
+	if m.retryRatesByIdsFunc != nil {
+		if err := m.retryRatesByIdsFunc(ctx, ids, nextAttemptAt, failure); err != nil {
+			return 0, err
+		}
+	}
+	return int64(len(ids)), nil
 }
 
-func (m *mockCurrencyRepository) FetchAndMarkForProcessing(ctx context.Context, limit int) ([]currency.CurrencyRate, error) {
+func (m *mockCurrencyRepository) FetchAndMarkForProcessing(ctx context.Context, workerId string, limit int) ([]currency.CurrencyRate, error) {
 	if m.fetchAndMarkForProcessingFunc != nil {
 		return m.fetchAndMarkForProcessingFunc(ctx, limit)
 	}
 	return nil, nil
 }
 
+func (m *mockCurrencyRepository) ReclaimExpiredRates(ctx context.Context, startedBefore time.Time, maxAttempts int, limit int) ([]currency.CurrencyRate, error) {
+	if m.reclaimExpiredRatesFunc != nil {
+		return m.reclaimExpiredRatesFunc(ctx, startedBefore, maxAttempts, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockCurrencyRepository) GetRateHistory(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, from time.Time, to time.Time) ([]currency.TimeSeriesPoint, error) {
+	return nil, nil
+}
+
+func (m *mockCurrencyRepository) SaveRateHistory(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, points []currency.TimeSeriesPoint) error {
+	return nil
+}
+
+func (m *mockCurrencyRepository) CreateRateBatch(ctx context.Context, clientId string, idempotencyKey string, requestHash string, items []currency.RateBatchItem) (*currency.RateBatch, error) {
+	return nil, nil
+}
+
+func (m *mockCurrencyRepository) GetRateBatchById(ctx context.Context, id string) (*currency.RateBatch, error) {
+	return nil, nil
+}
+
+func (m *mockCurrencyRepository) RequeueRate(ctx context.Context, id string, actor string) (*currency.CurrencyRate, error) {
+	return nil, nil
+}
+
+func (m *mockCurrencyRepository) RequeueFailedRates(ctx context.Context, filter currency.FailedRateFilter, limit int, actor string) ([]currency.CurrencyRate, error) {
+	return nil, nil
+}
+
+func (m *mockCurrencyRepository) ArchiveRate(ctx context.Context, id string, actor string) (*currency.CurrencyRate, error) {
+	return nil, nil
+}
+
+func (m *mockCurrencyRepository) GetRateAdminActions(ctx context.Context, id string) ([]currency.RateAdminAction, error) {
+	return nil, nil
+}
+
 type mockRateService struct {
-	fetchDataFunc func(baseCurrency currency.CurrencyCode) (map[string]float64, error)
+	fetchDataFunc       func(baseCurrency currency.CurrencyCode, date *time.Time) (map[string]decimal.Decimal, error)
+	fetchTimeSeriesFunc func(baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, from time.Time, to time.Time) (map[string]decimal.Decimal, error)
+}
+
+func (m *mockRateService) Name() string {
+	return "mock"
 }
 
-func (m *mockRateService) FetchData(baseCurrency currency.CurrencyCode) (map[string]float64, error) {
+func (m *mockRateService) FetchData(baseCurrency currency.CurrencyCode, date *time.Time, wanted ...currency.CurrencyCode) (rates_api.Rates, error) {
 	if m.fetchDataFunc != nil {
-		return m.fetchDataFunc(baseCurrency)
+		rates, err := m.fetchDataFunc(baseCurrency, date)
+		if rates == nil {
+			return nil, err
+		}
+		return rates_api.NewRates(m.Name(), rates), err
 	}
 	return nil, nil
 }
 
+func (m *mockRateService) FetchTimeSeries(baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, from time.Time, to time.Time) (*rates_api.TimeSeries, error) {
+	if m.fetchTimeSeriesFunc != nil {
+		series, err := m.fetchTimeSeriesFunc(baseCurrency, resultCurrency, from, to)
+		if err != nil {
+			return nil, err
+		}
+		return &rates_api.TimeSeries{Provider: m.Name(), Rates: series}, nil
+	}
+	return &rates_api.TimeSeries{Provider: m.Name()}, nil
+}
+
+type mockAlertEvaluator struct {
+	evaluateRateFunc func(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, rate decimal.Decimal)
+}
+
+func (m *mockAlertEvaluator) EvaluateRate(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, rate decimal.Decimal) {
+	if m.evaluateRateFunc != nil {
+		m.evaluateRateFunc(ctx, baseCurrency, resultCurrency, rate)
+	}
+}
+
 func TestProcessRates_SuccessfulProcessing(t *testing.T) {
 	now := time.Now()
 	testRates := []currency.CurrencyRate{
@@ -83,45 +203,55 @@ func TestProcessRates_SuccessfulProcessing(t *testing.T) {
 
 	var savedRates []struct {
 		ids  []string
-		rate float64
+		rate decimal.Decimal
 	}
 
 	repo := &mockCurrencyRepository{
 		fetchAndMarkForProcessingFunc: func(ctx context.Context, limit int) ([]currency.CurrencyRate, error) {
 			return testRates, nil
 		},
-		saveRatesByIdsFunc: func(ctx context.Context, ids []string, rate float64) error {
+		saveRatesByIdsFunc: func(ctx context.Context, ids []string, quote currency.RateQuote) error {
 			savedRates = append(savedRates, struct {
 				ids  []string
-				rate float64
-			}{ids: ids, rate: rate})
+				rate decimal.Decimal
+			}{ids: ids, rate: quote.Rate})
 			return nil
 		},
 	}
 
 	rateService := &mockRateService{
-		fetchDataFunc: func(baseCurrency currency.CurrencyCode) (map[string]float64, error) {
-			return map[string]float64{
-				"EUR": 0.85,
-				"MXN": 20.5,
+		fetchDataFunc: func(baseCurrency currency.CurrencyCode, date *time.Time) (map[string]decimal.Decimal, error) {
+			return map[string]decimal.Decimal{
+				"EUR": decimal.RequireFromString("0.85"),
+				"MXN": decimal.RequireFromString("20.5"),
 			}, nil
 		},
 	}
 
-	service := NewProcessRatesService(repo, rateService)
+	hub := NewRateCompletionHub()
+	completed, unsubscribe := hub.Subscribe("1")
+	defer unsubscribe()
+
+	service := NewProcessRatesService(repo, rateService, &mockAlertEvaluator{}, hub, ProcessRatesConfig{})
 	ctx := context.Background()
 
 	service.ProcessRates(ctx, 10)
 
+	select {
+	case <-completed:
+	default:
+		t.Fatal("waiters of saved rates should be notified")
+	}
+
 	expected := []struct {
 		ids  []string
-		rate float64
+		rate decimal.Decimal
 	}{
-		{ids: []string{"1"}, rate: 0.85},
-		{ids: []string{"2"}, rate: 20.5},
+		{ids: []string{"1"}, rate: decimal.RequireFromString("0.85")},
+		{ids: []string{"2"}, rate: decimal.RequireFromString("20.5")},
 	}
 
-	assert.Equal(t, expected, savedRates, "updated entities do not match")
+	assert.ElementsMatch(t, expected, savedRates, "updated entities do not match")
 }
 
 func TestProcessRates_CurrencyPairNotFound(t *testing.T) {
@@ -143,21 +273,21 @@ func TestProcessRates_CurrencyPairNotFound(t *testing.T) {
 		fetchAndMarkForProcessingFunc: func(ctx context.Context, limit int) ([]currency.CurrencyRate, error) {
 			return testRates, nil
 		},
-		updateRateStatusByIdsFunc: func(ctx context.Context, ids []string, status currency.CurrencyRateStatus) error {
+		failRatesByIdsFunc: func(ctx context.Context, ids []string, failure currency.RateFailure) error {
 			failedIds = append(failedIds, ids...)
 			return nil
 		},
 	}
 
 	rateService := &mockRateService{
-		fetchDataFunc: func(baseCurrency currency.CurrencyCode) (map[string]float64, error) {
-			return map[string]float64{
-				"MXN": 20.5,
+		fetchDataFunc: func(baseCurrency currency.CurrencyCode, date *time.Time) (map[string]decimal.Decimal, error) {
+			return map[string]decimal.Decimal{
+				"MXN": decimal.RequireFromString("20.5"),
 			}, nil
 		},
 	}
 
-	service := NewProcessRatesService(repo, rateService)
+	service := NewProcessRatesService(repo, rateService, &mockAlertEvaluator{}, NewRateCompletionHub(), ProcessRatesConfig{})
 	ctx := context.Background()
 
 	service.ProcessRates(ctx, 10)
@@ -167,6 +297,306 @@ func TestProcessRates_CurrencyPairNotFound(t *testing.T) {
 	assert.Equal(t, expected, failedIds, "failed entities ids do not match")
 }
 
+func TestProcessRates_FetchErrorRetriesWithBackoff(t *testing.T) {
+	testRates := []currency.CurrencyRate{
+		{Id: "1", BaseCurrency: currency.USD, ResultCurrency: currency.EUR, Status: currency.CurrencyRateStatusProcessing, Attempts: 1},
+		{Id: "2", BaseCurrency: currency.USD, ResultCurrency: currency.MXN, Status: currency.CurrencyRateStatusProcessing, Attempts: 3},
+	}
+
+	var mu sync.Mutex
+	retries := make(map[string]time.Time)
+	var failures []currency.RateFailure
+	var failedIds []string
+
+	repo := &mockCurrencyRepository{
+		fetchAndMarkForProcessingFunc: func(ctx context.Context, limit int) ([]currency.CurrencyRate, error) {
+			return testRates, nil
+		},
+		retryRatesByIdsFunc: func(ctx context.Context, ids []string, nextAttemptAt time.Time, failure currency.RateFailure) error {
+			mu.Lock()
+			defer mu.Unlock()
+			for _, id := range ids {
+				retries[id] = nextAttemptAt
+			}
+			failures = append(failures, failure)
+			return nil
+		},
+		failRatesByIdsFunc: func(ctx context.Context, ids []string, failure currency.RateFailure) error {
+			failedIds = append(failedIds, ids...)
+			return nil
+		},
+	}
+
+	rateService := &mockRateService{
+		fetchDataFunc: func(baseCurrency currency.CurrencyCode, date *time.Time) (map[string]decimal.Decimal, error) {
+			return nil, &rates_api.StatusError{Provider: "frankfurter", StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable"}
+		},
+	}
+
+	service := NewProcessRatesService(repo, rateService, &mockAlertEvaluator{}, NewRateCompletionHub(), ProcessRatesConfig{
+		MaxAttempts:    5,
+		InitialBackoff: 10 * time.Second,
+		MaxBackoff:     time.Minute,
+	})
+
+	start := time.Now()
+	service.ProcessRates(context.Background(), 10)
+
+	assert.Empty(t, failedIds)
+	assert.Len(t, retries, 2)
+	assert.Equal(t, currency.RateFailureProviderError, failures[0].Code)
+	assert.WithinDuration(t, start.Add(10*time.Second), retries["1"], time.Second)
+	assert.WithinDuration(t, start.Add(40*time.Second), retries["2"], time.Second)
+}
+
+func TestProcessRates_PartialRatesRetryMissingPairs(t *testing.T) {
+	testRates := []currency.CurrencyRate{
+		{Id: "1", BaseCurrency: currency.USD, ResultCurrency: currency.EUR, Status: currency.CurrencyRateStatusProcessing, Attempts: 1},
+		{Id: "2", BaseCurrency: currency.USD, ResultCurrency: "THB", Status: currency.CurrencyRateStatusProcessing, Attempts: 1},
+	}
+
+	var savedIds, retriedIds, failedIds []string
+	var failure currency.RateFailure
+
+	repo := &mockCurrencyRepository{
+		fetchAndMarkForProcessingFunc: func(ctx context.Context, limit int) ([]currency.CurrencyRate, error) {
+			return testRates, nil
+		},
+		saveRatesByIdsFunc: func(ctx context.Context, ids []string, quote currency.RateQuote) error {
+			savedIds = append(savedIds, ids...)
+			return nil
+		},
+		retryRatesByIdsFunc: func(ctx context.Context, ids []string, nextAttemptAt time.Time, f currency.RateFailure) error {
+			retriedIds = append(retriedIds, ids...)
+			failure = f
+			return nil
+		},
+		failRatesByIdsFunc: func(ctx context.Context, ids []string, f currency.RateFailure) error {
+			failedIds = append(failedIds, ids...)
+			return nil
+		},
+	}
+
+	rateService := &mockRateService{
+		fetchDataFunc: func(baseCurrency currency.CurrencyCode, date *time.Time) (map[string]decimal.Decimal, error) {
+			return map[string]decimal.Decimal{"EUR": decimal.RequireFromString("0.9")}, &rates_api.PartialRatesError{
+				Err: &rates_api.StatusError{Provider: "secondary", StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable"},
+			}
+		},
+	}
+
+	service := NewProcessRatesService(repo, rateService, &mockAlertEvaluator{}, NewRateCompletionHub(), ProcessRatesConfig{
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+	})
+
+	service.ProcessRates(context.Background(), 10)
+
+	assert.Equal(t, []string{"1"}, savedIds)
+	assert.Equal(t, []string{"2"}, retriedIds)
+	assert.Empty(t, failedIds)
+	assert.Equal(t, currency.RateFailureProviderError, failure.Code)
+}
+
+func TestProcessRates_PivotTimeoutIsNotPairNotSupported(t *testing.T) {
+	testRates := []currency.CurrencyRate{
+		{Id: "1", BaseCurrency: currency.USD, ResultCurrency: currency.MXN, Status: currency.CurrencyRateStatusProcessing, Attempts: 1},
+	}
+
+	var failure currency.RateFailure
+
+	repo := &mockCurrencyRepository{
+		fetchAndMarkForProcessingFunc: func(ctx context.Context, limit int) ([]currency.CurrencyRate, error) {
+			return testRates, nil
+		},
+		failRatesByIdsFunc: func(ctx context.Context, ids []string, f currency.RateFailure) error {
+			failure = f
+			return nil
+		},
+	}
+
+	rateService := &mockRateService{
+		fetchDataFunc: func(baseCurrency currency.CurrencyCode, date *time.Time) (map[string]decimal.Decimal, error) {
+			if baseCurrency == currency.EUR {
+				return nil, context.DeadlineExceeded
+			}
+			return map[string]decimal.Decimal{"EUR": decimal.RequireFromString("0.9")}, nil
+		},
+	}
+
+	service := NewProcessRatesService(repo, rateService, &mockAlertEvaluator{}, NewRateCompletionHub(), ProcessRatesConfig{PivotCurrency: currency.EUR})
+	service.ProcessRates(context.Background(), 10)
+
+	assert.Equal(t, currency.RateFailureProviderTimeout, failure.Code)
+	assert.False(t, failure.Code.IsPermanent())
+}
+
+func TestProcessRates_FailsAfterMaxAttempts(t *testing.T) {
+	testRates := []currency.CurrencyRate{
+		{Id: "1", BaseCurrency: currency.USD, ResultCurrency: currency.EUR, Status: currency.CurrencyRateStatusProcessing, Attempts: 3},
+		{Id: "2", BaseCurrency: currency.USD, ResultCurrency: currency.EUR, Status: currency.CurrencyRateStatusProcessing, Attempts: 1},
+	}
+
+	var retriedIds, failedIds []string
+
+	repo := &mockCurrencyRepository{
+		fetchAndMarkForProcessingFunc: func(ctx context.Context, limit int) ([]currency.CurrencyRate, error) {
+			return testRates, nil
+		},
+		retryRatesByIdsFunc: func(ctx context.Context, ids []string, nextAttemptAt time.Time, failure currency.RateFailure) error {
+			retriedIds = append(retriedIds, ids...)
+			return nil
+		},
+		failRatesByIdsFunc: func(ctx context.Context, ids []string, failure currency.RateFailure) error {
+			assert.Equal(t, currency.RateFailureProviderError, failure.Code)
+			failedIds = append(failedIds, ids...)
+			return nil
+		},
+	}
+
+	rateService := &mockRateService{
+		fetchDataFunc: func(baseCurrency currency.CurrencyCode, date *time.Time) (map[string]decimal.Decimal, error) {
+			return nil, &rates_api.StatusError{Provider: "frankfurter", StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable"}
+		},
+	}
+
+	service := NewProcessRatesService(repo, rateService, &mockAlertEvaluator{}, NewRateCompletionHub(), ProcessRatesConfig{
+		MaxAttempts:    3,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+	})
+
+	service.ProcessRates(context.Background(), 10)
+
+	assert.Equal(t, []string{"2"}, retriedIds)
+	assert.Equal(t, []string{"1"}, failedIds)
+}
+
+func TestProcessRates_PermanentFailureFailsImmediately(t *testing.T) {
+	testRates := []currency.CurrencyRate{
+		{Id: "1", BaseCurrency: currency.USD, ResultCurrency: currency.EUR, Status: currency.CurrencyRateStatusProcessing, Attempts: 1},
+		{Id: "2", BaseCurrency: "XAU", ResultCurrency: currency.EUR, Status: currency.CurrencyRateStatusProcessing, Attempts: 1},
+	}
+
+	var mu sync.Mutex
+	var retriedIds []string
+	failures := make(map[string]currency.RateFailureCode)
+
+	repo := &mockCurrencyRepository{
+		fetchAndMarkForProcessingFunc: func(ctx context.Context, limit int) ([]currency.CurrencyRate, error) {
+			return testRates, nil
+		},
+		retryRatesByIdsFunc: func(ctx context.Context, ids []string, nextAttemptAt time.Time, failure currency.RateFailure) error {
+			mu.Lock()
+			defer mu.Unlock()
+			retriedIds = append(retriedIds, ids...)
+			return nil
+		},
+		failRatesByIdsFunc: func(ctx context.Context, ids []string, failure currency.RateFailure) error {
+			mu.Lock()
+			defer mu.Unlock()
+			for _, id := range ids {
+				failures[id] = failure.Code
+			}
+			return nil
+		},
+	}
+
+	rateService := &mockRateService{
+		fetchDataFunc: func(baseCurrency currency.CurrencyCode, date *time.Time) (map[string]decimal.Decimal, error) {
+			if baseCurrency == "XAU" {
+				return nil, &rates_api.StatusError{Provider: "frankfurter", StatusCode: http.StatusNotFound, Status: "404 Not Found"}
+			}
+			return map[string]decimal.Decimal{"MXN": decimal.RequireFromString("20.5")}, nil
+		},
+	}
+
+	service := NewProcessRatesService(repo, rateService, &mockAlertEvaluator{}, NewRateCompletionHub(), ProcessRatesConfig{
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+	})
+
+	service.ProcessRates(context.Background(), 10)
+
+	assert.Equal(t, []string{"1"}, retriedIds)
+	assert.Equal(t, map[string]currency.RateFailureCode{
+		"2": currency.RateFailureProviderRejected,
+	}, failures)
+}
+
+func TestProcessRates_Triangulation(t *testing.T) {
+	now := time.Now()
+	testRates := []currency.CurrencyRate{
+		{Id: "1", BaseCurrency: currency.USD, ResultCurrency: currency.MXN, Status: currency.CurrencyRateStatusProcessing, CreatedAt: now},
+		{Id: "2", BaseCurrency: currency.USD, ResultCurrency: "THB", Status: currency.CurrencyRateStatusProcessing, CreatedAt: now},
+		{Id: "3", BaseCurrency: currency.USD, ResultCurrency: "JPY", Status: currency.CurrencyRateStatusProcessing, CreatedAt: now},
+		{Id: "4", BaseCurrency: currency.USD, ResultCurrency: "GBP", Status: currency.CurrencyRateStatusProcessing, CreatedAt: now},
+	}
+
+	saved := map[string]currency.RateQuote{}
+	var failedIds []string
+
+	repo := &mockCurrencyRepository{
+		fetchAndMarkForProcessingFunc: func(ctx context.Context, limit int) ([]currency.CurrencyRate, error) {
+			return testRates, nil
+		},
+		saveRatesByIdsFunc: func(ctx context.Context, ids []string, quote currency.RateQuote) error {
+			saved[ids[0]] = quote
+			return nil
+		},
+		failRatesByIdsFunc: func(ctx context.Context, ids []string, failure currency.RateFailure) error {
+			failedIds = append(failedIds, ids...)
+			return nil
+		},
+	}
+
+	var fetched []currency.CurrencyCode
+	rateService := &mockRateService{
+		fetchDataFunc: func(baseCurrency currency.CurrencyCode, date *time.Time) (map[string]decimal.Decimal, error) {
+			fetched = append(fetched, baseCurrency)
+
+			switch baseCurrency {
+			case currency.USD:
+				return map[string]decimal.Decimal{
+					"MXN": decimal.RequireFromString("18.5"),
+					"JPY": decimal.RequireFromString("150"),
+				}, nil
+			case currency.EUR:
+				return map[string]decimal.Decimal{
+					"USD": decimal.RequireFromString("1.25"),
+					"THB": decimal.RequireFromString("40"),
+				}, nil
+			}
+
+			return nil, nil
+		},
+	}
+
+	service := NewProcessRatesService(repo, rateService, &mockAlertEvaluator{}, NewRateCompletionHub(), ProcessRatesConfig{PivotCurrency: currency.EUR})
+
+	service.ProcessRates(context.Background(), 10)
+
+	assert.Equal(t, []currency.CurrencyCode{currency.USD, currency.EUR}, fetched, "pivot should be fetched once")
+
+	assert.Equal(t, "18.5", saved["1"].Rate.String())
+	assert.Nil(t, saved["1"].PivotCurrency)
+	assert.Equal(t, "mock", saved["1"].Provider)
+
+	assert.Equal(t, "150", saved["3"].Rate.String())
+	assert.Nil(t, saved["3"].PivotCurrency)
+
+	// USD->EUR is 1/1.25, then EUR->THB is 40
+	assert.Equal(t, "32", saved["2"].Rate.String())
+	if assert.NotNil(t, saved["2"].PivotCurrency) {
+		assert.Equal(t, currency.EUR, *saved["2"].PivotCurrency)
+	}
+	assert.Equal(t, "mock", saved["2"].Provider, "legs of the same provider")
+
+	assert.Equal(t, []string{"4"}, failedIds)
+}
+
 func TestGroupRates(t *testing.T) {
 	now := time.Now()
 	rates := []currency.CurrencyRate{
@@ -198,17 +628,89 @@ func TestGroupRates(t *testing.T) {
 
 	grouped := groupRates(rates)
 
-	expected := map[currency.CurrencyCode][]currencyPairGroup{
-		currency.USD: {
+	expected := map[currencyGroupKey][]currencyPairGroup{
+		{BaseCurrency: currency.USD}: {
 			{Ids: []string{"1", "4"}, ResultCurrency: currency.EUR},
 			{Ids: []string{"2"}, ResultCurrency: currency.MXN},
 		},
-		currency.EUR: {
+		{BaseCurrency: currency.EUR}: {
 			{Ids: []string{"3"}, ResultCurrency: currency.USD},
 		},
 	}
 
-	assert.Equal(t, expected, grouped)
+	assertGroupedRates(t, expected, grouped)
+}
+
+func TestGroupRates_ByDate(t *testing.T) {
+	now := time.Now()
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	rates := []currency.CurrencyRate{
+		{Id: "1", BaseCurrency: currency.USD, ResultCurrency: currency.EUR, CreatedAt: now},
+		{Id: "2", BaseCurrency: currency.USD, ResultCurrency: currency.EUR, RateDate: &day, CreatedAt: now},
+		{Id: "3", BaseCurrency: currency.USD, ResultCurrency: currency.MXN, RateDate: &day, CreatedAt: now},
+	}
+
+	grouped := groupRates(rates)
+
+	expected := map[currencyGroupKey][]currencyPairGroup{
+		{BaseCurrency: currency.USD}: {
+			{Ids: []string{"1"}, ResultCurrency: currency.EUR},
+		},
+		{BaseCurrency: currency.USD, RateDate: "2024-01-15"}: {
+			{Ids: []string{"2"}, ResultCurrency: currency.EUR},
+			{Ids: []string{"3"}, ResultCurrency: currency.MXN},
+		},
+	}
+
+	assertGroupedRates(t, expected, grouped)
+}
+
+func TestProcessRates_HistoricalDate(t *testing.T) {
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	testRates := []currency.CurrencyRate{
+		{Id: "1", BaseCurrency: currency.USD, ResultCurrency: currency.EUR, RateDate: &day, Status: currency.CurrencyRateStatusProcessing},
+	}
+
+	var gotDate *time.Time
+	var saved []string
+
+	repo := &mockCurrencyRepository{
+		fetchAndMarkForProcessingFunc: func(ctx context.Context, limit int) ([]currency.CurrencyRate, error) {
+			return testRates, nil
+		},
+		saveRatesByIdsFunc: func(ctx context.Context, ids []string, quote currency.RateQuote) error {
+			saved = append(saved, ids...)
+			return nil
+		},
+	}
+
+	rateService := &mockRateService{
+		fetchDataFunc: func(baseCurrency currency.CurrencyCode, date *time.Time) (map[string]decimal.Decimal, error) {
+			gotDate = date
+			return map[string]decimal.Decimal{"EUR": decimal.RequireFromString("0.91")}, nil
+		},
+	}
+
+	service := NewProcessRatesService(repo, rateService, &mockAlertEvaluator{}, NewRateCompletionHub(), ProcessRatesConfig{})
+
+	service.ProcessRates(context.Background(), 10)
+
+	if assert.NotNil(t, gotDate) {
+		assert.Equal(t, day, *gotDate)
+	}
+	assert.Equal(t, []string{"1"}, saved)
+}
+
+// assertGroupedRates compares groups without their order, it follows map
+// iteration.
+func assertGroupedRates(t *testing.T, expected map[currencyGroupKey][]currencyPairGroup, grouped map[currencyGroupKey][]currencyPairGroup) {
+	t.Helper()
+
+	assert.Len(t, grouped, len(expected))
+
+	for key, groups := range expected {
+		assert.ElementsMatch(t, groups, grouped[key], key)
+	}
 }
 
 func TestGroupRates_EmptySlice(t *testing.T) {
@@ -245,3 +747,95 @@ func TestFlattenString_EmptySlice(t *testing.T) {
 
 	assert.Equal(t, len(flattened), 0, "expected empty slice")
 }
+
+func TestProcessRates_EvaluatesAlertsForLatestRates(t *testing.T) {
+	date := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	testRates := []currency.CurrencyRate{
+		{Id: "1", BaseCurrency: currency.USD, ResultCurrency: currency.EUR, Status: currency.CurrencyRateStatusProcessing},
+		{Id: "2", BaseCurrency: currency.USD, ResultCurrency: currency.MXN, RateDate: &date, Status: currency.CurrencyRateStatusProcessing},
+	}
+
+	repo := &mockCurrencyRepository{
+		fetchAndMarkForProcessingFunc: func(ctx context.Context, limit int) ([]currency.CurrencyRate, error) {
+			return testRates, nil
+		},
+	}
+
+	rateService := &mockRateService{
+		fetchDataFunc: func(baseCurrency currency.CurrencyCode, date *time.Time) (map[string]decimal.Decimal, error) {
+			return map[string]decimal.Decimal{
+				"EUR": decimal.RequireFromString("0.85"),
+				"MXN": decimal.RequireFromString("20.5"),
+			}, nil
+		},
+	}
+
+	var (
+		mu        sync.Mutex
+		evaluated []string
+	)
+	alerts := &mockAlertEvaluator{
+		evaluateRateFunc: func(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, rate decimal.Decimal) {
+			mu.Lock()
+			defer mu.Unlock()
+			evaluated = append(evaluated, string(baseCurrency)+string(resultCurrency)+":"+rate.String())
+		},
+	}
+
+	service := NewProcessRatesService(repo, rateService, alerts, NewRateCompletionHub(), ProcessRatesConfig{})
+
+	service.ProcessRates(context.Background(), 10)
+
+	assert.Equal(t, []string{"USDEUR:0.85"}, evaluated)
+}
+
+func TestProcessRates_LostLeaseDropsResult(t *testing.T) {
+	startedAt := time.Now()
+	oldWorker := "worker-1"
+	testRates := []currency.CurrencyRate{
+		{
+			Id:                  "1",
+			BaseCurrency:        currency.USD,
+			ResultCurrency:      currency.EUR,
+			Status:              currency.CurrencyRateStatusProcessing,
+			WorkerId:            &oldWorker,
+			ProcessingStartedAt: &startedAt,
+		},
+	}
+
+	repo := &mockCurrencyRepository{
+		fetchAndMarkForProcessingFunc: func(ctx context.Context, limit int) ([]currency.CurrencyRate, error) {
+			return testRates, nil
+		},
+		heldLease: &currency.RateLease{WorkerId: "worker-2", StartedAt: startedAt.Add(time.Minute)},
+	}
+
+	rateService := &mockRateService{
+		fetchDataFunc: func(baseCurrency currency.CurrencyCode, date *time.Time) (map[string]decimal.Decimal, error) {
+			return map[string]decimal.Decimal{"EUR": decimal.RequireFromString("0.85")}, nil
+		},
+	}
+
+	evaluated := false
+	alerts := &mockAlertEvaluator{
+		evaluateRateFunc: func(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, rate decimal.Decimal) {
+			evaluated = true
+		},
+	}
+
+	hub := NewRateCompletionHub()
+	completed, unsubscribe := hub.Subscribe("1")
+	defer unsubscribe()
+
+	service := NewProcessRatesService(repo, rateService, alerts, hub, ProcessRatesConfig{})
+
+	service.ProcessRates(context.Background(), 10)
+
+	select {
+	case <-completed:
+		t.Fatal("waiters shouldn't be notified of a result that was dropped")
+	default:
+	}
+
+	assert.False(t, evaluated, "alerts shouldn't be evaluated for a dropped result")
+}
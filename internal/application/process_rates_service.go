@@ -2,8 +2,10 @@ package application
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"sync"
+	"time"
 
 	"currency-rate-app/internal/common/utils"
 	"currency-rate-app/internal/domains/currency"
@@ -11,16 +13,56 @@ import (
 	rates_api "currency-rate-app/internal/infrastructure/http/rates-api"
 )
 
+type ProcessRatesConfig struct {
+	// PivotCurrency enables triangulation: pairs the provider doesn't quote
+	// directly are derived through it. Empty disables triangulation.
+	PivotCurrency currency.CurrencyCode
+
+	// WorkerId marks the rates this instance claims, see RateLeaseReaperService.
+	WorkerId string
+
+	// A failed fetch or a pair missing from the provider response returns
+	// the rate to PENDING with exponential backoff until MaxAttempts, then
+	// the rate is FAILED. A request the provider rejected is FAILED at once.
+	// MaxAttempts of 0 or 1 disables retries.
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
 type ProcessRatesService struct {
 	repo         db.CurrencyRepository
 	ratesService rates_api.RateService
+	alerts       AlertEvaluator
+	completions  RateCompletionNotifier
+	config       ProcessRatesConfig
 }
 
 func NewProcessRatesService(
 	repo db.CurrencyRepository,
 	rateApi rates_api.RateService,
+	alerts AlertEvaluator,
+	completions RateCompletionNotifier,
+	config ProcessRatesConfig,
 ) *ProcessRatesService {
-	return &ProcessRatesService{repo: repo, ratesService: rateApi}
+	return &ProcessRatesService{repo: repo, ratesService: rateApi, alerts: alerts, completions: completions, config: config}
+}
+
+// currencyGroupKey identifies one provider call: a base currency on a business
+// date. An empty RateDate stands for the latest rates.
+type currencyGroupKey struct {
+	BaseCurrency currency.CurrencyCode
+	RateDate     string
+}
+
+func (k currencyGroupKey) date() *time.Time {
+	if k.RateDate == "" {
+		return nil
+	}
+
+	date, _ := time.Parse(currency.RateDateLayout, k.RateDate)
+
+	return &date
 }
 
 type currencyPairGroup struct {
@@ -29,7 +71,7 @@ type currencyPairGroup struct {
 }
 
 func (s *ProcessRatesService) ProcessRates(ctx context.Context, batchSize int) {
-	rates, err := s.repo.FetchAndMarkForProcessing(ctx, batchSize)
+	rates, err := s.repo.FetchAndMarkForProcessing(ctx, s.config.WorkerId, batchSize)
 
 	if err != nil {
 		slog.ErrorContext(
@@ -51,14 +93,22 @@ func (s *ProcessRatesService) ProcessRates(ctx context.Context, batchSize int) {
 
 	groupedRates := groupRates(rates)
 
+	// The rates are claimed by one statement, they share the lease.
+	lease := rates[0].Lease()
+
+	attempts := make(map[string]int, len(rates))
+	for _, r := range rates {
+		attempts[r.Id] = r.Attempts
+	}
+
 	var wg sync.WaitGroup
 
-	for baseCurrency, group := range groupedRates {
-		b := baseCurrency
+	for key, group := range groupedRates {
+		k := key
 		g := group
 
 		wg.Go(func() {
-			s.processRatesGroup(ctx, b, g)
+			s.processRatesGroup(ctx, lease, k, g, attempts)
 		})
 	}
 
@@ -69,12 +119,16 @@ func (s *ProcessRatesService) ProcessRates(ctx context.Context, batchSize int) {
 
 func (s *ProcessRatesService) processRatesGroup(
 	ctx context.Context,
-	baseCurrency currency.CurrencyCode,
+	lease currency.RateLease,
+	key currencyGroupKey,
 	group []currencyPairGroup,
+	attempts map[string]int,
 ) {
 	defer utils.HandleRecover()
 
-	rate, err := s.ratesService.FetchData(baseCurrency)
+	baseCurrency := key.BaseCurrency
+	wanted := groupResultCurrencies(group)
+	rate, err := s.ratesService.FetchData(baseCurrency, key.date(), wanted...)
 
 	if err != nil {
 		slog.ErrorContext(
@@ -83,47 +137,248 @@ func (s *ProcessRatesService) processRatesGroup(
 			slog.String("error", err.Error()),
 		)
 
-		return
+		// Rates found before the failure are saved, the rest is retried.
+		var partialErr *rates_api.PartialRatesError
+
+		if !errors.As(err, &partialErr) {
+			s.retryOrFail(ctx, lease, flattenGroupIds(group), attempts, rates_api.ClassifyError(err))
+
+			return
+		}
 	}
 
+	var pivotErr error
+	pivotRates := s.lazyPivotRates(ctx, key, append(wanted, baseCurrency), &pivotErr)
+
 	for _, val := range group {
-		pairRate, ok := rate[string(val.ResultCurrency)]
+		quote, ok := s.resolveQuote(baseCurrency, val.ResultCurrency, rate, pivotRates)
 
 		if !ok {
-			if queryErr := s.repo.UpdateRateStatusByIds(ctx, val.Ids, currency.CurrencyRateStatusFailed); queryErr != nil {
-				slog.ErrorContext(ctx, "Update failed", slog.String("error", queryErr.Error()))
-			}
 			slog.ErrorContext(
 				ctx,
 				"Currency pair not found",
 				slog.String("baseCurrency", string(baseCurrency)),
 				slog.String("resultCurrency", string(val.ResultCurrency)),
+				slog.String("rateDate", key.RateDate),
 			)
 
+			failure := currency.RateFailure{Code: currency.RateFailurePairNotSupported, Message: "currency pair not found"}
+
+			// Without every provider or the pivot leg it's unknown whether
+			// the pair is supported.
+			if err != nil {
+				failure = rates_api.ClassifyError(err)
+			} else if pivotErr != nil {
+				failure = rates_api.ClassifyError(pivotErr)
+			}
+
+			s.retryOrFail(ctx, lease, val.Ids, attempts, failure)
+
 			continue
 		}
 
-		if queryErr := s.repo.SaveRatesByIds(ctx, val.Ids, pairRate); queryErr != nil {
+		saved, queryErr := s.repo.SaveRatesByIds(ctx, lease, val.Ids, quote)
+
+		if queryErr != nil {
 			slog.ErrorContext(ctx, "Update failed", slog.String("error", queryErr.Error()))
+
+			continue
+		}
+
+		if saved == 0 {
+			warnLeaseLost(ctx, lease)
+
+			continue
+		}
+
+		s.completions.NotifyFinal(val.Ids)
+
+		// Alerts watch the latest rate, historical requests don't move it.
+		if key.RateDate == "" {
+			s.alerts.EvaluateRate(ctx, baseCurrency, val.ResultCurrency, quote.Rate)
 		}
 	}
 }
 
-func groupRates(rates []currency.CurrencyRate) map[currency.CurrencyCode][]currencyPairGroup {
-	groupedTemp := make(map[currency.CurrencyCode]map[currency.CurrencyCode][]string)
+// retryOrFail returns the rates to PENDING with backoff, rates that used up
+// their attempts or failed permanently are FAILED: retrying a rejected request
+// gives the same answer. The failure is kept on the rates either way.
+func (s *ProcessRatesService) retryOrFail(
+	ctx context.Context,
+	lease currency.RateLease,
+	ids []string,
+	attempts map[string]int,
+	failure currency.RateFailure,
+) {
+	var failed []string
+	retries := make(map[int][]string)
+
+	for _, id := range ids {
+		if failure.Code.IsPermanent() || attempts[id] >= s.config.MaxAttempts {
+			failed = append(failed, id)
+		} else {
+			retries[attempts[id]] = append(retries[attempts[id]], id)
+		}
+	}
+
+	now := time.Now()
+
+	for attempt, retryIds := range retries {
+		nextAttemptAt := now.Add(utils.Backoff(attempt, s.config.InitialBackoff, s.config.MaxBackoff))
+
+		retried, err := s.repo.RetryRatesByIds(ctx, lease, retryIds, nextAttemptAt, failure)
+
+		if err != nil {
+			slog.ErrorContext(ctx, "Update failed", slog.String("error", err.Error()))
+		} else if retried == 0 {
+			warnLeaseLost(ctx, lease)
+		}
+	}
+
+	if len(failed) == 0 {
+		return
+	}
+
+	failedCount, err := s.repo.FailRatesByIds(ctx, lease, failed, failure)
+
+	if err != nil {
+		slog.ErrorContext(ctx, "Update failed", slog.String("error", err.Error()))
+
+		return
+	}
+
+	if failedCount == 0 {
+		warnLeaseLost(ctx, lease)
+
+		return
+	}
+
+	s.completions.NotifyFinal(failed)
+}
+
+// warnLeaseLost reports results dropped because the rates were reclaimed by
+// RateLeaseReaperService while the worker was still processing them.
+func warnLeaseLost(ctx context.Context, lease currency.RateLease) {
+	slog.WarnContext(
+		ctx,
+		"Rates lease lost, result dropped",
+		slog.String("workerId", lease.WorkerId),
+		slog.Time("processingStartedAt", lease.StartedAt),
+	)
+}
+
+func (s *ProcessRatesService) resolveQuote(
+	baseCurrency currency.CurrencyCode,
+	resultCurrency currency.CurrencyCode,
+	baseRates rates_api.Rates,
+	pivotRates func() rates_api.Rates,
+) (currency.RateQuote, bool) {
+	if pairRate, ok := baseRates[string(resultCurrency)]; ok {
+		return currency.RateQuote{Rate: pairRate.Value, Provider: pairRate.Provider}, true
+	}
+
+	pivot := s.config.PivotCurrency
+
+	if pivot == "" || pivot == baseCurrency || pivot == resultCurrency {
+		return currency.RateQuote{}, false
+	}
+
+	quotes := pivotRates()
+
+	pivotQuote, ok := quotes[string(resultCurrency)]
+
+	if !ok {
+		return currency.RateQuote{}, false
+	}
+
+	basePivot, ok := baseRates[string(pivot)]
+
+	if !ok {
+		pivotBase, found := quotes[string(baseCurrency)]
+
+		if !found || pivotBase.Value.IsZero() {
+			return currency.RateQuote{}, false
+		}
+
+		basePivot = rates_api.Rate{Value: currency.InverseRate(pivotBase.Value), Provider: pivotBase.Provider}
+	}
+
+	provider := basePivot.Provider
+
+	if pivotQuote.Provider != provider {
+		provider += "+" + pivotQuote.Provider
+	}
+
+	return currency.RateQuote{
+		Rate:          currency.CrossRate(basePivot.Value, pivotQuote.Value),
+		PivotCurrency: &pivot,
+		Provider:      provider,
+	}, true
+}
+
+// lazyPivotRates fetches the pivot leg at most once per group and only if some
+// pair actually needs it. A failed fetch is reported through fetchErr.
+func (s *ProcessRatesService) lazyPivotRates(
+	ctx context.Context,
+	key currencyGroupKey,
+	wanted []currency.CurrencyCode,
+	fetchErr *error,
+) func() rates_api.Rates {
+	var (
+		fetched bool
+		rates   rates_api.Rates
+	)
+
+	return func() rates_api.Rates {
+		if fetched {
+			return rates
+		}
+
+		fetched = true
+
+		res, err := s.ratesService.FetchData(s.config.PivotCurrency, key.date(), wanted...)
+
+		if err != nil {
+			slog.ErrorContext(
+				ctx,
+				"Error getting pivot rate:",
+				slog.String("baseCurrency", string(key.BaseCurrency)),
+				slog.String("pivotCurrency", string(s.config.PivotCurrency)),
+				slog.String("rateDate", key.RateDate),
+				slog.String("error", err.Error()),
+			)
+
+			*fetchErr = err
+		}
+
+		// A partial result still has pivot rates to use.
+		rates = res
+
+		return rates
+	}
+}
+
+func groupRates(rates []currency.CurrencyRate) map[currencyGroupKey][]currencyPairGroup {
+	groupedTemp := make(map[currencyGroupKey]map[currency.CurrencyCode][]string)
 
 	for _, r := range rates {
-		if groupedTemp[r.BaseCurrency] == nil {
-			groupedTemp[r.BaseCurrency] = make(map[currency.CurrencyCode][]string)
+		key := currencyGroupKey{BaseCurrency: r.BaseCurrency}
+
+		if date := currency.FormatRateDate(r.RateDate); date != nil {
+			key.RateDate = *date
 		}
-		groupedTemp[r.BaseCurrency][r.ResultCurrency] = append(groupedTemp[r.BaseCurrency][r.ResultCurrency], r.Id)
+
+		if groupedTemp[key] == nil {
+			groupedTemp[key] = make(map[currency.CurrencyCode][]string)
+		}
+		groupedTemp[key][r.ResultCurrency] = append(groupedTemp[key][r.ResultCurrency], r.Id)
 	}
 
-	grouped := make(map[currency.CurrencyCode][]currencyPairGroup)
+	grouped := make(map[currencyGroupKey][]currencyPairGroup)
 
-	for base, m := range groupedTemp {
+	for key, m := range groupedTemp {
 		for result, ids := range m {
-			grouped[base] = append(grouped[base], currencyPairGroup{ResultCurrency: result, Ids: ids})
+			grouped[key] = append(grouped[key], currencyPairGroup{ResultCurrency: result, Ids: ids})
 		}
 	}
 
@@ -131,6 +386,14 @@ func groupRates(rates []currency.CurrencyRate) map[currency.CurrencyCode][]curre
 
 }
 
+func groupResultCurrencies(items []currencyPairGroup) []currency.CurrencyCode {
+	codes := make([]currency.CurrencyCode, 0, len(items))
+	for _, it := range items {
+		codes = append(codes, it.ResultCurrency)
+	}
+	return codes
+}
+
 func flattenGroupIds(items []currencyPairGroup) []string {
 	var merged []string
 	for _, it := range items {
@@ -0,0 +1,113 @@
+package application
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	error_utils "currency-rate-app/internal/common/error-utils"
+	http_client "currency-rate-app/internal/common/http-client"
+	"currency-rate-app/internal/domains/callback"
+	"currency-rate-app/internal/domains/currency"
+	"currency-rate-app/internal/infrastructure/db"
+)
+
+type RateBatchService interface {
+	CreateRateBatch(ctx context.Context, clientId string, idempotencyKey string, requests []currency.RateBatchRequest) (*currency.RateBatch, error)
+	GetRateBatch(ctx context.Context, id string) (*currency.RateBatch, error)
+}
+
+type rateBatchServiceImpl struct {
+	repo db.CurrencyRepository
+}
+
+func NewRateBatchService(repo db.CurrencyRepository) RateBatchService {
+	return &rateBatchServiceImpl{repo: repo}
+}
+
+func (s *rateBatchServiceImpl) CreateRateBatch(
+	ctx context.Context,
+	clientId string,
+	idempotencyKey string,
+	requests []currency.RateBatchRequest,
+) (*currency.RateBatch, error) {
+	if len(requests) == 0 || len(requests) > currency.MaxRateBatchItems {
+		return nil, error_utils.ErrValidationError(fmt.Sprintf("batch should have from 1 to %d items", currency.MaxRateBatchItems))
+	}
+
+	hash, err := hashRateBatch(requests)
+
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]currency.RateBatchItem, 0, len(requests))
+
+	for i, request := range requests {
+		items = append(items, validateRateBatchItem(i, request))
+	}
+
+	return s.repo.CreateRateBatch(ctx, clientId, idempotencyKey, hash, items)
+}
+
+func (s *rateBatchServiceImpl) GetRateBatch(ctx context.Context, id string) (*currency.RateBatch, error) {
+	return s.repo.GetRateBatchById(ctx, id)
+}
+
+// validateRateBatchItem runs the checks of a single POST /v1/currencies and
+// keeps the first error on the item.
+func validateRateBatchItem(position int, request currency.RateBatchRequest) currency.RateBatchItem {
+	item := currency.RateBatchItem{
+		Position:       position,
+		BaseCurrency:   request.BaseCurrency,
+		ResultCurrency: request.ResultCurrency,
+		CallbackUrl:    request.CallbackUrl,
+	}
+
+	err := currency.ValidateCurrencyPair(request.BaseCurrency, request.ResultCurrency)
+
+	if err == nil {
+		item.RateDate, err = currency.ParseRateDate(request.Date)
+	}
+
+	if err == nil && request.CallbackUrl != nil {
+		if urlErr := http_client.ValidatePublicUrl(*request.CallbackUrl); urlErr != nil {
+			err = callback.ErrInvalidCallbackUrl(urlErr.Error())
+		}
+	}
+
+	if err == nil {
+		return item
+	}
+
+	code, message := "ValidationError", err.Error()
+
+	var customErr *error_utils.CustomError
+
+	if errors.As(err, &customErr) {
+		code, message = customErr.Code, customErr.Message
+	}
+
+	item.ErrorCode = &code
+
+	if message != "" {
+		item.ErrorMessage = &message
+	}
+
+	return item
+}
+
+func hashRateBatch(requests []currency.RateBatchRequest) (string, error) {
+	body, err := json.Marshal(requests)
+
+	if err != nil {
+		return "", error_utils.ErrInternalServerError(err.Error())
+	}
+
+	sum := sha256.Sum256(body)
+
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,74 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	error_utils "currency-rate-app/internal/common/error-utils"
+	"currency-rate-app/internal/domains/currency"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateBatchService_ValidatesEveryItem(t *testing.T) {
+	var stored []currency.RateBatchItem
+	var storedHash string
+	repo := &mockRepo{
+		createRateBatch: func(ctx context.Context, clientId string, idempotencyKey string, requestHash string, items []currency.RateBatchItem) (*currency.RateBatch, error) {
+			stored = items
+			storedHash = requestHash
+			return &currency.RateBatch{Id: "batch-1", IdempotencyKey: idempotencyKey, Items: items}, nil
+		},
+	}
+	service := NewRateBatchService(repo)
+	localUrl := "http://127.0.0.1/hook"
+
+	requests := []currency.RateBatchRequest{
+		{BaseCurrency: currency.EUR, ResultCurrency: currency.USD},
+		{BaseCurrency: currency.EUR, ResultCurrency: currency.EUR},
+		{BaseCurrency: currency.USD, ResultCurrency: currency.MXN, Date: "2024-13-01"},
+		{BaseCurrency: currency.USD, ResultCurrency: currency.MXN, CallbackUrl: &localUrl},
+		{BaseCurrency: currency.USD, ResultCurrency: currency.MXN, Date: "2024-10-02"},
+	}
+
+	batch, err := service.CreateRateBatch(context.Background(), "client-a", "idem-1", requests)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "batch-1", batch.Id)
+	assert.Len(t, stored, 5)
+
+	for i, item := range stored {
+		assert.Equal(t, i, item.Position)
+	}
+
+	assert.Nil(t, stored[0].ErrorCode)
+	assert.Equal(t, "CurrenciesShouldDiffer", *stored[1].ErrorCode)
+	assert.Equal(t, "InvalidRateDate", *stored[2].ErrorCode)
+	assert.Equal(t, "InvalidCallbackUrl", *stored[3].ErrorCode)
+	assert.Nil(t, stored[4].ErrorCode)
+	assert.Equal(t, "2024-10-02", *currency.FormatRateDate(stored[4].RateDate))
+
+	firstHash := storedHash
+
+	_, err = service.CreateRateBatch(context.Background(), "client-a", "idem-1", requests)
+	assert.NoError(t, err)
+	assert.Equal(t, firstHash, storedHash, "a retry should hash the same")
+
+	requests[0].ResultCurrency = currency.MXN
+	_, err = service.CreateRateBatch(context.Background(), "client-a", "idem-1", requests)
+	assert.NoError(t, err)
+	assert.NotEqual(t, firstHash, storedHash, "a different batch should hash differently")
+}
+
+func TestRateBatchService_Size(t *testing.T) {
+	service := NewRateBatchService(&mockRepo{})
+
+	_, err := service.CreateRateBatch(context.Background(), "client-a", "idem-1", nil)
+	assert.Equal(t, error_utils.ErrorCodeBadRequest, err.(*error_utils.CustomError).ErrorType)
+
+	tooMany := make([]currency.RateBatchRequest, currency.MaxRateBatchItems+1)
+	_, err = service.CreateRateBatch(context.Background(), "client-a", "idem-1", tooMany)
+	assert.Equal(t, error_utils.ErrorCodeBadRequest, err.(*error_utils.CustomError).ErrorType)
+	assert.Equal(t, fmt.Sprintf("batch should have from 1 to %d items", currency.MaxRateBatchItems), err.(*error_utils.CustomError).Message)
+}
@@ -0,0 +1,61 @@
+package application
+
+import (
+	"sync"
+)
+
+// RateCompletionNotifier is told which rate requests reached a final status.
+type RateCompletionNotifier interface {
+	NotifyFinal(ids []string)
+}
+
+// RateCompletionHub wakes up requests waiting for a rate request to finish.
+// It's in-process only: waiters still re-read the row when they wake up, so a
+// missed or spurious signal costs at most one query.
+type RateCompletionHub struct {
+	mu      sync.Mutex
+	waiters map[string]map[chan struct{}]struct{}
+}
+
+func NewRateCompletionHub() *RateCompletionHub {
+	return &RateCompletionHub{waiters: make(map[string]map[chan struct{}]struct{})}
+}
+
+// Subscribe returns a channel closed once id is notified. The returned func
+// must be called when the caller stops waiting.
+func (h *RateCompletionHub) Subscribe(id string) (<-chan struct{}, func()) {
+	ch := make(chan struct{})
+
+	h.mu.Lock()
+	if h.waiters[id] == nil {
+		h.waiters[id] = make(map[chan struct{}]struct{})
+	}
+	h.waiters[id][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		if _, ok := h.waiters[id][ch]; ok {
+			delete(h.waiters[id], ch)
+
+			if len(h.waiters[id]) == 0 {
+				delete(h.waiters, id)
+			}
+		}
+	}
+}
+
+func (h *RateCompletionHub) NotifyFinal(ids []string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, id := range ids {
+		for ch := range h.waiters[id] {
+			close(ch)
+		}
+
+		delete(h.waiters, id)
+	}
+}
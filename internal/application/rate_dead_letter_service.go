@@ -0,0 +1,96 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"currency-rate-app/internal/domains/currency"
+	"currency-rate-app/internal/infrastructure/db"
+)
+
+// RateDeadLetterService is the admin side of FAILED rates: they are listed
+// with the failure reason and either requeued or archived, every action is
+// recorded with its actor.
+type RateDeadLetterService interface {
+	ListFailedRates(ctx context.Context, filter currency.FailedRateFilter, cursor *currency.RateCursor, limit int) (*currency.RatePage, error)
+	RequeueRate(ctx context.Context, id string, actor string) (*currency.CurrencyRate, error)
+	RequeueFailedRates(ctx context.Context, filter currency.FailedRateFilter, limit int, actor string) ([]currency.CurrencyRate, error)
+	ArchiveRate(ctx context.Context, id string, actor string) (*currency.CurrencyRate, error)
+	GetRateAdminActions(ctx context.Context, id string) ([]currency.RateAdminAction, error)
+}
+
+type rateDeadLetterServiceImpl struct {
+	repo            db.CurrencyRepository
+	currencyService CurrencyService
+}
+
+func NewRateDeadLetterService(repo db.CurrencyRepository, currencyService CurrencyService) RateDeadLetterService {
+	return &rateDeadLetterServiceImpl{repo: repo, currencyService: currencyService}
+}
+
+// ListFailedRates pages through the dead-letter list, latest failures first.
+func (s *rateDeadLetterServiceImpl) ListFailedRates(
+	ctx context.Context,
+	filter currency.FailedRateFilter,
+	cursor *currency.RateCursor,
+	limit int,
+) (*currency.RatePage, error) {
+	if err := validateFailedRateFilter(filter); err != nil {
+		return nil, err
+	}
+
+	return s.currencyService.ListRates(ctx, currency.RateListQuery{
+		Filter:    filter.RateFilter(),
+		SortField: currency.RateSortUpdatedAt,
+		Desc:      true,
+		Cursor:    cursor,
+		Limit:     limit,
+	})
+}
+
+func (s *rateDeadLetterServiceImpl) RequeueRate(ctx context.Context, id string, actor string) (*currency.CurrencyRate, error) {
+	return s.repo.RequeueRate(ctx, id, actor)
+}
+
+func (s *rateDeadLetterServiceImpl) RequeueFailedRates(
+	ctx context.Context,
+	filter currency.FailedRateFilter,
+	limit int,
+	actor string,
+) ([]currency.CurrencyRate, error) {
+	if err := validateFailedRateFilter(filter); err != nil {
+		return nil, err
+	}
+
+	if limit == 0 {
+		limit = currency.DefaultRequeueLimit
+	}
+
+	if limit < 0 || limit > currency.MaxRequeueLimit {
+		return nil, currency.ErrInvalidFailedRateFilter(fmt.Sprintf("limit should be from 1 to %d", currency.MaxRequeueLimit))
+	}
+
+	return s.repo.RequeueFailedRates(ctx, filter, limit, actor)
+}
+
+func (s *rateDeadLetterServiceImpl) ArchiveRate(ctx context.Context, id string, actor string) (*currency.CurrencyRate, error) {
+	return s.repo.ArchiveRate(ctx, id, actor)
+}
+
+func (s *rateDeadLetterServiceImpl) GetRateAdminActions(ctx context.Context, id string) ([]currency.RateAdminAction, error) {
+	return s.repo.GetRateAdminActions(ctx, id)
+}
+
+func validateFailedRateFilter(filter currency.FailedRateFilter) error {
+	for _, code := range []currency.CurrencyCode{filter.BaseCurrency, filter.ResultCurrency} {
+		if code != "" && !code.IsValid() {
+			return currency.ErrInvalidCurrencyCode()
+		}
+	}
+
+	if filter.FailedFrom != nil && filter.FailedTo != nil && !filter.FailedFrom.Before(*filter.FailedTo) {
+		return currency.ErrInvalidFailedRateFilter("failedFrom should be before failedTo")
+	}
+
+	return nil
+}
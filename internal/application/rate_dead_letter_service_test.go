@@ -0,0 +1,79 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	error_utils "currency-rate-app/internal/common/error-utils"
+	"currency-rate-app/internal/domains/currency"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateDeadLetterService_ListFailedRates(t *testing.T) {
+	var received currency.RateListQuery
+	repo := &mockRepo{
+		listRatesFn: func(ctx context.Context, query currency.RateListQuery) ([]currency.CurrencyRate, error) {
+			received = query
+			return []currency.CurrencyRate{{Id: "rate-1", Status: currency.CurrencyRateStatusFailed}}, nil
+		},
+	}
+	service := NewRateDeadLetterService(repo, NewCurrencyService(repo, FreshnessConfig{}, NewRateCompletionHub()))
+
+	page, err := service.ListFailedRates(context.Background(), currency.FailedRateFilter{BaseCurrency: currency.USD}, nil, 0)
+
+	assert.Nil(t, err)
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, []currency.CurrencyRateStatus{currency.CurrencyRateStatusFailed}, received.Filter.Statuses)
+	assert.Equal(t, currency.USD, received.Filter.BaseCurrency)
+	assert.False(t, *received.Filter.Archived)
+	assert.Equal(t, currency.RateSortUpdatedAt, received.SortField)
+	assert.True(t, received.Desc)
+}
+
+func TestRateDeadLetterService_RequeueFailedRates_DefaultLimit(t *testing.T) {
+	var receivedLimit int
+	var receivedActor string
+	repo := &mockRepo{
+		requeueFailedRatesFn: func(ctx context.Context, filter currency.FailedRateFilter, limit int, actor string) ([]currency.CurrencyRate, error) {
+			receivedLimit = limit
+			receivedActor = actor
+			return []currency.CurrencyRate{{Id: "rate-1"}}, nil
+		},
+	}
+	service := NewRateDeadLetterService(repo, nil)
+
+	rates, err := service.RequeueFailedRates(context.Background(), currency.FailedRateFilter{}, 0, "alice")
+
+	assert.Nil(t, err)
+	assert.Len(t, rates, 1)
+	assert.Equal(t, currency.DefaultRequeueLimit, receivedLimit)
+	assert.Equal(t, "alice", receivedActor)
+}
+
+func TestRateDeadLetterService_RequeueFailedRates_InvalidFilter(t *testing.T) {
+	service := NewRateDeadLetterService(&mockRepo{}, nil)
+	from := time.Date(2024, 10, 2, 0, 0, 0, 0, time.UTC)
+	to := from.Add(-time.Hour)
+
+	cases := []struct {
+		name   string
+		filter currency.FailedRateFilter
+		limit  int
+	}{
+		{name: "limit", limit: currency.MaxRequeueLimit + 1},
+		{name: "currency", filter: currency.FailedRateFilter{BaseCurrency: "XXX1"}},
+		{name: "range", filter: currency.FailedRateFilter{FailedFrom: &from, FailedTo: &to}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := service.RequeueFailedRates(context.Background(), tc.filter, tc.limit, "alice")
+
+			customErr, ok := err.(*error_utils.CustomError)
+			assert.True(t, ok)
+			assert.Equal(t, error_utils.ErrorCodeBadRequest, customErr.ErrorType)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package application
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"currency-rate-app/internal/common/metrics"
+	"currency-rate-app/internal/domains/currency"
+	"currency-rate-app/internal/infrastructure/db"
+)
+
+type RateLeaseReaperConfig struct {
+	// Lease is how long a worker may hold a claimed rate. It must be longer
+	// than a provider call, otherwise rates in progress are reclaimed.
+	Lease time.Duration
+	// MaxAttempts matches ProcessRatesConfig, a reclaimed rate without
+	// attempts left is FAILED.
+	MaxAttempts int
+}
+
+// RateLeaseReaperService returns rates stuck in PROCESSING, e.g. after the
+// worker crashed between claiming and saving them, back to the queue.
+type RateLeaseReaperService struct {
+	repo        db.CurrencyRepository
+	completions RateCompletionNotifier
+	config      RateLeaseReaperConfig
+	now         func() time.Time
+}
+
+func NewRateLeaseReaperService(
+	repo db.CurrencyRepository,
+	completions RateCompletionNotifier,
+	config RateLeaseReaperConfig,
+) *RateLeaseReaperService {
+	return &RateLeaseReaperService{repo: repo, completions: completions, config: config, now: time.Now}
+}
+
+func (s *RateLeaseReaperService) ReclaimExpired(ctx context.Context, batchSize int) {
+	rates, err := s.repo.ReclaimExpiredRates(ctx, s.now().Add(-s.config.Lease), s.config.MaxAttempts, batchSize)
+
+	if err != nil {
+		slog.ErrorContext(ctx, "Rates reclaim failed", slog.String("error", err.Error()))
+
+		return
+	}
+
+	var failed []string
+
+	for _, rate := range rates {
+		attrs := []any{
+			slog.String("id", rate.Id),
+			slog.String("status", string(rate.Status)),
+			slog.Int("attempts", rate.Attempts),
+		}
+
+		if rate.WorkerId != nil {
+			attrs = append(attrs, slog.String("workerId", *rate.WorkerId))
+		}
+
+		if rate.ProcessingStartedAt != nil {
+			attrs = append(attrs, slog.Time("processingStartedAt", *rate.ProcessingStartedAt))
+		}
+
+		slog.WarnContext(ctx, "Rate lease expired", attrs...)
+
+		if rate.Status == currency.CurrencyRateStatusFailed {
+			metrics.RatesLeasesFailed.Add(1)
+			failed = append(failed, rate.Id)
+		} else {
+			metrics.RatesLeasesReclaimed.Add(1)
+		}
+	}
+
+	if len(failed) > 0 {
+		s.completions.NotifyFinal(failed)
+	}
+}
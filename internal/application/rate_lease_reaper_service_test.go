@@ -0,0 +1,78 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"currency-rate-app/internal/common/metrics"
+	"currency-rate-app/internal/domains/currency"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLeaseReaper_ReclaimsExpiredLeases(t *testing.T) {
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	workerId := "worker-1"
+
+	var startedBefore time.Time
+	var maxAttempts int
+
+	repo := &mockCurrencyRepository{
+		reclaimExpiredRatesFunc: func(ctx context.Context, before time.Time, max int, limit int) ([]currency.CurrencyRate, error) {
+			startedBefore = before
+			maxAttempts = max
+			return []currency.CurrencyRate{
+				{Id: "1", Status: currency.CurrencyRateStatusFailed, Attempts: 5, WorkerId: &workerId},
+				{Id: "2", Status: currency.CurrencyRateStatusPending, Attempts: 1, WorkerId: &workerId},
+			}, nil
+		},
+	}
+
+	hub := NewRateCompletionHub()
+	failedSignal, unsubscribeFailed := hub.Subscribe("1")
+	defer unsubscribeFailed()
+	pendingSignal, unsubscribePending := hub.Subscribe("2")
+	defer unsubscribePending()
+
+	reclaimedBefore := metrics.RatesLeasesReclaimed.Value()
+	failedBefore := metrics.RatesLeasesFailed.Value()
+
+	service := NewRateLeaseReaperService(repo, hub, RateLeaseReaperConfig{Lease: 2 * time.Minute, MaxAttempts: 5})
+	service.now = func() time.Time { return now }
+
+	service.ReclaimExpired(context.Background(), 100)
+
+	assert.Equal(t, now.Add(-2*time.Minute), startedBefore)
+	assert.Equal(t, 5, maxAttempts)
+	assert.Equal(t, reclaimedBefore+1, metrics.RatesLeasesReclaimed.Value())
+	assert.Equal(t, failedBefore+1, metrics.RatesLeasesFailed.Value())
+
+	select {
+	case <-failedSignal:
+	default:
+		t.Fatal("waiters of a failed rate are not notified")
+	}
+
+	select {
+	case <-pendingSignal:
+		t.Fatal("waiters of a reclaimed rate must keep waiting")
+	default:
+	}
+}
+
+func TestRateLeaseReaper_RepositoryError(t *testing.T) {
+	repo := &mockCurrencyRepository{
+		reclaimExpiredRatesFunc: func(ctx context.Context, before time.Time, max int, limit int) ([]currency.CurrencyRate, error) {
+			return nil, errors.New("db is down")
+		},
+	}
+
+	reclaimedBefore := metrics.RatesLeasesReclaimed.Value()
+
+	service := NewRateLeaseReaperService(repo, NewRateCompletionHub(), RateLeaseReaperConfig{Lease: time.Minute, MaxAttempts: 5})
+	service.ReclaimExpired(context.Background(), 100)
+
+	assert.Equal(t, reclaimedBefore, metrics.RatesLeasesReclaimed.Value())
+}
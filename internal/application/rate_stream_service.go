@@ -0,0 +1,117 @@
+package application
+
+import (
+	"context"
+	"sync"
+
+	"currency-rate-app/internal/domains/currency"
+	"currency-rate-app/internal/infrastructure/db"
+)
+
+// rateStreamBuffer is how many events a slow stream may lag behind before it
+// is dropped. The client reconnects with Last-Event-ID and reads the rest from
+// history, a stuck connection never blocks the other streams.
+const rateStreamBuffer = 64
+
+type RateStreamService interface {
+	// Subscribe returns live events for the pairs. The channel is closed when
+	// the subscriber falls behind or the service is closed.
+	Subscribe(pairs []currency.CurrencyPair) (<-chan currency.RateEvent, func())
+	GetRateEventsAfter(ctx context.Context, lastEventId int64, pairs []currency.CurrencyPair, limit int) ([]currency.RateEvent, error)
+	Publish(event currency.RateEvent)
+	// Close ends every stream, so the server can shut down.
+	Close()
+}
+
+type rateSubscriber struct {
+	pairs  map[currency.CurrencyPair]struct{}
+	events chan currency.RateEvent
+}
+
+type rateStreamServiceImpl struct {
+	repo db.RateEventRepository
+
+	mu          sync.Mutex
+	closed      bool
+	subscribers map[*rateSubscriber]struct{}
+}
+
+func NewRateStreamService(repo db.RateEventRepository) RateStreamService {
+	return &rateStreamServiceImpl{repo: repo, subscribers: make(map[*rateSubscriber]struct{})}
+}
+
+func (s *rateStreamServiceImpl) Subscribe(pairs []currency.CurrencyPair) (<-chan currency.RateEvent, func()) {
+	sub := &rateSubscriber{
+		pairs:  make(map[currency.CurrencyPair]struct{}, len(pairs)),
+		events: make(chan currency.RateEvent, rateStreamBuffer),
+	}
+
+	for _, pair := range pairs {
+		sub.pairs[pair] = struct{}{}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		close(sub.events)
+
+		return sub.events, func() {}
+	}
+
+	s.subscribers[sub] = struct{}{}
+
+	return sub.events, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.remove(sub)
+	}
+}
+
+func (s *rateStreamServiceImpl) GetRateEventsAfter(
+	ctx context.Context,
+	lastEventId int64,
+	pairs []currency.CurrencyPair,
+	limit int,
+) ([]currency.RateEvent, error) {
+	return s.repo.GetRateEventsAfter(ctx, lastEventId, pairs, limit)
+}
+
+func (s *rateStreamServiceImpl) Publish(event currency.RateEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sub := range s.subscribers {
+		if _, ok := sub.pairs[event.Pair()]; !ok {
+			continue
+		}
+
+		select {
+		case sub.events <- event:
+		default:
+			s.remove(sub)
+		}
+	}
+}
+
+func (s *rateStreamServiceImpl) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+
+	for sub := range s.subscribers {
+		s.remove(sub)
+	}
+}
+
+// remove must be called with mu held.
+func (s *rateStreamServiceImpl) remove(sub *rateSubscriber) {
+	if _, ok := s.subscribers[sub]; !ok {
+		return
+	}
+
+	delete(s.subscribers, sub)
+	close(sub.events)
+}
@@ -0,0 +1,59 @@
+package application
+
+import (
+	"testing"
+
+	"currency-rate-app/internal/domains/currency"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var eurUsd = currency.CurrencyPair{BaseCurrency: currency.EUR, ResultCurrency: currency.USD}
+
+func TestRateStream_PublishFiltersPairs(t *testing.T) {
+	service := NewRateStreamService(nil)
+
+	events, unsubscribe := service.Subscribe([]currency.CurrencyPair{eurUsd})
+	defer unsubscribe()
+
+	service.Publish(currency.RateEvent{Id: 1, BaseCurrency: currency.USD, ResultCurrency: currency.MXN})
+	service.Publish(currency.RateEvent{Id: 2, BaseCurrency: currency.EUR, ResultCurrency: currency.USD})
+
+	event := <-events
+	assert.Equal(t, int64(2), event.Id)
+	assert.Empty(t, events)
+}
+
+func TestRateStream_SlowSubscriberIsDropped(t *testing.T) {
+	service := NewRateStreamService(nil)
+
+	events, unsubscribe := service.Subscribe([]currency.CurrencyPair{eurUsd})
+	defer unsubscribe()
+
+	for i := 0; i <= rateStreamBuffer; i++ {
+		service.Publish(currency.RateEvent{Id: int64(i), BaseCurrency: currency.EUR, ResultCurrency: currency.USD})
+	}
+
+	received := 0
+	for range events {
+		received++
+	}
+
+	assert.Equal(t, rateStreamBuffer, received, "channel should be closed after the buffered events")
+}
+
+func TestRateStream_CloseEndsStreams(t *testing.T) {
+	service := NewRateStreamService(nil)
+
+	events, unsubscribe := service.Subscribe([]currency.CurrencyPair{eurUsd})
+	defer unsubscribe()
+
+	service.Close()
+
+	_, ok := <-events
+	assert.False(t, ok)
+
+	late, _ := service.Subscribe([]currency.CurrencyPair{eurUsd})
+	_, ok = <-late
+	assert.False(t, ok, "subscriptions after close should be closed right away")
+}
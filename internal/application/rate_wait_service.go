@@ -0,0 +1,59 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"currency-rate-app/internal/infrastructure/db"
+)
+
+type RateWaitService interface {
+	// WaitForFinalStatus blocks until the rate request reaches a final status,
+	// the timeout runs out or ctx is done. It reports whether the request is
+	// final, the caller reads the result itself.
+	WaitForFinalStatus(ctx context.Context, id string, timeout time.Duration) (bool, error)
+}
+
+type rateWaitServiceImpl struct {
+	repo db.CurrencyRepository
+	hub  *RateCompletionHub
+}
+
+func NewRateWaitService(repo db.CurrencyRepository, hub *RateCompletionHub) RateWaitService {
+	return &rateWaitServiceImpl{repo: repo, hub: hub}
+}
+
+func (s *rateWaitServiceImpl) WaitForFinalStatus(ctx context.Context, id string, timeout time.Duration) (bool, error) {
+	// Subscribe before the first read, so a completion between the read and
+	// the wait isn't missed.
+	signal, unsubscribe := s.hub.Subscribe(id)
+	defer unsubscribe()
+
+	final, err := s.isFinal(ctx, id)
+
+	if err != nil || final {
+		return final, err
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-signal:
+	case <-timer.C:
+	case <-ctx.Done():
+		return false, nil
+	}
+
+	return s.isFinal(ctx, id)
+}
+
+func (s *rateWaitServiceImpl) isFinal(ctx context.Context, id string) (bool, error) {
+	rate, err := s.repo.GetRateById(ctx, id)
+
+	if err != nil {
+		return false, err
+	}
+
+	return rate.Status.IsFinal(), nil
+}
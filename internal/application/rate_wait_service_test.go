@@ -0,0 +1,107 @@
+package application
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"currency-rate-app/internal/domains/currency"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitForFinalStatus_AlreadyCompleted(t *testing.T) {
+	repo := &mockCurrencyRepository{
+		getRateByIdFunc: func(ctx context.Context, id string) (*currency.CurrencyRate, error) {
+			return &currency.CurrencyRate{Id: id, Status: currency.CurrencyRateStatusCompleted}, nil
+		},
+	}
+
+	service := NewRateWaitService(repo, NewRateCompletionHub())
+
+	final, err := service.WaitForFinalStatus(context.Background(), "1", time.Hour)
+
+	assert.NoError(t, err)
+	assert.True(t, final)
+}
+
+func TestWaitForFinalStatus_WakesUpOnNotify(t *testing.T) {
+	var status atomic.Value
+	status.Store(currency.CurrencyRateStatusPending)
+
+	repo := &mockCurrencyRepository{
+		getRateByIdFunc: func(ctx context.Context, id string) (*currency.CurrencyRate, error) {
+			return &currency.CurrencyRate{Id: id, Status: status.Load().(currency.CurrencyRateStatus)}, nil
+		},
+	}
+
+	hub := NewRateCompletionHub()
+	service := NewRateWaitService(repo, hub)
+
+	go func() {
+		// Notify only once the waiter is subscribed.
+		for {
+			hub.mu.Lock()
+			subscribed := len(hub.waiters["1"]) > 0
+			hub.mu.Unlock()
+
+			if subscribed {
+				break
+			}
+
+			time.Sleep(time.Millisecond)
+		}
+
+		status.Store(currency.CurrencyRateStatusFailed)
+		hub.NotifyFinal([]string{"1"})
+	}()
+
+	start := time.Now()
+	final, err := service.WaitForFinalStatus(context.Background(), "1", time.Hour)
+
+	assert.NoError(t, err)
+	assert.True(t, final)
+	assert.Less(t, time.Since(start), time.Minute)
+	assert.Empty(t, hub.waiters, "waiter should be removed")
+}
+
+func TestWaitForFinalStatus_Timeout(t *testing.T) {
+	repo := &mockCurrencyRepository{
+		getRateByIdFunc: func(ctx context.Context, id string) (*currency.CurrencyRate, error) {
+			return &currency.CurrencyRate{Id: id, Status: currency.CurrencyRateStatusProcessing}, nil
+		},
+	}
+
+	hub := NewRateCompletionHub()
+	service := NewRateWaitService(repo, hub)
+
+	final, err := service.WaitForFinalStatus(context.Background(), "1", 10*time.Millisecond)
+
+	assert.NoError(t, err)
+	assert.False(t, final)
+	assert.Empty(t, hub.waiters, "waiter should be removed")
+}
+
+func TestRateCompletionHub_NotifyOnlyMatchingIds(t *testing.T) {
+	hub := NewRateCompletionHub()
+
+	first, cancelFirst := hub.Subscribe("1")
+	defer cancelFirst()
+	second, cancelSecond := hub.Subscribe("2")
+	defer cancelSecond()
+
+	hub.NotifyFinal([]string{"1"})
+
+	select {
+	case <-first:
+	default:
+		t.Fatal("first waiter should be notified")
+	}
+
+	select {
+	case <-second:
+		t.Fatal("second waiter should not be notified")
+	default:
+	}
+}
@@ -0,0 +1,45 @@
+package application
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"currency-rate-app/internal/infrastructure/db"
+)
+
+type SubscriptionSchedulerService struct {
+	repo db.SubscriptionRepository
+	now  func() time.Time
+}
+
+func NewSubscriptionSchedulerService(repo db.SubscriptionRepository) *SubscriptionSchedulerService {
+	return &SubscriptionSchedulerService{repo: repo, now: time.Now}
+}
+
+// EnqueueDueSubscriptions turns due subscriptions into PENDING rate requests,
+// which the rates job then processes like any other request.
+func (s *SubscriptionSchedulerService) EnqueueDueSubscriptions(ctx context.Context, batchSize int) {
+	enqueued, err := s.repo.EnqueueDueSubscriptions(ctx, s.now(), batchSize)
+
+	if err != nil {
+		slog.ErrorContext(
+			ctx,
+			"Subscriptions enqueue failed",
+			slog.String("error", err.Error()),
+		)
+
+		return
+	}
+
+	for _, sub := range enqueued {
+		slog.InfoContext(
+			ctx,
+			"Subscription enqueued",
+			slog.String("subscriptionId", sub.Id),
+			slog.String("baseCurrency", string(sub.BaseCurrency)),
+			slog.String("resultCurrency", string(sub.ResultCurrency)),
+			slog.Time("nextRunAt", sub.NextRunAt),
+		)
+	}
+}
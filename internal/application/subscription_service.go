@@ -0,0 +1,54 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"currency-rate-app/internal/domains/currency"
+	"currency-rate-app/internal/domains/subscription"
+	"currency-rate-app/internal/infrastructure/db"
+)
+
+type SubscriptionService interface {
+	CreateSubscription(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, interval time.Duration) (*subscription.Subscription, error)
+	ListSubscriptions(ctx context.Context) ([]subscription.Subscription, error)
+	GetSubscriptionById(ctx context.Context, id string) (*subscription.Subscription, error)
+	DeleteSubscription(ctx context.Context, id string) error
+}
+
+type subscriptionServiceImpl struct {
+	repo db.SubscriptionRepository
+}
+
+func NewSubscriptionService(repo db.SubscriptionRepository) SubscriptionService {
+	return &subscriptionServiceImpl{repo: repo}
+}
+
+func (s *subscriptionServiceImpl) CreateSubscription(
+	ctx context.Context,
+	baseCurrency currency.CurrencyCode,
+	resultCurrency currency.CurrencyCode,
+	interval time.Duration,
+) (*subscription.Subscription, error) {
+	if err := currency.ValidateCurrencyPair(baseCurrency, resultCurrency); err != nil {
+		return nil, err
+	}
+
+	if err := subscription.ValidateInterval(interval); err != nil {
+		return nil, err
+	}
+
+	return s.repo.CreateSubscription(ctx, baseCurrency, resultCurrency, interval)
+}
+
+func (s *subscriptionServiceImpl) ListSubscriptions(ctx context.Context) ([]subscription.Subscription, error) {
+	return s.repo.ListSubscriptions(ctx)
+}
+
+func (s *subscriptionServiceImpl) GetSubscriptionById(ctx context.Context, id string) (*subscription.Subscription, error) {
+	return s.repo.GetSubscriptionById(ctx, id)
+}
+
+func (s *subscriptionServiceImpl) DeleteSubscription(ctx context.Context, id string) error {
+	return s.repo.DeleteSubscription(ctx, id)
+}
@@ -0,0 +1,75 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"currency-rate-app/internal/domains/currency"
+	"currency-rate-app/internal/domains/subscription"
+	"currency-rate-app/internal/infrastructure/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSubscriptionRepository struct {
+	db.SubscriptionRepository
+	createFn  func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, interval time.Duration) (*subscription.Subscription, error)
+	enqueueFn func(ctx context.Context, now time.Time, limit int) ([]subscription.Subscription, error)
+}
+
+func (m *mockSubscriptionRepository) CreateSubscription(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, interval time.Duration) (*subscription.Subscription, error) {
+	return m.createFn(ctx, base, result, interval)
+}
+
+func (m *mockSubscriptionRepository) EnqueueDueSubscriptions(ctx context.Context, now time.Time, limit int) ([]subscription.Subscription, error) {
+	return m.enqueueFn(ctx, now, limit)
+}
+
+func TestCreateSubscription_Success(t *testing.T) {
+	repo := &mockSubscriptionRepository{
+		createFn: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, interval time.Duration) (*subscription.Subscription, error) {
+			return &subscription.Subscription{Id: "sub-1", BaseCurrency: base, ResultCurrency: result, Interval: interval}, nil
+		},
+	}
+	svc := NewSubscriptionService(repo)
+
+	res, err := svc.CreateSubscription(context.Background(), currency.EUR, currency.USD, 5*time.Minute)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "sub-1", res.Id)
+	assert.Equal(t, 5*time.Minute, res.Interval)
+}
+
+func TestCreateSubscription_Validation(t *testing.T) {
+	svc := NewSubscriptionService(&mockSubscriptionRepository{})
+
+	_, err := svc.CreateSubscription(context.Background(), currency.EUR, currency.EUR, time.Hour)
+	assert.Equal(t, currency.ErrCurrenciesShouldDiffer(), err)
+
+	_, err = svc.CreateSubscription(context.Background(), currency.EUR, currency.USD, 30*time.Second)
+	assert.Equal(t, subscription.ErrInvalidSubscriptionInterval(), err)
+
+	_, err = svc.CreateSubscription(context.Background(), currency.EUR, currency.USD, 8*24*time.Hour)
+	assert.Equal(t, subscription.ErrInvalidSubscriptionInterval(), err)
+}
+
+func TestEnqueueDueSubscriptions(t *testing.T) {
+	now := time.Date(2024, 10, 2, 12, 0, 0, 0, time.UTC)
+	var gotNow time.Time
+	var gotLimit int
+	repo := &mockSubscriptionRepository{
+		enqueueFn: func(ctx context.Context, now time.Time, limit int) ([]subscription.Subscription, error) {
+			gotNow = now
+			gotLimit = limit
+			return []subscription.Subscription{{Id: "sub-1", BaseCurrency: currency.EUR, ResultCurrency: currency.USD}}, nil
+		},
+	}
+	svc := NewSubscriptionSchedulerService(repo)
+	svc.now = func() time.Time { return now }
+
+	svc.EnqueueDueSubscriptions(context.Background(), 50)
+
+	assert.Equal(t, now, gotNow)
+	assert.Equal(t, 50, gotLimit)
+}
@@ -0,0 +1,202 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"currency-rate-app/internal/domains/currency"
+	"currency-rate-app/internal/infrastructure/db"
+	rates_api "currency-rate-app/internal/infrastructure/http/rates-api"
+)
+
+type TimeSeriesService interface {
+	GetTimeSeries(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, from time.Time, to time.Time) (*currency.TimeSeries, error)
+}
+
+type timeSeriesServiceImpl struct {
+	repo         db.CurrencyRepository
+	ratesService rates_api.RateService
+
+	// closedDays are business days the provider published no rate for
+	// (holidays), grouped by pair and year. They would otherwise look missing
+	// and be fetched again on every request. At most closedDaysLimit groups
+	// are kept, the oldest one is dropped first.
+	mu              sync.Mutex
+	closedDays      map[string]map[string]bool
+	closedDaysOrder []string
+	closedDaysLimit int
+}
+
+// maxClosedDaysGroups bounds the holiday cache, a group holds the closed days
+// of one pair in one year.
+const maxClosedDaysGroups = 1024
+
+func NewTimeSeriesService(
+	repo db.CurrencyRepository,
+	rateApi rates_api.RateService,
+) TimeSeriesService {
+	return &timeSeriesServiceImpl{
+		repo:            repo,
+		ratesService:    rateApi,
+		closedDays:      map[string]map[string]bool{},
+		closedDaysLimit: maxClosedDaysGroups,
+	}
+}
+
+func (s *timeSeriesServiceImpl) GetTimeSeries(
+	ctx context.Context,
+	baseCurrency currency.CurrencyCode,
+	resultCurrency currency.CurrencyCode,
+	from time.Time,
+	to time.Time,
+) (*currency.TimeSeries, error) {
+	if err := currency.ValidateDateRange(from, to); err != nil {
+		return nil, err
+	}
+
+	points, err := s.repo.GetRateHistory(ctx, baseCurrency, resultCurrency, from, to)
+
+	if err != nil {
+		return nil, err
+	}
+
+	missing := s.withoutClosedDays(baseCurrency, resultCurrency, currency.MissingBusinessDays(from, to, points))
+
+	if len(missing) > 0 {
+		filled, err := s.fillFromProvider(ctx, baseCurrency, resultCurrency, missing, points)
+
+		if err != nil {
+			return nil, err
+		}
+
+		points = append(points, filled...)
+
+		sort.Slice(points, func(i, j int) bool { return points[i].Date.Before(points[j].Date) })
+	}
+
+	return &currency.TimeSeries{
+		BaseCurrency:   baseCurrency,
+		ResultCurrency: resultCurrency,
+		From:           from,
+		To:             to,
+		Points:         points,
+	}, nil
+}
+
+// fillFromProvider asks the provider for the span of missing days in one call
+// and keeps only dates that aren't stored yet.
+func (s *timeSeriesServiceImpl) fillFromProvider(
+	ctx context.Context,
+	baseCurrency currency.CurrencyCode,
+	resultCurrency currency.CurrencyCode,
+	missing []time.Time,
+	stored []currency.TimeSeriesPoint,
+) ([]currency.TimeSeriesPoint, error) {
+	series, err := s.ratesService.FetchTimeSeries(baseCurrency, resultCurrency, missing[0], missing[len(missing)-1])
+
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(stored))
+	for _, p := range stored {
+		known[p.Date.Format(currency.RateDateLayout)] = true
+	}
+
+	s.rememberClosedDays(baseCurrency, resultCurrency, missing, series)
+
+	var filled []currency.TimeSeriesPoint
+
+	for date, rate := range series.Rates {
+		if known[date] {
+			continue
+		}
+
+		day, err := time.Parse(currency.RateDateLayout, date)
+
+		if err != nil {
+			continue
+		}
+
+		filled = append(filled, currency.TimeSeriesPoint{Date: day, Rate: rate, FromProvider: true, Provider: series.Provider})
+	}
+
+	if err := s.repo.SaveRateHistory(ctx, baseCurrency, resultCurrency, filled); err != nil {
+		slog.ErrorContext(ctx, "Rate history save failed", slog.String("error", err.Error()))
+	}
+
+	return filled, nil
+}
+
+// rememberClosedDays records missing days the provider skipped. Only days
+// before the latest published one count, later ones may not be published yet.
+func (s *timeSeriesServiceImpl) rememberClosedDays(
+	baseCurrency currency.CurrencyCode,
+	resultCurrency currency.CurrencyCode,
+	missing []time.Time,
+	series *rates_api.TimeSeries,
+) {
+	var latest string
+
+	for date := range series.Rates {
+		if date > latest {
+			latest = date
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, day := range missing {
+		date := day.Format(currency.RateDateLayout)
+
+		if date >= latest {
+			break
+		}
+
+		if _, ok := series.Rates[date]; ok {
+			continue
+		}
+
+		key := closedDaysKey(baseCurrency, resultCurrency, day)
+
+		if s.closedDays[key] == nil {
+			if len(s.closedDaysOrder) >= s.closedDaysLimit {
+				delete(s.closedDays, s.closedDaysOrder[0])
+				s.closedDaysOrder = s.closedDaysOrder[1:]
+			}
+
+			s.closedDays[key] = map[string]bool{}
+			s.closedDaysOrder = append(s.closedDaysOrder, key)
+		}
+
+		s.closedDays[key][date] = true
+	}
+}
+
+func (s *timeSeriesServiceImpl) withoutClosedDays(
+	baseCurrency currency.CurrencyCode,
+	resultCurrency currency.CurrencyCode,
+	missing []time.Time,
+) []time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	open := make([]time.Time, 0, len(missing))
+
+	for _, day := range missing {
+		if !s.closedDays[closedDaysKey(baseCurrency, resultCurrency, day)][day.Format(currency.RateDateLayout)] {
+			open = append(open, day)
+		}
+	}
+
+	return open
+}
+
+func closedDaysKey(baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, day time.Time) string {
+	return fmt.Sprintf("%s/%s/%d", baseCurrency, resultCurrency, day.Year())
+}
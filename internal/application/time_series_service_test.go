@@ -0,0 +1,176 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"currency-rate-app/internal/domains/currency"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func day(value string) time.Time {
+	d, _ := time.Parse(currency.RateDateLayout, value)
+	return d
+}
+
+func TestTimeSeriesService_FillsGaps(t *testing.T) {
+	ctx := context.Background()
+
+	// 2024-01-05 is a Friday, 2024-01-08 is a Monday
+	stored := []currency.TimeSeriesPoint{
+		{Date: day("2024-01-04"), Rate: decimal.RequireFromString("0.91")},
+		{Date: day("2024-01-08"), Rate: decimal.RequireFromString("0.92")},
+	}
+
+	var saved []currency.TimeSeriesPoint
+	repo := &mockRepo{
+		getRateHistory: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, from time.Time, to time.Time) ([]currency.TimeSeriesPoint, error) {
+			return stored, nil
+		},
+		saveRateHistory: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, points []currency.TimeSeriesPoint) error {
+			saved = points
+			return nil
+		},
+	}
+
+	var requestedFrom, requestedTo time.Time
+	rateService := &mockRateService{
+		fetchTimeSeriesFunc: func(baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, from time.Time, to time.Time) (map[string]decimal.Decimal, error) {
+			requestedFrom, requestedTo = from, to
+			return map[string]decimal.Decimal{
+				"2024-01-05": decimal.RequireFromString("0.915"),
+				"2024-01-08": decimal.RequireFromString("0.999"),
+			}, nil
+		},
+	}
+
+	service := NewTimeSeriesService(repo, rateService)
+
+	res, err := service.GetTimeSeries(ctx, currency.USD, currency.EUR, day("2024-01-04"), day("2024-01-08"))
+
+	assert.Nil(t, err)
+	assert.Equal(t, day("2024-01-05"), requestedFrom)
+	assert.Equal(t, day("2024-01-05"), requestedTo)
+
+	expected := []currency.TimeSeriesPoint{
+		{Date: day("2024-01-04"), Rate: decimal.RequireFromString("0.91")},
+		{Date: day("2024-01-05"), Rate: decimal.RequireFromString("0.915"), FromProvider: true, Provider: "mock"},
+		{Date: day("2024-01-08"), Rate: decimal.RequireFromString("0.92")},
+	}
+	assert.Equal(t, expected, res.Points)
+	assert.Equal(t, expected[1:2], saved)
+}
+
+func TestTimeSeriesService_StoredOnly(t *testing.T) {
+	ctx := context.Background()
+
+	repo := &mockRepo{
+		getRateHistory: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, from time.Time, to time.Time) ([]currency.TimeSeriesPoint, error) {
+			return []currency.TimeSeriesPoint{{Date: day("2024-01-05"), Rate: decimal.RequireFromString("0.91")}}, nil
+		},
+	}
+	rateService := &mockRateService{
+		fetchTimeSeriesFunc: func(baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, from time.Time, to time.Time) (map[string]decimal.Decimal, error) {
+			t.Fatal("provider should not be called when there are no gaps")
+			return nil, nil
+		},
+	}
+
+	service := NewTimeSeriesService(repo, rateService)
+
+	res, err := service.GetTimeSeries(ctx, currency.USD, currency.EUR, day("2024-01-05"), day("2024-01-07"))
+
+	assert.Nil(t, err)
+	assert.Len(t, res.Points, 1)
+}
+
+func TestTimeSeriesService_InvalidRange(t *testing.T) {
+	service := NewTimeSeriesService(&mockRepo{}, &mockRateService{})
+
+	_, err := service.GetTimeSeries(context.Background(), currency.USD, currency.EUR, day("2024-02-01"), day("2024-01-01"))
+	assert.Equal(t, currency.ErrInvalidDateRange(), err)
+
+	_, err = service.GetTimeSeries(context.Background(), currency.USD, currency.EUR, day("2022-01-01"), day("2024-01-01"))
+	assert.Equal(t, currency.ErrInvalidDateRange(), err)
+}
+
+func TestTimeSeriesService_HolidaysAreNotRefetched(t *testing.T) {
+	ctx := context.Background()
+
+	repo := &mockRepo{
+		getRateHistory: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, from time.Time, to time.Time) ([]currency.TimeSeriesPoint, error) {
+			return nil, nil
+		},
+		saveRateHistory: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, points []currency.TimeSeriesPoint) error {
+			return nil
+		},
+	}
+
+	var requests [][2]time.Time
+	rateService := &mockRateService{
+		fetchTimeSeriesFunc: func(baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, from time.Time, to time.Time) (map[string]decimal.Decimal, error) {
+			requests = append(requests, [2]time.Time{from, to})
+			// 2024-01-01 is a holiday, 2024-01-03 isn't published yet
+			return map[string]decimal.Decimal{"2024-01-02": decimal.RequireFromString("0.91")}, nil
+		},
+	}
+
+	service := NewTimeSeriesService(repo, rateService)
+
+	for range 2 {
+		res, err := service.GetTimeSeries(ctx, currency.USD, currency.EUR, day("2024-01-01"), day("2024-01-03"))
+
+		assert.Nil(t, err)
+		assert.Len(t, res.Points, 1)
+	}
+
+	assert.Equal(t, [][2]time.Time{
+		{day("2024-01-01"), day("2024-01-03")},
+		{day("2024-01-02"), day("2024-01-03")},
+	}, requests, "the holiday is skipped, the unpublished day is asked for again")
+}
+
+func TestTimeSeriesService_ClosedDaysAreBounded(t *testing.T) {
+	ctx := context.Background()
+
+	repo := &mockRepo{
+		getRateHistory: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, from time.Time, to time.Time) ([]currency.TimeSeriesPoint, error) {
+			return nil, nil
+		},
+		saveRateHistory: func(ctx context.Context, base currency.CurrencyCode, result currency.CurrencyCode, points []currency.TimeSeriesPoint) error {
+			return nil
+		},
+	}
+
+	calls := 0
+	rateService := &mockRateService{
+		fetchTimeSeriesFunc: func(baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, from time.Time, to time.Time) (map[string]decimal.Decimal, error) {
+			calls++
+			// The first day of every range is a holiday
+			return map[string]decimal.Decimal{to.Format(currency.RateDateLayout): decimal.RequireFromString("0.91")}, nil
+		},
+	}
+
+	service := NewTimeSeriesService(repo, rateService)
+	service.(*timeSeriesServiceImpl).closedDaysLimit = 2
+
+	// 2022-01-03, 2023-01-02 and 2024-01-01 are Mondays, one group per year
+	for _, from := range []string{"2022-01-03", "2023-01-02", "2024-01-01"} {
+		_, err := service.GetTimeSeries(ctx, currency.USD, currency.EUR, day(from), day(from).AddDate(0, 0, 1))
+		assert.Nil(t, err)
+	}
+
+	assert.Len(t, service.(*timeSeriesServiceImpl).closedDays, 2)
+	assert.Equal(t, 3, calls)
+
+	_, err := service.GetTimeSeries(ctx, currency.USD, currency.EUR, day("2022-01-03"), day("2022-01-03"))
+	assert.Nil(t, err)
+	assert.Equal(t, 4, calls, "the oldest group was dropped, its holiday is asked for again")
+
+	_, err = service.GetTimeSeries(ctx, currency.USD, currency.EUR, day("2024-01-01"), day("2024-01-01"))
+	assert.Nil(t, err)
+	assert.Equal(t, 4, calls, "the newest group is kept")
+}
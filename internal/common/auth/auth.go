@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"context"
+)
+
+type ctxKey string
+
+const (
+	actorKey  ctxKey = "Actor"
+	clientKey ctxKey = "Client"
+)
+
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+func ActorFromContext(ctx context.Context) (string, bool) {
+	v := ctx.Value(actorKey)
+	if v == nil {
+		return "", false
+	}
+	return v.(string), true
+}
+
+// WithClient marks the request as sent by an API client whose key was
+// verified.
+func WithClient(ctx context.Context, clientId string) context.Context {
+	return context.WithValue(ctx, clientKey, clientId)
+}
+
+func ClientFromContext(ctx context.Context) (string, bool) {
+	v := ctx.Value(clientKey)
+	if v == nil {
+		return "", false
+	}
+	return v.(string), true
+}
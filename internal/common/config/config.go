@@ -17,6 +17,30 @@ type Config struct {
 	RatesUpdateCronInSeconds int `env:"RATES_UPDATE_CRON_IN_SECONDS" validate:"required,min=1,max=60000"`
 	RatesUpdateBatchSize     int `env:"RATES_UPDATE_BATCH_SIZE" validate:"required,min=1,max=100"`
 
+	// Provider failures return the rate to PENDING with exponential backoff,
+	// the rate is FAILED after RATES_MAX_ATTEMPTS fetches
+	RatesMaxAttempts             int `env:"RATES_MAX_ATTEMPTS" env-default:"5" validate:"min=1,max=50"`
+	RatesInitialBackoffInSeconds int `env:"RATES_INITIAL_BACKOFF_IN_SECONDS" env-default:"10" validate:"min=1"`
+	RatesMaxBackoffInSeconds     int `env:"RATES_MAX_BACKOFF_IN_SECONDS" env-default:"600" validate:"min=1"`
+
+	// Lease of rates claimed by the worker. Must be longer than a provider call
+	// (two with triangulation), PROCESSING rates past it are reclaimed
+	RatesLeaseInSeconds      int `env:"RATES_LEASE_IN_SECONDS" env-default:"120" validate:"min=1"`
+	RatesReaperCronInSeconds int `env:"RATES_REAPER_CRON_IN_SECONDS" env-default:"30" validate:"min=1,max=60000"`
+	RatesReaperBatchSize     int `env:"RATES_REAPER_BATCH_SIZE" env-default:"100" validate:"min=1,max=1000"`
+	// Defaults to <hostname>-<pid>
+	RatesWorkerId string `env:"RATES_WORKER_ID"`
+
+	// Upper bound for Prefer: wait=N and ?wait=N on rate requests
+	RatesMaxWaitInSeconds int `env:"RATES_MAX_WAIT_IN_SECONDS" env-default:"30" validate:"min=1,max=300"`
+
+	// Delay before reconnecting the LISTEN connection behind /v1/currencies/stream
+	RatesStreamListenerRetryInSeconds int `env:"RATES_STREAM_LISTENER_RETRY_IN_SECONDS" env-default:"5" validate:"min=1,max=300"`
+
+	// Subscriptions scheduler
+	SubscriptionsSchedulerCronInSeconds int `env:"SUBSCRIPTIONS_SCHEDULER_CRON_IN_SECONDS" env-default:"10" validate:"min=1,max=60000"`
+	SubscriptionsSchedulerBatchSize     int `env:"SUBSCRIPTIONS_SCHEDULER_BATCH_SIZE" env-default:"100" validate:"min=1,max=1000"`
+
 	// Http Clients
 	HttpClientsDefaultTimeoutInSeconds int `env:"HTTP_CLIENTS_DEFAULT_TIMEOUT_IN_SECONDS"`
 
@@ -28,8 +52,56 @@ type Config struct {
 	DatabasePort     uint16 `env:"DATABASE_PORT" validate:"required"`
 
 	// Rates API
-	RatesApiType      string `env:"RATES_API_TYPE" validate:"required"`
-	FrankfurterApiURL string `env:"FRANKFURTER_API_URL" validate:"required"`
+	// Providers in failover order, Frankfurter by default. Mock can only be used
+	// alone. A provider failing RATES_PROVIDER_FAILURE_THRESHOLD times in a row
+	// is skipped for the cooldown
+	RatesApiProviders []string `env:"RATES_API_PROVIDERS" validate:"min=1,dive,oneof=Frankfurter Mock"`
+	// Deprecated: single provider, read when RATES_API_PROVIDERS isn't set
+	RatesApiType                   string `env:"RATES_API_TYPE" validate:"omitempty,oneof=Frankfurter Mock"`
+	FrankfurterApiURL              string `env:"FRANKFURTER_API_URL" validate:"required"`
+	RatesProviderFailureThreshold  int    `env:"RATES_PROVIDER_FAILURE_THRESHOLD" env-default:"3" validate:"min=1"`
+	RatesProviderCooldownInSeconds int    `env:"RATES_PROVIDER_COOLDOWN_IN_SECONDS" env-default:"60" validate:"min=1"`
+
+	// Pivot currency for cross rates, triangulation is disabled when empty
+	RatesTriangulationPivot string `env:"RATES_TRIANGULATION_PIVOT" validate:"omitempty,len=3"`
+
+	// Freshness of actual rates, 0 disables the check, up to one year like the
+	// maxAge query. Per pair format: EURUSD:3600,USDMXN:600
+	RatesMaxAgeDefaultInSeconds int            `env:"RATES_MAX_AGE_DEFAULT_IN_SECONDS" validate:"min=0,max=31622400"`
+	RatesMaxAgeByPairInSeconds  map[string]int `env:"RATES_MAX_AGE_BY_PAIR_IN_SECONDS" validate:"dive,min=0,max=31622400"`
+
+	// Pricing, spreads without an admin rule for the pair or currency
+	PricingDefaultBidBps int `env:"PRICING_DEFAULT_BID_BPS" validate:"min=0,max=9999"`
+	PricingDefaultAskBps int `env:"PRICING_DEFAULT_ASK_BPS" validate:"min=0,max=10000"`
+
+	// Webhooks (alerts and completion callbacks), signed with HMAC-SHA256 and
+	// sent to public addresses only. Sent unsigned when the secret is empty
+	WebhookTimeoutInSeconds int    `env:"WEBHOOK_TIMEOUT_IN_SECONDS" env-default:"10" validate:"min=1,max=60"`
+	WebhookSigningSecret    string `env:"WEBHOOK_SIGNING_SECRET"`
+
+	// Completion callbacks and alert firings, delivered by a job and retried with exponential backoff
+	CallbacksDeliveryCronInSeconds   int `env:"CALLBACKS_DELIVERY_CRON_IN_SECONDS" env-default:"5" validate:"min=1,max=60000"`
+	CallbacksDeliveryBatchSize       int `env:"CALLBACKS_DELIVERY_BATCH_SIZE" env-default:"50" validate:"min=1,max=1000"`
+	CallbacksMaxAttempts             int `env:"CALLBACKS_MAX_ATTEMPTS" env-default:"8" validate:"min=1,max=50"`
+	CallbacksInitialBackoffInSeconds int `env:"CALLBACKS_INITIAL_BACKOFF_IN_SECONDS" env-default:"10" validate:"min=1"`
+	CallbacksMaxBackoffInSeconds     int `env:"CALLBACKS_MAX_BACKOFF_IN_SECONDS" env-default:"3600" validate:"min=1"`
+	// Must be longer than WEBHOOK_TIMEOUT_IN_SECONDS, otherwise a slow attempt can be sent twice
+	CallbacksLeaseInSeconds int `env:"CALLBACKS_LEASE_IN_SECONDS" env-default:"60" validate:"min=1"`
+
+	// Idempotency-Key on mutating requests: responses are replayed for the TTL.
+	// The lock timeout frees keys of requests that never finished, it must be
+	// longer than RATES_MAX_WAIT_IN_SECONDS
+	IdempotencyTTLInSeconds         int `env:"IDEMPOTENCY_TTL_IN_SECONDS" env-default:"86400" validate:"min=1"`
+	IdempotencyLockTimeoutInSeconds int `env:"IDEMPOTENCY_LOCK_TIMEOUT_IN_SECONDS" env-default:"60" validate:"min=1,gtfield=RatesMaxWaitInSeconds"`
+	IdempotencyCleanupCronInSeconds int `env:"IDEMPOTENCY_CLEANUP_CRON_IN_SECONDS" env-default:"300" validate:"min=1,max=60000"`
+	IdempotencyCleanupBatchSize     int `env:"IDEMPOTENCY_CLEANUP_BATCH_SIZE" env-default:"1000" validate:"min=1,max=10000"`
+
+	// API clients sending X-Api-Key, format client-a:key-a,client-b:key-b.
+	// Idempotency-Key is only accepted from them
+	ApiClientKeys map[string]string `env:"API_CLIENT_KEYS"`
+
+	// Admin API, every admin request is rejected when empty
+	AdminApiToken string `env:"ADMIN_API_TOKEN"`
 }
 
 func Load() *Config {
@@ -39,6 +111,8 @@ func Load() *Config {
 		panic(err)
 	}
 
+	cfg.resolveRatesApiProviders()
+
 	err = validation.GetValidator().Struct(cfg)
 
 	if err != nil {
@@ -52,6 +126,22 @@ func Load() *Config {
 	return &cfg
 }
 
+// resolveRatesApiProviders keeps deployments that still set the single
+// RATES_API_TYPE on the provider they chose.
+func (cfg *Config) resolveRatesApiProviders() {
+	if len(cfg.RatesApiProviders) > 0 {
+		return
+	}
+
+	if cfg.RatesApiType != "" {
+		cfg.RatesApiProviders = []string{cfg.RatesApiType}
+
+		return
+	}
+
+	cfg.RatesApiProviders = []string{"Frankfurter"}
+}
+
 func readEnv() (Config, error) {
 	var cfg Config
 	var err error
@@ -9,13 +9,17 @@ const (
 	ErrorCodeBadRequest    ErrorType = "ErrorCodeBadRequest"
 	ErrorCodeBusinessLogic ErrorType = "ErrorCodeBusinessLogic"
 	ErrorCodeUnexpected    ErrorType = "ErrorCodeUnexpected"
+	ErrorCodeUnauthorized  ErrorType = "ErrorCodeUnauthorized"
+	ErrorCodeConflict      ErrorType = "ErrorCodeConflict"
 )
 
 type CustomError struct {
 	ErrorType ErrorType
 	Code      string
 	Message   string
-	Err       error
+	// Details is sent along with the code, e.g. what exactly failed.
+	Details any
+	Err     error
 }
 
 func (c *CustomError) Error() string {
@@ -52,3 +56,10 @@ func ErrBusinessLogic(code string) error {
 		Code:      code,
 	}
 }
+
+func ErrUnauthorized() error {
+	return &CustomError{
+		ErrorType: ErrorCodeUnauthorized,
+		Code:      "Unauthorized",
+	}
+}
@@ -0,0 +1,100 @@
+package http_client
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var ErrPrivateAddress = errors.New("target address is not public")
+
+// IsPublicAddr reports whether addr is routable on the internet, so that user
+// supplied URLs can't reach loopback, private, link-local (cloud metadata),
+// CGNAT or multicast addresses.
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	if !addr.IsValid() ||
+		addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() ||
+		addr.IsUnspecified() {
+		return false
+	}
+
+	return !sharedAddressSpace.Contains(addr)
+}
+
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// ValidatePublicUrl is the cheap check done when a URL is accepted: http(s),
+// a host, no credentials, and no literal non-public IP or localhost. DNS is
+// resolved only when dialing, by NewPublicTransport.
+func ValidatePublicUrl(rawUrl string) error {
+	target, err := url.Parse(rawUrl)
+
+	if err != nil {
+		return err
+	}
+
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return fmt.Errorf("unsupported scheme %q", target.Scheme)
+	}
+
+	if target.User != nil {
+		return errors.New("credentials in url are not allowed")
+	}
+
+	host := target.Hostname()
+
+	if host == "" {
+		return errors.New("host is required")
+	}
+
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrPrivateAddress
+	}
+
+	if addr, err := netip.ParseAddr(host); err == nil && !IsPublicAddr(addr) {
+		return ErrPrivateAddress
+	}
+
+	return nil
+}
+
+// NewPublicTransport refuses connections to non-public addresses. The check
+// runs on the resolved IP right before connecting, which also covers DNS
+// rebinding and redirects.
+func NewPublicTransport(timeout time.Duration) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+
+			if err != nil {
+				return err
+			}
+
+			if !IsPublicAddr(addrPort.Addr()) {
+				return ErrPrivateAddress
+			}
+
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return transport
+}
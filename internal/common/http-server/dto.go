@@ -11,6 +11,7 @@ import (
 type HttpErrorResponse struct {
 	Code    string `json:"code"`
 	Message string `json:"message,omitempty"`
+	Details any    `json:"details,omitempty"`
 }
 
 func SendErrorResponse(w http.ResponseWriter, err error) {
@@ -23,6 +24,7 @@ func SendErrorResponse(w http.ResponseWriter, err error) {
 		response = HttpErrorResponse{
 			Code:    customerErr.Code,
 			Message: customerErr.Message,
+			Details: customerErr.Details,
 		}
 	} else {
 		status = http.StatusInternalServerError
@@ -54,6 +56,10 @@ func getHttpStatusByErrorType(errorType error_utils.ErrorType) int {
 		return http.StatusUnprocessableEntity
 	case error_utils.ErrorCodeUnexpected:
 		return http.StatusInternalServerError
+	case error_utils.ErrorCodeUnauthorized:
+		return http.StatusUnauthorized
+	case error_utils.ErrorCodeConflict:
+		return http.StatusConflict
 	}
 
 	return http.StatusInternalServerError
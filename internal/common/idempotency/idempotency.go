@@ -0,0 +1,63 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	error_utils "currency-rate-app/internal/common/error-utils"
+)
+
+const MaxKeyLength = 255
+
+// Response is what the first request with a key answered, replayed as is
+// on retries.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Record is a key reserved by a request. Response is nil while the request
+// is still in flight.
+type Record struct {
+	Scope       string
+	Key         string
+	RequestHash string
+	Response    *Response
+	ExpiresAt   time.Time
+	CreatedAt   time.Time
+}
+
+func (r Record) InFlight() bool {
+	return r.Response == nil
+}
+
+// Store keeps keys for every instance. A key in flight expires at its lock
+// timeout, so the key of a request that died with its instance frees up.
+type Store interface {
+	// Acquire reserves the key until lockedUntil. When an unexpired record
+	// already holds the key it is returned instead and acquired is false.
+	Acquire(ctx context.Context, scope string, key string, requestHash string, now time.Time, lockedUntil time.Time) (record *Record, acquired bool, err error)
+	// Complete stores the response of the request holding the key.
+	Complete(ctx context.Context, scope string, key string, requestHash string, response Response, expiresAt time.Time) error
+	// Release frees the key of a request that shouldn't be replayed.
+	Release(ctx context.Context, scope string, key string, requestHash string) error
+	DeleteExpired(ctx context.Context, now time.Time, limit int) (int64, error)
+}
+
+func ErrIdempotencyKeyReused() *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeConflict,
+		Code:      "IdempotencyKeyReused",
+		Message:   "Idempotency-Key was already used with a different request",
+	}
+}
+
+func ErrIdempotencyRequestInProgress() *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeConflict,
+		Code:      "IdempotencyRequestInProgress",
+		Message:   "A request with this Idempotency-Key is still in progress",
+	}
+}
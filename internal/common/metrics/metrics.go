@@ -0,0 +1,17 @@
+package metrics
+
+import "expvar"
+
+// Counters are published through expvar and served as JSON on
+// GET /v1/admin/metrics.
+var (
+	// RatesLeasesReclaimed counts PROCESSING rates returned to PENDING after
+	// the worker's lease expired.
+	RatesLeasesReclaimed = expvar.NewInt("rates_leases_reclaimed")
+	// RatesLeasesFailed counts rates moved to FAILED by the reaper because
+	// they had no attempts left.
+	RatesLeasesFailed = expvar.NewInt("rates_leases_failed")
+	// RatesProviderFailures counts failed provider calls by provider, each of
+	// them made the failover chain move on to the next provider.
+	RatesProviderFailures = expvar.NewMap("rates_provider_failures")
+)
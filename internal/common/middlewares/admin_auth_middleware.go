@@ -0,0 +1,40 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"currency-rate-app/internal/common/auth"
+	error_utils "currency-rate-app/internal/common/error-utils"
+	http_server "currency-rate-app/internal/common/http-server"
+)
+
+const adminActorHeader = "X-Admin-Actor"
+
+// NewAdminAuthMiddleware guards admin routes with a static bearer token. The
+// caller must name itself in X-Admin-Actor, every change is attributed to it.
+// An empty token disables the admin API entirely.
+func NewAdminAuthMiddleware(token string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			provided, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+			if token == "" || !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				http_server.SendErrorResponse(w, error_utils.ErrUnauthorized())
+
+				return
+			}
+
+			actor := strings.TrimSpace(r.Header.Get(adminActorHeader))
+
+			if actor == "" {
+				http_server.SendErrorResponse(w, error_utils.ErrValidationError(adminActorHeader+" header is required"))
+
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithActor(r.Context(), actor)))
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"currency-rate-app/internal/common/auth"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminAuthMiddleware(t *testing.T) {
+	var actor string
+	handler := NewAdminAuthMiddleware("secret")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor, _ = auth.ActorFromContext(r.Context())
+		okHandler(w, r)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/admin/spreads", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set(adminActorHeader, "alice")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "alice", actor)
+}
+
+func TestAdminAuthMiddleware_WrongToken(t *testing.T) {
+	handler := NewAdminAuthMiddleware("secret")(http.HandlerFunc(okHandler))
+
+	for _, header := range []string{"", "secret", "Bearer wrong"} {
+		req := httptest.NewRequest(http.MethodGet, "/v1/admin/spreads", nil)
+		req.Header.Set("Authorization", header)
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, header)
+	}
+}
+
+func TestAdminAuthMiddleware_RequiresActor(t *testing.T) {
+	calls := 0
+	handler := NewAdminAuthMiddleware("secret")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		okHandler(w, r)
+	}))
+
+	for _, actor := range []string{"", "  "} {
+		req := httptest.NewRequest(http.MethodPost, "/v1/admin/rates/id-1/requeue", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		req.Header.Set(adminActorHeader, actor)
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, actor)
+		assert.Contains(t, rec.Body.String(), adminActorHeader)
+	}
+
+	assert.Equal(t, 0, calls)
+}
+
+func TestAdminAuthMiddleware_EmptyTokenDisablesAdmin(t *testing.T) {
+	handler := NewAdminAuthMiddleware("")(http.HandlerFunc(okHandler))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/admin/spreads", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
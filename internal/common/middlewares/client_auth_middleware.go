@@ -0,0 +1,53 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"currency-rate-app/internal/common/auth"
+	error_utils "currency-rate-app/internal/common/error-utils"
+	http_server "currency-rate-app/internal/common/http-server"
+)
+
+const apiKeyHeader = "X-Api-Key"
+
+// NewClientAuthMiddleware identifies API clients by the X-Api-Key they were
+// given, keys maps client ids to their keys. Requests without a key pass
+// anonymously, requests with an unknown key are rejected.
+func NewClientAuthMiddleware(keys map[string]string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			provided := r.Header.Get(apiKeyHeader)
+
+			if provided == "" {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			clientId, ok := lookupClient(keys, provided)
+
+			if !ok {
+				http_server.SendErrorResponse(w, error_utils.ErrUnauthorized())
+
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithClient(r.Context(), clientId)))
+		})
+	}
+}
+
+// lookupClient compares the provided key with every configured key in
+// constant time.
+func lookupClient(keys map[string]string, provided string) (string, bool) {
+	var found string
+
+	for clientId, key := range keys {
+		if key != "" && subtle.ConstantTimeCompare([]byte(provided), []byte(key)) == 1 {
+			found = clientId
+		}
+	}
+
+	return found, found != ""
+}
@@ -0,0 +1,42 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"currency-rate-app/internal/common/auth"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientAuthMiddleware(t *testing.T) {
+	keys := map[string]string{"client-a": "key-a", "client-b": "key-b"}
+
+	cases := []struct {
+		key          string
+		expectedCode int
+		expectedId   string
+	}{
+		{key: "key-b", expectedCode: http.StatusOK, expectedId: "client-b"},
+		{key: "", expectedCode: http.StatusOK},
+		{key: "wrong", expectedCode: http.StatusUnauthorized},
+	}
+
+	for _, c := range cases {
+		var clientId string
+		handler := NewClientAuthMiddleware(keys)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			clientId, _ = auth.ClientFromContext(r.Context())
+			okHandler(w, r)
+		}))
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/currencies", nil)
+		req.Header.Set(apiKeyHeader, c.key)
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, c.expectedCode, rec.Code, c.key)
+		assert.Equal(t, c.expectedId, clientId, c.key)
+	}
+}
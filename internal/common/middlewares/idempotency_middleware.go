@@ -0,0 +1,195 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"currency-rate-app/internal/common/auth"
+	error_utils "currency-rate-app/internal/common/error-utils"
+	http_server "currency-rate-app/internal/common/http-server"
+	"currency-rate-app/internal/common/idempotency"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotencyReplayedHeader = "Idempotent-Replayed"
+	idempotencyMaxBodyBytes   = 1 << 20
+	// Callers without an API client key share one key space, as every caller
+	// did before API clients were introduced.
+	anonymousIdempotencyScope = "anonymous"
+)
+
+type IdempotencyConfig struct {
+	// TTL is how long a completed response is replayed.
+	TTL time.Duration
+	// LockTimeout frees the key of a request that never completed, e.g.
+	// because its instance died. Must be longer than the slowest handler.
+	LockTimeout time.Duration
+}
+
+// NewIdempotencyMiddleware makes mutating requests with an Idempotency-Key
+// safe to retry. The first request reserves the key together with a hash of
+// the request, retries with the same request get its stored response and
+// retries with another request or while the first is in flight get 409.
+// Keys are scoped per API client, see NewClientAuthMiddleware: a stored
+// response is only replayed to the client that made the request. Anonymous
+// callers share their own scope. 5xx responses aren't stored so the request
+// can be retried.
+func NewIdempotencyMiddleware(store idempotency.Store, config IdempotencyConfig) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(idempotencyKeyHeader)
+
+			if key == "" || !isMutatingMethod(r.Method) {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			if len(key) > idempotency.MaxKeyLength {
+				http_server.SendErrorResponse(w, error_utils.ErrValidationError("Idempotency key is too long"))
+
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, idempotencyMaxBodyBytes))
+
+			if err != nil {
+				http_server.SendErrorResponse(w, error_utils.ErrValidationError(err.Error()))
+
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			scope := idempotencyScope(r)
+			requestHash := hashRequest(r, body)
+			now := time.Now()
+
+			record, acquired, err := store.Acquire(r.Context(), scope, key, requestHash, now, now.Add(config.LockTimeout))
+
+			if err != nil {
+				http_server.SendErrorResponse(w, err)
+
+				return
+			}
+
+			if !acquired {
+				replayResponse(w, record, requestHash)
+
+				return
+			}
+
+			// The outcome is stored even if the client has gone away.
+			storeCtx := context.WithoutCancel(r.Context())
+			recorder := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+			completed := false
+
+			defer func() {
+				if completed {
+					return
+				}
+
+				if err := store.Release(storeCtx, scope, key, requestHash); err != nil {
+					slog.ErrorContext(storeCtx, "Idempotency key release failed", slog.String("error", err.Error()))
+				}
+			}()
+
+			next.ServeHTTP(recorder, r)
+
+			if recorder.statusCode >= http.StatusInternalServerError {
+				return
+			}
+
+			header := recorder.Header().Clone()
+			header.Del(traceIdHeader)
+
+			response := idempotency.Response{StatusCode: recorder.statusCode, Header: header, Body: recorder.body.Bytes()}
+
+			if err := store.Complete(storeCtx, scope, key, requestHash, response, time.Now().Add(config.TTL)); err != nil {
+				slog.ErrorContext(storeCtx, "Idempotency key completion failed", slog.String("error", err.Error()))
+			}
+
+			completed = true
+		})
+	}
+}
+
+func replayResponse(w http.ResponseWriter, record *idempotency.Record, requestHash string) {
+	if record.RequestHash != requestHash {
+		http_server.SendErrorResponse(w, idempotency.ErrIdempotencyKeyReused())
+
+		return
+	}
+
+	if record.InFlight() {
+		w.Header().Set("Retry-After", "1")
+		http_server.SendErrorResponse(w, idempotency.ErrIdempotencyRequestInProgress())
+
+		return
+	}
+
+	for name, values := range record.Response.Header {
+		w.Header()[name] = values
+	}
+
+	w.Header().Set(idempotencyReplayedHeader, "true")
+	w.WriteHeader(record.Response.StatusCode)
+	w.Write(record.Response.Body)
+}
+
+func isMutatingMethod(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch || method == http.MethodDelete
+}
+
+// hashRequest covers the method and target along with the body, so a key
+// reused for another endpoint is a mismatch too.
+func hashRequest(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder passes the response through and keeps a copy to store.
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	if !r.wroteHeader {
+		r.statusCode = statusCode
+		r.wroteHeader = true
+	}
+
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(data)
+
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func idempotencyScope(r *http.Request) string {
+	if clientId, ok := auth.ClientFromContext(r.Context()); ok {
+		return "client:" + clientId
+	}
+
+	return anonymousIdempotencyScope
+}
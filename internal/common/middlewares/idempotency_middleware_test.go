@@ -0,0 +1,250 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"currency-rate-app/internal/common/auth"
+	"currency-rate-app/internal/common/idempotency"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]*idempotency.Record
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{records: map[string]*idempotency.Record{}}
+}
+
+func (s *memoryIdempotencyStore) Acquire(ctx context.Context, scope string, key string, requestHash string, now time.Time, lockedUntil time.Time) (*idempotency.Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if record, ok := s.records[scope+"/"+key]; ok && record.ExpiresAt.After(now) {
+		copied := *record
+		return &copied, false, nil
+	}
+
+	record := &idempotency.Record{Scope: scope, Key: key, RequestHash: requestHash, ExpiresAt: lockedUntil, CreatedAt: now}
+	s.records[scope+"/"+key] = record
+
+	return record, true, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(ctx context.Context, scope string, key string, requestHash string, response idempotency.Response, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if record, ok := s.records[scope+"/"+key]; ok && record.RequestHash == requestHash {
+		record.Response = &response
+		record.ExpiresAt = expiresAt
+	}
+
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(ctx context.Context, scope string, key string, requestHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, scope+"/"+key)
+
+	return nil
+}
+
+func (s *memoryIdempotencyStore) DeleteExpired(ctx context.Context, now time.Time, limit int) (int64, error) {
+	return 0, nil
+}
+
+func newIdempotentHandler(store idempotency.Store, handler http.HandlerFunc) http.Handler {
+	return NewIdempotencyMiddleware(store, IdempotencyConfig{TTL: time.Hour, LockTimeout: time.Minute})(handler)
+}
+
+func idempotentRequest(method string, body string, key string) *http.Request {
+	req := httptest.NewRequest(method, "/v1/currencies", strings.NewReader(body))
+	req.Header.Set(idempotencyKeyHeader, key)
+
+	return req.WithContext(auth.WithClient(req.Context(), "client-a"))
+}
+
+func TestIdempotencyMiddleware_ReplaysStoredResponse(t *testing.T) {
+	calls := 0
+	handler := newIdempotentHandler(newMemoryIdempotencyStore(), func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Location", "/v1/currencies/id-1/status")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"id":"id-1"}`))
+	})
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, idempotentRequest(http.MethodPost, `{"baseCurrency":"USD"}`, "key-1"))
+
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, idempotentRequest(http.MethodPost, `{"baseCurrency":"USD"}`, "key-1"))
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusAccepted, second.Code)
+	assert.Equal(t, `{"id":"id-1"}`, second.Body.String())
+	assert.Equal(t, "/v1/currencies/id-1/status", second.Header().Get("Location"))
+	assert.Equal(t, "true", second.Header().Get(idempotencyReplayedHeader))
+	assert.Empty(t, first.Header().Get(idempotencyReplayedHeader))
+}
+
+func TestIdempotencyMiddleware_PayloadMismatch(t *testing.T) {
+	handler := newIdempotentHandler(newMemoryIdempotencyStore(), okHandler)
+
+	handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, `{"baseCurrency":"USD"}`, "key-1"))
+
+	cases := []*http.Request{
+		idempotentRequest(http.MethodPost, `{"baseCurrency":"EUR"}`, "key-1"),
+		idempotentRequest(http.MethodPut, `{"baseCurrency":"USD"}`, "key-1"),
+	}
+
+	for _, req := range cases {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusConflict, rec.Code, req.Method)
+		assert.Contains(t, rec.Body.String(), "IdempotencyKeyReused")
+	}
+}
+
+func TestIdempotencyMiddleware_InFlight(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := newIdempotentHandler(newMemoryIdempotencyStore(), func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, `{}`, "key-1"))
+		close(done)
+	}()
+	<-started
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, idempotentRequest(http.MethodPost, `{}`, "key-1"))
+
+	close(release)
+	<-done
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "IdempotencyRequestInProgress")
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+}
+
+func TestIdempotencyMiddleware_ServerErrorIsNotStored(t *testing.T) {
+	calls := 0
+	handler := newIdempotentHandler(newMemoryIdempotencyStore(), func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, idempotentRequest(http.MethodPost, `{}`, "key-1"))
+
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, idempotentRequest(http.MethodPost, `{}`, "key-1"))
+
+	assert.Equal(t, http.StatusInternalServerError, first.Code)
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyMiddleware_ScopedPerClient(t *testing.T) {
+	calls := 0
+	handler := newIdempotentHandler(newMemoryIdempotencyStore(), func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		okHandler(w, r)
+	})
+
+	for _, clientId := range []string{"client-a", "client-b"} {
+		req := idempotentRequest(http.MethodPost, `{}`, "key-1")
+		req = req.WithContext(auth.WithClient(req.Context(), clientId))
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get(idempotencyReplayedHeader))
+	}
+
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyMiddleware_AnonymousClientsShareScope(t *testing.T) {
+	calls := 0
+	handler := newIdempotentHandler(newMemoryIdempotencyStore(), func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		okHandler(w, r)
+	})
+
+	anonymous := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/v1/currencies", strings.NewReader(`{}`))
+		req.Header.Set(idempotencyKeyHeader, "key-1")
+
+		return req
+	}
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, anonymous())
+
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, anonymous())
+
+	client := httptest.NewRecorder()
+	handler.ServeHTTP(client, idempotentRequest(http.MethodPost, `{}`, "key-1"))
+
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "true", second.Header().Get(idempotencyReplayedHeader))
+	assert.Empty(t, client.Header().Get(idempotencyReplayedHeader), "API clients don't see anonymous keys")
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyMiddleware_SkipsWithoutKeyAndSafeMethods(t *testing.T) {
+	calls := 0
+	handler := newIdempotentHandler(newMemoryIdempotencyStore(), func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		okHandler(w, r)
+	})
+
+	for range 2 {
+		handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, `{}`, ""))
+		handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodGet, "", "key-1"))
+	}
+
+	assert.Equal(t, 4, calls)
+}
+
+func TestIdempotencyMiddleware_ExpiredKeyRunsAgain(t *testing.T) {
+	calls := 0
+	store := newMemoryIdempotencyStore()
+	handler := NewIdempotencyMiddleware(store, IdempotencyConfig{TTL: -time.Second, LockTimeout: time.Minute})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		okHandler(w, r)
+	}))
+
+	for range 2 {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, idempotentRequest(http.MethodPost, `{}`, "key-1"))
+
+		assert.Empty(t, rec.Header().Get(idempotencyReplayedHeader))
+	}
+
+	assert.Equal(t, 2, calls)
+}
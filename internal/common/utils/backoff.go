@@ -0,0 +1,19 @@
+package utils
+
+import "time"
+
+// Backoff is the delay before the attempt after the given failed one:
+// initial, 2*initial, 4*initial... capped by max.
+func Backoff(failedAttempts int, initial time.Duration, max time.Duration) time.Duration {
+	delay := initial
+
+	for i := 1; i < failedAttempts && delay < max; i++ {
+		delay *= 2
+	}
+
+	if delay > max {
+		return max
+	}
+
+	return delay
+}
@@ -0,0 +1,123 @@
+package alert
+
+import (
+	"net/url"
+	"time"
+
+	"currency-rate-app/internal/domains/currency"
+
+	"github.com/shopspring/decimal"
+)
+
+type Condition string
+
+const (
+	// ConditionAbove fires when the rate reaches the threshold from below.
+	ConditionAbove Condition = "ABOVE"
+	// ConditionBelow fires when the rate drops to the threshold from above.
+	ConditionBelow Condition = "BELOW"
+	// ConditionChangePercent fires when the rate moves by threshold percent or
+	// more against the oldest rate within the window, in either direction.
+	ConditionChangePercent Condition = "CHANGE_PERCENT"
+)
+
+func (c Condition) IsValid() bool {
+	return c == ConditionAbove || c == ConditionBelow || c == ConditionChangePercent
+}
+
+const (
+	MinWindow = time.Minute
+	MaxWindow = 7 * 24 * time.Hour
+)
+
+// AlertRule watches the latest rate of a pair. Triggered is set when the rule
+// fires and cleared once the condition stops holding, so a rule pages once per
+// crossing instead of on every refresh.
+type AlertRule struct {
+	Id             string
+	BaseCurrency   currency.CurrencyCode
+	ResultCurrency currency.CurrencyCode
+	Condition      Condition
+	Threshold      decimal.Decimal
+	Window         time.Duration
+	TargetUrl      string
+	Triggered      bool
+	LastFiredAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "PENDING"
+	DeliveryStatusDelivered DeliveryStatus = "DELIVERED"
+	DeliveryStatusFailed    DeliveryStatus = "FAILED"
+)
+
+// AlertFiring records one notification of a rule. ReferenceRate is the start
+// of the window for CHANGE_PERCENT rules.
+type AlertFiring struct {
+	Id             string
+	RuleId         string
+	Rate           decimal.Decimal
+	ReferenceRate  *decimal.Decimal
+	DeliveryStatus DeliveryStatus
+	Attempts       int
+	LastError      *string
+	FiredAt        time.Time
+}
+
+func ValidateAlertRule(rule AlertRule) error {
+	if err := currency.ValidateCurrencyPair(rule.BaseCurrency, rule.ResultCurrency); err != nil {
+		return err
+	}
+
+	if !rule.Condition.IsValid() {
+		return ErrInvalidAlertCondition()
+	}
+
+	if !rule.Threshold.IsPositive() {
+		return ErrInvalidAlertThreshold()
+	}
+
+	if rule.Condition == ConditionChangePercent {
+		if rule.Window < MinWindow || rule.Window > MaxWindow || rule.Window%time.Second != 0 {
+			return ErrInvalidAlertWindow()
+		}
+	} else if rule.Window != 0 {
+		return ErrInvalidAlertWindow()
+	}
+
+	target, err := url.Parse(rule.TargetUrl)
+
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return ErrInvalidAlertTargetUrl()
+	}
+
+	return nil
+}
+
+// Holds reports whether the condition of the rule is met by rate. reference is
+// only used by CHANGE_PERCENT rules, which never hold without one.
+func (r AlertRule) Holds(rate decimal.Decimal, reference *decimal.Decimal) bool {
+	switch r.Condition {
+	case ConditionAbove:
+		return rate.GreaterThanOrEqual(r.Threshold)
+	case ConditionBelow:
+		return rate.LessThanOrEqual(r.Threshold)
+	case ConditionChangePercent:
+		if reference == nil || reference.IsZero() {
+			return false
+		}
+
+		return ChangePercent(*reference, rate).Abs().GreaterThanOrEqual(r.Threshold)
+	}
+
+	return false
+}
+
+// ChangePercent is the move from reference to rate in percent.
+func ChangePercent(reference decimal.Decimal, rate decimal.Decimal) decimal.Decimal {
+	return rate.Sub(reference).Mul(decimal.NewFromInt(100)).DivRound(reference, currency.DerivedRatePrecision)
+}
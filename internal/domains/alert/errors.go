@@ -0,0 +1,40 @@
+package alert
+
+import (
+	error_utils "currency-rate-app/internal/common/error-utils"
+)
+
+func ErrAlertRuleNotFound() *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeNotFound,
+		Code:      "AlertRuleNotFound",
+	}
+}
+
+func ErrInvalidAlertCondition() *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeBadRequest,
+		Code:      "InvalidAlertCondition",
+	}
+}
+
+func ErrInvalidAlertThreshold() *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeBadRequest,
+		Code:      "InvalidAlertThreshold",
+	}
+}
+
+func ErrInvalidAlertWindow() *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeBadRequest,
+		Code:      "InvalidAlertWindow",
+	}
+}
+
+func ErrInvalidAlertTargetUrl() *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeBadRequest,
+		Code:      "InvalidAlertTargetUrl",
+	}
+}
@@ -0,0 +1,46 @@
+package callback
+
+import (
+	"time"
+)
+
+type Event string
+
+const (
+	EventRateCompleted Event = "currency_rate.completed"
+	EventRateFailed    Event = "currency_rate.failed"
+	EventRateCancelled Event = "currency_rate.cancelled"
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "PENDING"
+	DeliveryStatusDelivered DeliveryStatus = "DELIVERED"
+	DeliveryStatusFailed    DeliveryStatus = "FAILED"
+)
+
+// Delivery is one callback of a rate request, created in the same transaction
+// that moves the request to a final status and retried until it's delivered
+// or runs out of attempts.
+type Delivery struct {
+	Id            string
+	RateId        string
+	Url           string
+	Event         Event
+	Status        DeliveryStatus
+	Attempts      int
+	NextAttemptAt time.Time
+	DeliveredAt   *time.Time
+	CreatedAt     time.Time
+	Log           []Attempt
+}
+
+// Attempt is an entry of the delivery log.
+type Attempt struct {
+	Number     int
+	StatusCode *int
+	Error      *string
+	Duration   time.Duration
+	CreatedAt  time.Time
+}
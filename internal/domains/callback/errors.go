@@ -0,0 +1,13 @@
+package callback
+
+import (
+	error_utils "currency-rate-app/internal/common/error-utils"
+)
+
+func ErrInvalidCallbackUrl(msg string) *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeBadRequest,
+		Code:      "InvalidCallbackUrl",
+		Message:   msg,
+	}
+}
@@ -0,0 +1,121 @@
+package currency
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type RoundingMode string
+
+const (
+	RoundingModeHalfEven RoundingMode = "half-even"
+	RoundingModeHalfUp   RoundingMode = "half-up"
+	RoundingModeDown     RoundingMode = "down"
+)
+
+func (m RoundingMode) IsValid() bool {
+	switch m {
+	case RoundingModeHalfEven, RoundingModeHalfUp, RoundingModeDown:
+		return true
+	}
+
+	return false
+}
+
+const (
+	// MaxAmountLength bounds the amount before it is parsed at all.
+	MaxAmountLength = 64
+	// MaxAmountIntegerDigits and MaxAmountScale bound the parsed amount, an
+	// exponent like 1e1000000000 is short but expensive to multiply and print.
+	MaxAmountIntegerDigits = 20
+	MaxAmountScale         = 18
+)
+
+// ParseAmount parses a non-negative amount with at most MaxAmountIntegerDigits
+// digits before the point and MaxAmountScale after it.
+func ParseAmount(value string) (decimal.Decimal, error) {
+	if value == "" || len(value) > MaxAmountLength {
+		return decimal.Decimal{}, ErrInvalidAmount()
+	}
+
+	amount, err := decimal.NewFromString(value)
+
+	if err != nil || amount.IsNegative() {
+		return decimal.Decimal{}, ErrInvalidAmount()
+	}
+
+	if amount.IsZero() {
+		return decimal.Zero, nil
+	}
+
+	exponent := int64(amount.Exponent())
+
+	if exponent < -MaxAmountScale || int64(amount.NumDigits())+exponent > MaxAmountIntegerDigits {
+		return decimal.Decimal{}, ErrInvalidAmount()
+	}
+
+	return amount, nil
+}
+
+type Conversion struct {
+	From            CurrencyCode
+	To              CurrencyCode
+	Amount          decimal.Decimal
+	ConvertedAmount decimal.Decimal
+	RoundingMode    RoundingMode
+	Rate            decimal.Decimal
+	RateCompletedAt time.Time
+}
+
+// FormattedAmount renders the converted amount with exactly the target
+// currency's minor units, so 100 EUR reads as "100.00".
+func (c Conversion) FormattedAmount() string {
+	info, ok := LookupCurrency(c.To)
+
+	if !ok || info.MinorUnits == MinorUnitsNotApplicable {
+		return c.ConvertedAmount.String()
+	}
+
+	return c.ConvertedAmount.StringFixed(int32(info.MinorUnits))
+}
+
+// Convert multiplies amount by the rate and rounds the result to the minor
+// units of the target currency. Currencies without minor units (metals, funds)
+// are returned unrounded.
+func Convert(amount decimal.Decimal, rate CurrencyRate, mode RoundingMode) (*Conversion, error) {
+	if amount.IsNegative() {
+		return nil, ErrInvalidAmount()
+	}
+
+	if !mode.IsValid() {
+		return nil, ErrInvalidRoundingMode()
+	}
+
+	converted := amount.Mul(*rate.Rate)
+
+	if info, ok := LookupCurrency(rate.ResultCurrency); ok && info.MinorUnits != MinorUnitsNotApplicable {
+		converted = roundAmount(converted, int32(info.MinorUnits), mode)
+	}
+
+	return &Conversion{
+		From:            rate.BaseCurrency,
+		To:              rate.ResultCurrency,
+		Amount:          amount,
+		ConvertedAmount: converted,
+		RoundingMode:    mode,
+		Rate:            *rate.Rate,
+		RateCompletedAt: *rate.CompletedAt,
+	}, nil
+}
+
+func roundAmount(amount decimal.Decimal, places int32, mode RoundingMode) decimal.Decimal {
+	switch mode {
+	case RoundingModeHalfUp:
+		return amount.Round(places)
+	case RoundingModeDown:
+		return amount.Truncate(places)
+	}
+
+	return amount.RoundBank(places)
+}
@@ -2,6 +2,8 @@ package currency
 
 import (
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 type CurrencyCode string
@@ -13,12 +15,9 @@ const (
 )
 
 func (s CurrencyCode) IsValid() bool {
-	switch s {
-	case USD, EUR, MXN:
-		return true
-	}
+	_, ok := LookupCurrency(s)
 
-	return false
+	return ok
 }
 
 type CurrencyRateStatus string
@@ -28,18 +27,38 @@ const (
 	CurrencyRateStatusProcessing CurrencyRateStatus = "PROCESSING"
 	CurrencyRateStatusCompleted  CurrencyRateStatus = "COMPLETED"
 	CurrencyRateStatusFailed     CurrencyRateStatus = "FAILED"
+	// CurrencyRateStatusCancelled is set by the client while the request is
+	// still PENDING, the worker never picks it up.
+	CurrencyRateStatusCancelled CurrencyRateStatus = "CANCELLED"
 )
 
+// IsFinal reports whether the status can't change anymore.
+func (s CurrencyRateStatus) IsFinal() bool {
+	return s == CurrencyRateStatusCompleted || s == CurrencyRateStatusFailed || s == CurrencyRateStatusCancelled
+}
+
 type CurrencyRate struct {
-	Id             string
-	IdempotencyKey string
-	BaseCurrency   CurrencyCode
-	ResultCurrency CurrencyCode
-	Status         CurrencyRateStatus
-	Rate           *float64
-	CompletedAt    *time.Time
-	CreatedAt      time.Time
-	UpdatedAt      time.Time
+	Id                  string
+	IdempotencyKey      string
+	BaseCurrency        CurrencyCode
+	ResultCurrency      CurrencyCode
+	RateDate            *time.Time
+	Status              CurrencyRateStatus
+	Rate                *decimal.Decimal
+	PivotCurrency       *CurrencyCode
+	Provider            *string
+	Inverted            bool
+	Stale               bool
+	CallbackUrl         *string
+	Attempts            int
+	NextAttemptAt       *time.Time
+	WorkerId            *string
+	ProcessingStartedAt *time.Time
+	Failure             *RateFailure
+	ArchivedAt          *time.Time
+	CompletedAt         *time.Time
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
 }
 
 func ValidateCurrencyPair(baseCurrency CurrencyCode, resultCurrency CurrencyCode) error {
@@ -57,3 +76,68 @@ func ValidateCurrencyPair(baseCurrency CurrencyCode, resultCurrency CurrencyCode
 
 	return nil
 }
+
+// Age is how long ago the rate was completed, zero for incomplete rates.
+func (r CurrencyRate) Age(now time.Time) time.Duration {
+	if r.CompletedAt == nil {
+		return 0
+	}
+
+	return now.Sub(*r.CompletedAt)
+}
+
+// RetryAfter is how long a client polling the rate should wait before asking
+// again: the poll interval, or until the next attempt when it is scheduled
+// later. Zero for final rates.
+func (r CurrencyRate) RetryAfter(now time.Time, pollInterval time.Duration) time.Duration {
+	if r.Status.IsFinal() {
+		return 0
+	}
+
+	if r.NextAttemptAt != nil && r.NextAttemptAt.Sub(now) > pollInterval {
+		return r.NextAttemptAt.Sub(now)
+	}
+
+	return pollInterval
+}
+
+// RateLease identifies one claim of a rate by a worker. Updates of the worker
+// match the lease, so results of a claim that expired and was reclaimed by
+// another worker are dropped instead of overwriting the new claim.
+type RateLease struct {
+	WorkerId  string
+	StartedAt time.Time
+}
+
+// Lease is the claim the rate was fetched for processing with, zero for rates
+// that aren't claimed.
+func (r CurrencyRate) Lease() RateLease {
+	var lease RateLease
+
+	if r.WorkerId != nil {
+		lease.WorkerId = *r.WorkerId
+	}
+
+	if r.ProcessingStartedAt != nil {
+		lease.StartedAt = *r.ProcessingStartedAt
+	}
+
+	return lease
+}
+
+// MaxRateAge caps the maxAge of a lookup, one year is far past any rate a
+// client would still call fresh.
+const MaxRateAge = 366 * 24 * time.Hour
+
+type StalePolicy string
+
+const (
+	// StalePolicyAllow returns a stale rate marked as stale.
+	StalePolicyAllow StalePolicy = "allow"
+	// StalePolicyError rejects a stale rate with CurrencyRateStale.
+	StalePolicyError StalePolicy = "error"
+)
+
+func (p StalePolicy) IsValid() bool {
+	return p == StalePolicyAllow || p == StalePolicyError
+}
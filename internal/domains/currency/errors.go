@@ -2,6 +2,8 @@ package currency
 
 import (
 	error_utils "currency-rate-app/internal/common/error-utils"
+	"fmt"
+	"time"
 )
 
 func ErrInvalidCurrencyCode() *error_utils.CustomError {
@@ -39,9 +41,154 @@ func ErrCurrencyRateNotCompletedYet() *error_utils.CustomError {
 	}
 }
 
-func ErrCurrencyRateFetchFailed() *error_utils.CustomError {
-	return &error_utils.CustomError{
+// ErrCurrencyRateFetchFailed carries the failure of the rate when it's known,
+// rates failed before failures were stored have none.
+func ErrCurrencyRateFetchFailed(failure *RateFailure) *error_utils.CustomError {
+	err := &error_utils.CustomError{
 		ErrorType: error_utils.ErrorCodeBusinessLogic,
 		Code:      "CurrencyRateFetchFailed",
 	}
+
+	if failure != nil {
+		err.Message = failure.Message
+		err.Details = RateFailureDetails{
+			FailureCode:    failure.Code,
+			FailureMessage: failure.Message,
+			Permanent:      failure.Code.IsPermanent(),
+		}
+	}
+
+	return err
+}
+
+func ErrInvalidAmount() *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeBadRequest,
+		Code:      "InvalidAmount",
+	}
+}
+
+func ErrInvalidRoundingMode() *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeBadRequest,
+		Code:      "InvalidRoundingMode",
+	}
+}
+
+func ErrInvalidRateDate() *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeBadRequest,
+		Code:      "InvalidRateDate",
+	}
+}
+
+func ErrInvalidDateRange() *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeBadRequest,
+		Code:      "InvalidDateRange",
+	}
+}
+
+func ErrCurrencyRateStale(age time.Duration) *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeBusinessLogic,
+		Code:      "CurrencyRateStale",
+		Message:   fmt.Sprintf("newest rate is %d seconds old", int64(age.Seconds())),
+	}
+}
+
+func ErrInvalidStalePolicy() *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeBadRequest,
+		Code:      "InvalidStalePolicy",
+	}
+}
+
+func ErrInvalidMaxAge() *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeBadRequest,
+		Code:      "InvalidMaxAge",
+	}
+}
+
+func ErrInvalidCurrencyPair() *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeBadRequest,
+		Code:      "InvalidCurrencyPair",
+	}
+}
+
+func ErrInvalidLastEventId() *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeBadRequest,
+		Code:      "InvalidLastEventId",
+	}
+}
+
+func ErrRateBatchNotFound() *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeNotFound,
+		Code:      "RateBatchNotFound",
+	}
+}
+
+func ErrInvalidRateMatrixCodes() *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeBadRequest,
+		Code:      "InvalidRateMatrixCodes",
+		Message:   fmt.Sprintf("from 2 to %d distinct codes are expected", MaxRateMatrixCodes),
+	}
+}
+
+func ErrInvalidRateCursor() *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeBadRequest,
+		Code:      "InvalidRateCursor",
+	}
+}
+
+func ErrInvalidRateListQuery(msg string) *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeBadRequest,
+		Code:      "InvalidRateListQuery",
+		Message:   msg,
+	}
+}
+
+func ErrCurrencyRateCancelled() *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeBusinessLogic,
+		Code:      "CurrencyRateCancelled",
+	}
+}
+
+func ErrCurrencyRateNotCancellable(status CurrencyRateStatus) *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeConflict,
+		Code:      "CurrencyRateNotCancellable",
+		Message:   fmt.Sprintf("only PENDING requests can be cancelled, this one is %s", status),
+	}
+}
+
+func ErrCurrencyRateNotFailed(status CurrencyRateStatus) *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeConflict,
+		Code:      "CurrencyRateNotFailed",
+		Message:   fmt.Sprintf("only FAILED requests can be requeued or archived, this one is %s", status),
+	}
+}
+
+func ErrCurrencyRateArchived() *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeConflict,
+		Code:      "CurrencyRateArchived",
+	}
+}
+
+func ErrInvalidFailedRateFilter(msg string) *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeBadRequest,
+		Code:      "InvalidFailedRateFilter",
+		Message:   msg,
+	}
 }
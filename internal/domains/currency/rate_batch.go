@@ -0,0 +1,40 @@
+package currency
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const MaxRateBatchItems = 100
+
+// RateBatchRequest is one item as the client sent it, validated per item so a
+// bad pair doesn't reject the whole batch.
+type RateBatchRequest struct {
+	BaseCurrency   CurrencyCode `json:"baseCurrency"`
+	ResultCurrency CurrencyCode `json:"resultCurrency"`
+	Date           string       `json:"date,omitempty"`
+	CallbackUrl    *string      `json:"callbackUrl,omitempty"`
+}
+
+// RateBatchItem is either a created rate request (RateId) or a rejected one
+// (ErrorCode). Status and Rate mirror the rate request when read back.
+type RateBatchItem struct {
+	Position       int
+	BaseCurrency   CurrencyCode
+	ResultCurrency CurrencyCode
+	RateDate       *time.Time
+	CallbackUrl    *string
+	RateId         *string
+	Status         *CurrencyRateStatus
+	Rate           *decimal.Decimal
+	ErrorCode      *string
+	ErrorMessage   *string
+}
+
+type RateBatch struct {
+	Id             string
+	IdempotencyKey string
+	Items          []RateBatchItem
+	CreatedAt      time.Time
+}
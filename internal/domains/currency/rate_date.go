@@ -0,0 +1,43 @@
+package currency
+
+import "time"
+
+const RateDateLayout = "2006-01-02"
+
+// ParseRateDate parses an optional YYYY-MM-DD business date. An empty string
+// means the latest rate and yields nil.
+func ParseRateDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	date, err := time.Parse(RateDateLayout, value)
+
+	if err != nil {
+		return nil, ErrInvalidRateDate()
+	}
+
+	if date.After(time.Now().UTC()) {
+		return nil, ErrInvalidRateDate()
+	}
+
+	return &date, nil
+}
+
+func FormatRateDate(date *time.Time) *string {
+	if date == nil {
+		return nil
+	}
+
+	res := date.UTC().Format(RateDateLayout)
+
+	return &res
+}
+
+func SameRateDate(a *time.Time, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	return a.UTC().Format(RateDateLayout) == b.UTC().Format(RateDateLayout)
+}
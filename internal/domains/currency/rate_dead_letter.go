@@ -0,0 +1,46 @@
+package currency
+
+import "time"
+
+const (
+	DefaultRequeueLimit = 100
+	MaxRequeueLimit     = 1000
+)
+
+type RateAdminActionType string
+
+const (
+	RateAdminActionRequeue RateAdminActionType = "REQUEUE"
+	RateAdminActionArchive RateAdminActionType = "ARCHIVE"
+)
+
+// RateAdminAction is an entry of the audit log of manual actions on a rate.
+type RateAdminAction struct {
+	Id        string
+	RateId    string
+	Action    RateAdminActionType
+	Actor     string
+	CreatedAt time.Time
+}
+
+// FailedRateFilter selects FAILED rates that aren't archived, zero fields
+// don't filter. The failure time is the last update of the rate.
+type FailedRateFilter struct {
+	BaseCurrency   CurrencyCode
+	ResultCurrency CurrencyCode
+	FailedFrom     *time.Time
+	FailedTo       *time.Time
+}
+
+func (f FailedRateFilter) RateFilter() RateFilter {
+	archived := false
+
+	return RateFilter{
+		Statuses:       []CurrencyRateStatus{CurrencyRateStatusFailed},
+		BaseCurrency:   f.BaseCurrency,
+		ResultCurrency: f.ResultCurrency,
+		UpdatedFrom:    f.FailedFrom,
+		UpdatedTo:      f.FailedTo,
+		Archived:       &archived,
+	}
+}
@@ -0,0 +1,47 @@
+package currency
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// CurrencyPair is written as two concatenated codes, e.g. EURUSD.
+type CurrencyPair struct {
+	BaseCurrency   CurrencyCode
+	ResultCurrency CurrencyCode
+}
+
+func ParseCurrencyPair(value string) (CurrencyPair, error) {
+	if len(value) != 6 {
+		return CurrencyPair{}, ErrInvalidCurrencyPair()
+	}
+
+	pair := CurrencyPair{BaseCurrency: CurrencyCode(value[:3]), ResultCurrency: CurrencyCode(value[3:])}
+
+	if err := ValidateCurrencyPair(pair.BaseCurrency, pair.ResultCurrency); err != nil {
+		return CurrencyPair{}, err
+	}
+
+	return pair, nil
+}
+
+func (p CurrencyPair) String() string {
+	return string(p.BaseCurrency) + string(p.ResultCurrency)
+}
+
+// RateEvent is published every time the worker completes a rate. Ids grow
+// monotonically, so a stream can resume after the last id it has seen.
+type RateEvent struct {
+	Id             int64
+	BaseCurrency   CurrencyCode
+	ResultCurrency CurrencyCode
+	RateDate       *time.Time
+	Rate           decimal.Decimal
+	PivotCurrency  *CurrencyCode
+	CreatedAt      time.Time
+}
+
+func (e RateEvent) Pair() CurrencyPair {
+	return CurrencyPair{BaseCurrency: e.BaseCurrency, ResultCurrency: e.ResultCurrency}
+}
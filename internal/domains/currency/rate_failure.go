@@ -0,0 +1,38 @@
+package currency
+
+type RateFailureCode string
+
+const (
+	// RateFailureProviderUnavailable is a provider call that failed before a
+	// response, e.g. connection refused or DNS.
+	RateFailureProviderUnavailable RateFailureCode = "PROVIDER_UNAVAILABLE"
+	RateFailureProviderTimeout     RateFailureCode = "PROVIDER_TIMEOUT"
+	// RateFailureProviderError is a 5xx or 429 response of the provider.
+	RateFailureProviderError RateFailureCode = "PROVIDER_ERROR"
+	// RateFailureProviderRejected is any other non-200 response: the provider
+	// won't serve this request, e.g. a date it has no rates for.
+	RateFailureProviderRejected    RateFailureCode = "PROVIDER_REJECTED"
+	RateFailureProviderBadResponse RateFailureCode = "PROVIDER_BAD_RESPONSE"
+	RateFailurePairNotSupported    RateFailureCode = "PAIR_NOT_SUPPORTED"
+	RateFailureLeaseExpired        RateFailureCode = "LEASE_EXPIRED"
+)
+
+// IsPermanent tells that sending the same request again won't help, the other
+// failures may go away on their own. A pair missing from one response may be
+// back in the next one, so PAIR_NOT_SUPPORTED is retried.
+func (c RateFailureCode) IsPermanent() bool {
+	return c == RateFailureProviderRejected
+}
+
+// RateFailure is the cause of the last failed attempt of a rate.
+type RateFailure struct {
+	Code    RateFailureCode
+	Message string
+}
+
+// RateFailureDetails is the body of a FAILED rate's error response.
+type RateFailureDetails struct {
+	FailureCode    RateFailureCode `json:"failureCode"`
+	FailureMessage string          `json:"failureMessage"`
+	Permanent      bool            `json:"permanent"`
+}
@@ -0,0 +1,100 @@
+package currency
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+const (
+	DefaultRateListLimit = 50
+	MaxRateListLimit     = 200
+)
+
+func (s CurrencyRateStatus) IsValid() bool {
+	switch s {
+	case CurrencyRateStatusPending, CurrencyRateStatusProcessing, CurrencyRateStatusCompleted, CurrencyRateStatusFailed, CurrencyRateStatusCancelled:
+		return true
+	}
+
+	return false
+}
+
+type RateSortField string
+
+const (
+	RateSortCreatedAt RateSortField = "createdAt"
+	RateSortUpdatedAt RateSortField = "updatedAt"
+)
+
+func (f RateSortField) IsValid() bool {
+	return f == RateSortCreatedAt || f == RateSortUpdatedAt
+}
+
+// RateFilter narrows a listing, zero fields don't filter.
+type RateFilter struct {
+	Statuses       []CurrencyRateStatus
+	BaseCurrency   CurrencyCode
+	ResultCurrency CurrencyCode
+	IdempotencyKey string
+	CreatedFrom    *time.Time
+	CreatedTo      *time.Time
+	CompletedFrom  *time.Time
+	CompletedTo    *time.Time
+	UpdatedFrom    *time.Time
+	UpdatedTo      *time.Time
+	Archived       *bool
+}
+
+// RateCursor is the position after the last row of a page. It remembers the
+// sort it was made for, so it can't be replayed against another order.
+type RateCursor struct {
+	SortField RateSortField `json:"s"`
+	Desc      bool          `json:"d"`
+	Value     time.Time     `json:"v"`
+	Id        string        `json:"i"`
+}
+
+func (c RateCursor) Encode() string {
+	data, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func ParseRateCursor(value string) (*RateCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+
+	if err != nil {
+		return nil, ErrInvalidRateCursor()
+	}
+
+	var cursor RateCursor
+
+	if err := json.Unmarshal(data, &cursor); err != nil || !cursor.SortField.IsValid() || cursor.Id == "" {
+		return nil, ErrInvalidRateCursor()
+	}
+
+	return &cursor, nil
+}
+
+type RateListQuery struct {
+	Filter    RateFilter
+	SortField RateSortField
+	Desc      bool
+	Cursor    *RateCursor
+	Limit     int
+}
+
+type RatePage struct {
+	Items []CurrencyRate
+	// Next is nil on the last page.
+	Next *RateCursor
+}
+
+func (r CurrencyRate) SortValue(field RateSortField) time.Time {
+	if field == RateSortUpdatedAt {
+		return r.UpdatedAt
+	}
+
+	return r.CreatedAt
+}
@@ -0,0 +1,40 @@
+package currency
+
+const MaxRateMatrixCodes = 20
+
+// RateMatrixCell is one ordered pair of the matrix, Rate is nil when the pair
+// has no completed rate.
+type RateMatrixCell struct {
+	BaseCurrency   CurrencyCode
+	ResultCurrency CurrencyCode
+	Rate           *CurrencyRate
+}
+
+type RateMatrix struct {
+	Codes []CurrencyCode
+	// Cells lists every ordered pair of distinct codes, row by row in the
+	// order of Codes.
+	Cells []RateMatrixCell
+}
+
+func ValidateRateMatrixCodes(codes []CurrencyCode) error {
+	if len(codes) < 2 || len(codes) > MaxRateMatrixCodes {
+		return ErrInvalidRateMatrixCodes()
+	}
+
+	seen := make(map[CurrencyCode]struct{}, len(codes))
+
+	for _, code := range codes {
+		if !code.IsValid() {
+			return ErrInvalidCurrencyCode()
+		}
+
+		if _, ok := seen[code]; ok {
+			return ErrInvalidRateMatrixCodes()
+		}
+
+		seen[code] = struct{}{}
+	}
+
+	return nil
+}
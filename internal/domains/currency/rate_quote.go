@@ -0,0 +1,28 @@
+package currency
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// DerivedRatePrecision is the number of decimal places kept for rates that
+// are computed rather than quoted by the provider.
+const DerivedRatePrecision int32 = 18
+
+// RateQuote is the resolved rate for a pair, together with how it was obtained.
+type RateQuote struct {
+	Rate          decimal.Decimal
+	PivotCurrency *CurrencyCode
+	// Provider served the rate, both legs of a cross rate when they differ
+	// (frankfurter+mock)
+	Provider string
+}
+
+// CrossRate derives base->quote from the base->pivot and pivot->quote legs.
+func CrossRate(basePivot decimal.Decimal, pivotQuote decimal.Decimal) decimal.Decimal {
+	return basePivot.Mul(pivotQuote).Round(DerivedRatePrecision)
+}
+
+// InverseRate returns 1/rate. The caller must make sure the rate is not zero.
+func InverseRate(rate decimal.Decimal) decimal.Decimal {
+	return decimal.NewFromInt(1).DivRound(rate, DerivedRatePrecision)
+}
@@ -0,0 +1,62 @@
+package currency
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// MaxTimeSeriesDays caps a single time series request, roughly one year.
+const MaxTimeSeriesDays = 366
+
+type TimeSeriesPoint struct {
+	Date         time.Time
+	Rate         decimal.Decimal
+	FromProvider bool
+	// Provider is set on points fetched from a provider
+	Provider string
+}
+
+type TimeSeries struct {
+	BaseCurrency   CurrencyCode
+	ResultCurrency CurrencyCode
+	From           time.Time
+	To             time.Time
+	Points         []TimeSeriesPoint
+}
+
+func ValidateDateRange(from time.Time, to time.Time) error {
+	if to.Before(from) {
+		return ErrInvalidDateRange()
+	}
+
+	if to.Sub(from) > MaxTimeSeriesDays*24*time.Hour {
+		return ErrInvalidDateRange()
+	}
+
+	return nil
+}
+
+// MissingBusinessDays lists weekdays in [from, to] that have no point yet.
+// Weekends are skipped because providers don't publish rates for them.
+func MissingBusinessDays(from time.Time, to time.Time, points []TimeSeriesPoint) []time.Time {
+	known := make(map[string]bool, len(points))
+
+	for _, p := range points {
+		known[p.Date.Format(RateDateLayout)] = true
+	}
+
+	var missing []time.Time
+
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+
+		if !known[day.Format(RateDateLayout)] {
+			missing = append(missing, day)
+		}
+	}
+
+	return missing
+}
@@ -0,0 +1,26 @@
+package pricing
+
+import (
+	error_utils "currency-rate-app/internal/common/error-utils"
+)
+
+func ErrInvalidSpreadScope() *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeBadRequest,
+		Code:      "InvalidSpreadScope",
+	}
+}
+
+func ErrInvalidSpread() *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeBadRequest,
+		Code:      "InvalidSpread",
+	}
+}
+
+func ErrSpreadNotFound() *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeNotFound,
+		Code:      "SpreadNotFound",
+	}
+}
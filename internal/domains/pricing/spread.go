@@ -0,0 +1,96 @@
+package pricing
+
+import (
+	"time"
+
+	"currency-rate-app/internal/domains/currency"
+
+	"github.com/shopspring/decimal"
+)
+
+// MaxBasisPoints is 100%, a bid spread can't take the price below zero.
+const MaxBasisPoints = 10000
+
+// DefaultScope marks the spread applied when no pair or currency rule matches.
+const DefaultScope = "DEFAULT"
+
+// Spread is a margin over the mid-market rate. Scope is either a pair of codes
+// ("EURUSD") or a single currency ("MXN").
+type Spread struct {
+	Scope     string
+	BidBps    int
+	AskBps    int
+	UpdatedBy string
+	UpdatedAt time.Time
+}
+
+type SpreadAction string
+
+const (
+	SpreadActionSet    SpreadAction = "SET"
+	SpreadActionDelete SpreadAction = "DELETE"
+)
+
+type SpreadChange struct {
+	Id        string
+	Scope     string
+	Action    SpreadAction
+	BidBps    *int
+	AskBps    *int
+	Actor     string
+	CreatedAt time.Time
+}
+
+type PricedRate struct {
+	Mid    decimal.Decimal
+	Bid    decimal.Decimal
+	Ask    decimal.Decimal
+	Spread Spread
+}
+
+func ValidateScope(scope string) error {
+	switch len(scope) {
+	case 3:
+		if currency.CurrencyCode(scope).IsValid() {
+			return nil
+		}
+	case 6:
+		if currency.ValidateCurrencyPair(currency.CurrencyCode(scope[:3]), currency.CurrencyCode(scope[3:])) == nil {
+			return nil
+		}
+	}
+
+	return ErrInvalidSpreadScope()
+}
+
+func ValidateSpread(bidBps int, askBps int) error {
+	if bidBps < 0 || bidBps >= MaxBasisPoints || askBps < 0 || askBps > MaxBasisPoints {
+		return ErrInvalidSpread()
+	}
+
+	return nil
+}
+
+// ScopesFor lists the scopes that can price a pair, most specific first: the
+// pair itself, then the result currency, then the base currency.
+func ScopesFor(baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode) []string {
+	return []string{
+		string(baseCurrency) + string(resultCurrency),
+		string(resultCurrency),
+		string(baseCurrency),
+	}
+}
+
+// Apply prices the mid rate, bid = mid*(1-bid/10000), ask = mid*(1+ask/10000).
+// Basis points are exact in decimal, so no rounding is involved.
+func Apply(mid decimal.Decimal, spread Spread) PricedRate {
+	bidFactor := decimal.New(int64(MaxBasisPoints-spread.BidBps), -4)
+	askFactor := decimal.New(int64(MaxBasisPoints+spread.AskBps), -4)
+
+	return PricedRate{
+		Mid:    mid,
+		Bid:    mid.Mul(bidFactor),
+		Ask:    mid.Mul(askFactor),
+		Spread: spread,
+	}
+}
@@ -0,0 +1,26 @@
+package subscription
+
+import (
+	error_utils "currency-rate-app/internal/common/error-utils"
+)
+
+func ErrSubscriptionNotFound() *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeNotFound,
+		Code:      "SubscriptionNotFound",
+	}
+}
+
+func ErrSubscriptionAlreadyExists() *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeBusinessLogic,
+		Code:      "SubscriptionAlreadyExists",
+	}
+}
+
+func ErrInvalidSubscriptionInterval() *error_utils.CustomError {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeBadRequest,
+		Code:      "InvalidSubscriptionInterval",
+	}
+}
@@ -0,0 +1,33 @@
+package subscription
+
+import (
+	"time"
+
+	"currency-rate-app/internal/domains/currency"
+)
+
+const (
+	MinInterval = time.Minute
+	MaxInterval = 7 * 24 * time.Hour
+)
+
+// Subscription keeps the latest rate of a pair warm: every Interval the
+// scheduler enqueues a PENDING rate request for it.
+type Subscription struct {
+	Id             string
+	BaseCurrency   currency.CurrencyCode
+	ResultCurrency currency.CurrencyCode
+	Interval       time.Duration
+	NextRunAt      time.Time
+	LastEnqueuedAt *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func ValidateInterval(interval time.Duration) error {
+	if interval < MinInterval || interval > MaxInterval || interval%time.Second != 0 {
+		return ErrInvalidSubscriptionInterval()
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"currency-rate-app/internal/domains/alert"
+	"currency-rate-app/internal/domains/currency"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type AlertRuleEntity struct {
+	Id             string          `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	BaseCurrency   string          `gorm:"not null;index:idx_alert_rules_pair"`
+	ResultCurrency string          `gorm:"not null;index:idx_alert_rules_pair"`
+	Condition      string          `gorm:"not null"`
+	Threshold      decimal.Decimal `gorm:"type:numeric;not null"`
+	WindowSeconds  int             `gorm:"not null;default:0"`
+	TargetUrl      string          `gorm:"not null"`
+	Triggered      bool            `gorm:"not null;default:false"`
+	LastFiredAt    *time.Time
+	CreatedAt      time.Time `gorm:"not null"`
+	UpdatedAt      time.Time `gorm:"not null"`
+}
+
+func (AlertRuleEntity) TableName() string {
+	return "alert_rules"
+}
+
+type AlertFiringEntity struct {
+	Id             string           `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	RuleId         string           `gorm:"type:uuid;not null;index"`
+	Rate           decimal.Decimal  `gorm:"type:numeric;not null"`
+	ReferenceRate  *decimal.Decimal `gorm:"type:numeric"`
+	DeliveryStatus string           `gorm:"not null;index:idx_alert_firings_due,priority:1"`
+	Attempts       int              `gorm:"not null;default:0"`
+	LastError      *string
+	NextAttemptAt  *time.Time `gorm:"index:idx_alert_firings_due,priority:2"`
+	FiredAt        time.Time  `gorm:"not null"`
+}
+
+func (AlertFiringEntity) TableName() string {
+	return "alert_firings"
+}
+
+func alertRuleToDomain(e *AlertRuleEntity) *alert.AlertRule {
+	return &alert.AlertRule{
+		Id:             e.Id,
+		BaseCurrency:   currency.CurrencyCode(e.BaseCurrency),
+		ResultCurrency: currency.CurrencyCode(e.ResultCurrency),
+		Condition:      alert.Condition(e.Condition),
+		Threshold:      e.Threshold,
+		Window:         time.Duration(e.WindowSeconds) * time.Second,
+		TargetUrl:      e.TargetUrl,
+		Triggered:      e.Triggered,
+		LastFiredAt:    e.LastFiredAt,
+		CreatedAt:      e.CreatedAt,
+		UpdatedAt:      e.UpdatedAt,
+	}
+}
+
+func alertFiringToDomain(e *AlertFiringEntity) *alert.AlertFiring {
+	return &alert.AlertFiring{
+		Id:             e.Id,
+		RuleId:         e.RuleId,
+		Rate:           e.Rate,
+		ReferenceRate:  e.ReferenceRate,
+		DeliveryStatus: alert.DeliveryStatus(e.DeliveryStatus),
+		Attempts:       e.Attempts,
+		LastError:      e.LastError,
+		FiredAt:        e.FiredAt,
+	}
+}
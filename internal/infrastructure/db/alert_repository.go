@@ -0,0 +1,299 @@
+package db
+
+import (
+	"context"
+	error_utils "currency-rate-app/internal/common/error-utils"
+	"currency-rate-app/internal/domains/alert"
+	"currency-rate-app/internal/domains/currency"
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AlertRepository interface {
+	CreateAlertRule(ctx context.Context, rule alert.AlertRule) (*alert.AlertRule, error)
+	ListAlertRules(ctx context.Context) ([]alert.AlertRule, error)
+	GetAlertRuleById(ctx context.Context, id string) (*alert.AlertRule, error)
+	DeleteAlertRule(ctx context.Context, id string) error
+	GetAlertRulesByPair(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode) ([]alert.AlertRule, error)
+	GetReferenceRate(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, since time.Time) (*decimal.Decimal, error)
+	FireAlertRule(ctx context.Context, ruleId string, rate decimal.Decimal, referenceRate *decimal.Decimal) (*alert.AlertFiring, error)
+	ResetAlertRule(ctx context.Context, ruleId string) error
+	FetchDueFirings(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]alert.AlertFiring, error)
+	UpdateFiringDelivery(ctx context.Context, firingId string, status alert.DeliveryStatus, attempts int, lastError *string, nextAttemptAt time.Time) error
+	GetAlertFirings(ctx context.Context, ruleId string) ([]alert.AlertFiring, error)
+}
+
+type alertRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewAlertRepository(db *gorm.DB) *alertRepositoryImpl {
+	return &alertRepositoryImpl{db: db}
+}
+
+func (repo *alertRepositoryImpl) CreateAlertRule(ctx context.Context, rule alert.AlertRule) (*alert.AlertRule, error) {
+	now := time.Now()
+	entity := &AlertRuleEntity{
+		BaseCurrency:   string(rule.BaseCurrency),
+		ResultCurrency: string(rule.ResultCurrency),
+		Condition:      string(rule.Condition),
+		Threshold:      rule.Threshold,
+		WindowSeconds:  int(rule.Window / time.Second),
+		TargetUrl:      rule.TargetUrl,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	if err := repo.db.WithContext(ctx).Create(entity).Error; err != nil {
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	return alertRuleToDomain(entity), nil
+}
+
+func (repo *alertRepositoryImpl) ListAlertRules(ctx context.Context) ([]alert.AlertRule, error) {
+	var entities []AlertRuleEntity
+
+	if err := repo.db.WithContext(ctx).Order("created_at ASC").Find(&entities).Error; err != nil {
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	return alertRulesToDomain(entities), nil
+}
+
+func (repo *alertRepositoryImpl) GetAlertRuleById(ctx context.Context, id string) (*alert.AlertRule, error) {
+	var entity AlertRuleEntity
+
+	err := repo.db.WithContext(ctx).Where(&AlertRuleEntity{Id: id}).First(&entity).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, alert.ErrAlertRuleNotFound()
+		}
+
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	return alertRuleToDomain(&entity), nil
+}
+
+// DeleteAlertRule removes the rule together with its firings.
+func (repo *alertRepositoryImpl) DeleteAlertRule(ctx context.Context, id string) error {
+	return repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where(&AlertRuleEntity{Id: id}).Delete(&AlertRuleEntity{})
+
+		if res.Error != nil {
+			return error_utils.ErrInternalServerError(res.Error.Error())
+		}
+
+		if res.RowsAffected == 0 {
+			return alert.ErrAlertRuleNotFound()
+		}
+
+		if err := tx.Where(&AlertFiringEntity{RuleId: id}).Delete(&AlertFiringEntity{}).Error; err != nil {
+			return error_utils.ErrInternalServerError(err.Error())
+		}
+
+		return nil
+	})
+}
+
+func (repo *alertRepositoryImpl) GetAlertRulesByPair(
+	ctx context.Context,
+	baseCurrency currency.CurrencyCode,
+	resultCurrency currency.CurrencyCode,
+) ([]alert.AlertRule, error) {
+	var entities []AlertRuleEntity
+
+	err := repo.db.WithContext(ctx).
+		Where(&AlertRuleEntity{BaseCurrency: string(baseCurrency), ResultCurrency: string(resultCurrency)}).
+		Find(&entities).Error
+
+	if err != nil {
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	return alertRulesToDomain(entities), nil
+}
+
+// GetReferenceRate returns the oldest latest-rate of the pair completed at or
+// after since, nil when there is none.
+func (repo *alertRepositoryImpl) GetReferenceRate(
+	ctx context.Context,
+	baseCurrency currency.CurrencyCode,
+	resultCurrency currency.CurrencyCode,
+	since time.Time,
+) (*decimal.Decimal, error) {
+	var entities []CurrencyRateEntity
+
+	err := whereRateDate(repo.db.WithContext(ctx), nil).
+		Where(&CurrencyRateEntity{
+			BaseCurrency:   string(baseCurrency),
+			ResultCurrency: string(resultCurrency),
+			Status:         string(currency.CurrencyRateStatusCompleted),
+		}).
+		Where("completed_at >= ?", since).
+		Order("completed_at ASC").
+		Limit(1).
+		Find(&entities).Error
+
+	if err != nil {
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	if len(entities) == 0 {
+		return nil, nil
+	}
+
+	return entities[0].Rate, nil
+}
+
+// FireAlertRule flips the rule to triggered and records the firing. The flip
+// is conditional, so when several instances evaluate the same rule only one of
+// them gets a firing back, the others get nil. The firing is PENDING delivery,
+// it is sent by the delivery job, see FetchDueFirings.
+func (repo *alertRepositoryImpl) FireAlertRule(
+	ctx context.Context,
+	ruleId string,
+	rate decimal.Decimal,
+	referenceRate *decimal.Decimal,
+) (*alert.AlertFiring, error) {
+	now := time.Now()
+
+	var firing *AlertFiringEntity
+
+	err := repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&AlertRuleEntity{}).
+			Where("id = ? AND triggered = ?", ruleId, false).
+			UpdateColumns(map[string]any{"triggered": true, "last_fired_at": now, "updated_at": now})
+
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+
+		firing = &AlertFiringEntity{
+			RuleId:         ruleId,
+			Rate:           rate,
+			ReferenceRate:  referenceRate,
+			DeliveryStatus: string(alert.DeliveryStatusPending),
+			NextAttemptAt:  &now,
+			FiredAt:        now,
+		}
+
+		return tx.Create(firing).Error
+	})
+
+	if err != nil {
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	if firing == nil {
+		return nil, nil
+	}
+
+	return alertFiringToDomain(firing), nil
+}
+
+func (repo *alertRepositoryImpl) ResetAlertRule(ctx context.Context, ruleId string) error {
+	err := repo.db.WithContext(ctx).Model(&AlertRuleEntity{}).
+		Where("id = ? AND triggered = ?", ruleId, true).
+		UpdateColumns(map[string]any{"triggered": false, "updated_at": time.Now()}).Error
+
+	if err != nil {
+		return error_utils.ErrInternalServerError(err.Error())
+	}
+
+	return nil
+}
+
+// FetchDueFirings leases firings due for delivery like FetchDueDeliveries
+// does with callbacks.
+func (repo *alertRepositoryImpl) FetchDueFirings(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]alert.AlertFiring, error) {
+	var entities []AlertFiringEntity
+
+	err := repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		subquery := tx.
+			Model(&AlertFiringEntity{}).
+			Where(&AlertFiringEntity{DeliveryStatus: string(alert.DeliveryStatusPending)}).
+			Where("next_attempt_at IS NULL OR next_attempt_at <= ?", now).
+			Order("fired_at ASC").
+			Limit(limit).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Select("id")
+
+		return tx.Model(&entities).
+			Clauses(clause.Returning{}).
+			Where("id IN (?)", subquery).
+			UpdateColumns(map[string]any{"next_attempt_at": now.Add(lease)}).Error
+	})
+
+	if err != nil {
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	models := make([]alert.AlertFiring, 0, len(entities))
+	for _, e := range entities {
+		models = append(models, *alertFiringToDomain(&e))
+	}
+	return models, nil
+}
+
+func (repo *alertRepositoryImpl) UpdateFiringDelivery(
+	ctx context.Context,
+	firingId string,
+	status alert.DeliveryStatus,
+	attempts int,
+	lastError *string,
+	nextAttemptAt time.Time,
+) error {
+	err := repo.db.WithContext(ctx).Model(&AlertFiringEntity{}).
+		Where("id = ?", firingId).
+		UpdateColumns(map[string]any{
+			"delivery_status": string(status),
+			"attempts":        attempts,
+			"last_error":      lastError,
+			"next_attempt_at": nextAttemptAt,
+		}).Error
+
+	if err != nil {
+		return error_utils.ErrInternalServerError(err.Error())
+	}
+
+	return nil
+}
+
+func (repo *alertRepositoryImpl) GetAlertFirings(ctx context.Context, ruleId string) ([]alert.AlertFiring, error) {
+	var entities []AlertFiringEntity
+
+	err := repo.db.WithContext(ctx).
+		Where(&AlertFiringEntity{RuleId: ruleId}).
+		Order("fired_at DESC").
+		Find(&entities).Error
+
+	if err != nil {
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	models := make([]alert.AlertFiring, 0, len(entities))
+	for _, e := range entities {
+		models = append(models, *alertFiringToDomain(&e))
+	}
+	return models, nil
+}
+
+func alertRulesToDomain(entities []AlertRuleEntity) []alert.AlertRule {
+	models := make([]alert.AlertRule, 0, len(entities))
+	for _, e := range entities {
+		models = append(models, *alertRuleToDomain(&e))
+	}
+	return models
+}
@@ -0,0 +1,61 @@
+package db
+
+import (
+	"currency-rate-app/internal/domains/callback"
+	"time"
+)
+
+type CallbackDeliveryEntity struct {
+	Id            string    `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	RateId        string    `gorm:"type:uuid;not null;index"`
+	Url           string    `gorm:"not null"`
+	Event         string    `gorm:"not null"`
+	Status        string    `gorm:"not null;index:idx_callback_deliveries_due,priority:1"`
+	Attempts      int       `gorm:"not null;default:0"`
+	NextAttemptAt time.Time `gorm:"not null;index:idx_callback_deliveries_due,priority:2"`
+	DeliveredAt   *time.Time
+	CreatedAt     time.Time `gorm:"not null"`
+	UpdatedAt     time.Time `gorm:"not null"`
+}
+
+func (CallbackDeliveryEntity) TableName() string {
+	return "callback_deliveries"
+}
+
+type CallbackAttemptEntity struct {
+	Id         string `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	DeliveryId string `gorm:"type:uuid;not null;index"`
+	Number     int    `gorm:"not null"`
+	StatusCode *int
+	Error      *string
+	DurationMs int64     `gorm:"not null"`
+	CreatedAt  time.Time `gorm:"not null"`
+}
+
+func (CallbackAttemptEntity) TableName() string {
+	return "callback_delivery_attempts"
+}
+
+func callbackDeliveryToDomain(e *CallbackDeliveryEntity) *callback.Delivery {
+	return &callback.Delivery{
+		Id:            e.Id,
+		RateId:        e.RateId,
+		Url:           e.Url,
+		Event:         callback.Event(e.Event),
+		Status:        callback.DeliveryStatus(e.Status),
+		Attempts:      e.Attempts,
+		NextAttemptAt: e.NextAttemptAt,
+		DeliveredAt:   e.DeliveredAt,
+		CreatedAt:     e.CreatedAt,
+	}
+}
+
+func callbackAttemptToDomain(e *CallbackAttemptEntity) *callback.Attempt {
+	return &callback.Attempt{
+		Number:     e.Number,
+		StatusCode: e.StatusCode,
+		Error:      e.Error,
+		Duration:   time.Duration(e.DurationMs) * time.Millisecond,
+		CreatedAt:  e.CreatedAt,
+	}
+}
@@ -0,0 +1,185 @@
+package db
+
+import (
+	"context"
+	error_utils "currency-rate-app/internal/common/error-utils"
+	"currency-rate-app/internal/domains/callback"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CallbackRepository interface {
+	FetchDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]callback.Delivery, error)
+	RecordAttempt(ctx context.Context, deliveryId string, attempt callback.Attempt, status callback.DeliveryStatus, nextAttemptAt time.Time) error
+	GetDeliveriesByRateId(ctx context.Context, rateId string) ([]callback.Delivery, error)
+}
+
+type callbackRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewCallbackRepository(db *gorm.DB) *callbackRepositoryImpl {
+	return &callbackRepositoryImpl{db: db}
+}
+
+// FetchDueDeliveries leases due deliveries by pushing their next attempt past
+// the lease, so other instances skip them while this one is sending. A
+// delivery whose sender died is picked up again once the lease runs out.
+func (repo *callbackRepositoryImpl) FetchDueDeliveries(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]callback.Delivery, error) {
+	var entities []CallbackDeliveryEntity
+
+	err := repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		subquery := tx.
+			Model(&CallbackDeliveryEntity{}).
+			Where(&CallbackDeliveryEntity{Status: string(callback.DeliveryStatusPending)}).
+			Where("next_attempt_at <= ?", now).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Select("id")
+
+		return tx.Model(&entities).
+			Clauses(clause.Returning{}).
+			Where("id IN (?)", subquery).
+			UpdateColumns(&CallbackDeliveryEntity{
+				NextAttemptAt: now.Add(lease),
+				UpdatedAt:     now,
+			}).Error
+	})
+
+	if err != nil {
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	models := make([]callback.Delivery, 0, len(entities))
+	for _, e := range entities {
+		models = append(models, *callbackDeliveryToDomain(&e))
+	}
+	return models, nil
+}
+
+// RecordAttempt appends the attempt to the delivery log and moves the delivery
+// to its new state.
+func (repo *callbackRepositoryImpl) RecordAttempt(
+	ctx context.Context,
+	deliveryId string,
+	attempt callback.Attempt,
+	status callback.DeliveryStatus,
+	nextAttemptAt time.Time,
+) error {
+	err := repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&CallbackAttemptEntity{
+			DeliveryId: deliveryId,
+			Number:     attempt.Number,
+			StatusCode: attempt.StatusCode,
+			Error:      attempt.Error,
+			DurationMs: attempt.Duration.Milliseconds(),
+			CreatedAt:  attempt.CreatedAt,
+		}).Error
+
+		if err != nil {
+			return err
+		}
+
+		columns := map[string]any{
+			"status":          string(status),
+			"attempts":        attempt.Number,
+			"next_attempt_at": nextAttemptAt,
+			"updated_at":      attempt.CreatedAt,
+		}
+
+		if status == callback.DeliveryStatusDelivered {
+			columns["delivered_at"] = attempt.CreatedAt
+		}
+
+		return tx.Model(&CallbackDeliveryEntity{}).Where("id = ?", deliveryId).UpdateColumns(columns).Error
+	})
+
+	if err != nil {
+		return error_utils.ErrInternalServerError(err.Error())
+	}
+
+	return nil
+}
+
+func (repo *callbackRepositoryImpl) GetDeliveriesByRateId(ctx context.Context, rateId string) ([]callback.Delivery, error) {
+	var entities []CallbackDeliveryEntity
+
+	err := repo.db.WithContext(ctx).
+		Where(&CallbackDeliveryEntity{RateId: rateId}).
+		Order("created_at ASC").
+		Find(&entities).Error
+
+	if err != nil {
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	if len(entities) == 0 {
+		return []callback.Delivery{}, nil
+	}
+
+	ids := make([]string, 0, len(entities))
+	for _, e := range entities {
+		ids = append(ids, e.Id)
+	}
+
+	var attempts []CallbackAttemptEntity
+
+	err = repo.db.WithContext(ctx).
+		Where("delivery_id IN ?", ids).
+		Order("number ASC").
+		Find(&attempts).Error
+
+	if err != nil {
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	logs := make(map[string][]callback.Attempt, len(entities))
+	for _, a := range attempts {
+		logs[a.DeliveryId] = append(logs[a.DeliveryId], *callbackAttemptToDomain(&a))
+	}
+
+	models := make([]callback.Delivery, 0, len(entities))
+	for _, e := range entities {
+		delivery := callbackDeliveryToDomain(&e)
+		delivery.Log = logs[e.Id]
+		models = append(models, *delivery)
+	}
+	return models, nil
+}
+
+// enqueueCallbacks schedules a delivery for every rate that asked for one. It
+// runs in the transaction that moves the rates to a final status, so a
+// callback is never lost nor sent for a change that was rolled back.
+func enqueueCallbacks(tx *gorm.DB, rates []CurrencyRateEntity, event callback.Event, now time.Time) error {
+	deliveries := make([]CallbackDeliveryEntity, 0, len(rates))
+
+	for _, r := range rates {
+		if r.CallbackUrl == nil {
+			continue
+		}
+
+		deliveries = append(deliveries, CallbackDeliveryEntity{
+			RateId:        r.Id,
+			Url:           *r.CallbackUrl,
+			Event:         string(event),
+			Status:        string(callback.DeliveryStatusPending),
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return tx.Create(&deliveries).Error
+}
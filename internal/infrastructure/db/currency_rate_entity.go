@@ -3,34 +3,106 @@ package db
 import (
 	"currency-rate-app/internal/domains/currency"
 	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm/clause"
 )
 
+// Indexes back the worker queue (status, created_at), lookups of the latest
+// rate of a pair and the keyset pagination of GET /v1/currencies. Idempotency
+// keys are unique per API client, ClientId is empty for anonymous callers.
+// WorkerId and ProcessingStartedAt are the lease of the last claim. FAILED
+// rates keep the last failure and leave the dead-letter list once archived.
 type CurrencyRateEntity struct {
-	Id             string `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	IdempotencyKey string `gorm:"uniqueIndex;not null"`
-	BaseCurrency   string `gorm:"not null"`
-	ResultCurrency string `gorm:"not null"`
-	Status         string `gorm:"not null"`
-	Rate           *float64
-	CompletedAt    *time.Time
-	CreatedAt      time.Time `gorm:"not null"`
-	UpdatedAt      time.Time `gorm:"not null"`
+	Id                  string           `gorm:"type:uuid;default:gen_random_uuid();primaryKey;index:idx_currencies_rates_created,priority:2;index:idx_currencies_rates_updated,priority:2;index:idx_currencies_rates_status_created,priority:3"`
+	ClientId            string           `gorm:"not null;default:'';uniqueIndex:idx_currencies_rates_client_key,priority:1"`
+	IdempotencyKey      string           `gorm:"not null;uniqueIndex:idx_currencies_rates_client_key,priority:2"`
+	BaseCurrency        string           `gorm:"not null;index:idx_currencies_rates_pair,priority:1"`
+	ResultCurrency      string           `gorm:"not null;index:idx_currencies_rates_pair,priority:2"`
+	RateDate            *time.Time       `gorm:"type:date"`
+	Status              string           `gorm:"not null;index:idx_currencies_rates_status_created,priority:1"`
+	Rate                *decimal.Decimal `gorm:"type:numeric"`
+	PivotCurrency       *string
+	Provider            *string
+	CallbackUrl         *string
+	Attempts            int `gorm:"not null;default:0"`
+	NextAttemptAt       *time.Time
+	WorkerId            *string
+	ProcessingStartedAt *time.Time
+	FailureCode         *string
+	FailureMessage      *string
+	ArchivedAt          *time.Time
+	CompletedAt         *time.Time `gorm:"index:idx_currencies_rates_pair,priority:3;index:idx_currencies_rates_completed"`
+	CreatedAt           time.Time  `gorm:"not null;index:idx_currencies_rates_created,priority:1;index:idx_currencies_rates_status_created,priority:2"`
+	UpdatedAt           time.Time  `gorm:"not null;index:idx_currencies_rates_updated,priority:1"`
 }
 
 func (CurrencyRateEntity) TableName() string {
 	return "currencies_rates"
 }
 
+// clientKeyColumns is the conflict target of inserts keyed by an idempotency
+// key, rates and batches share it.
+var clientKeyColumns = []clause.Column{{Name: "client_id"}, {Name: "idempotency_key"}}
+
 func toDomain(e *CurrencyRateEntity) *currency.CurrencyRate {
 	return &currency.CurrencyRate{
-		Id:             e.Id,
-		IdempotencyKey: e.IdempotencyKey,
-		BaseCurrency:   currency.CurrencyCode(e.BaseCurrency),
-		ResultCurrency: currency.CurrencyCode(e.ResultCurrency),
-		Status:         currency.CurrencyRateStatus(e.Status),
-		Rate:           e.Rate,
-		CompletedAt:    e.CompletedAt,
-		CreatedAt:      e.CreatedAt,
-		UpdatedAt:      e.UpdatedAt,
+		Id:                  e.Id,
+		IdempotencyKey:      e.IdempotencyKey,
+		BaseCurrency:        currency.CurrencyCode(e.BaseCurrency),
+		ResultCurrency:      currency.CurrencyCode(e.ResultCurrency),
+		RateDate:            e.RateDate,
+		Status:              currency.CurrencyRateStatus(e.Status),
+		Rate:                e.Rate,
+		PivotCurrency:       (*currency.CurrencyCode)(e.PivotCurrency),
+		Provider:            e.Provider,
+		CallbackUrl:         e.CallbackUrl,
+		Attempts:            e.Attempts,
+		NextAttemptAt:       e.NextAttemptAt,
+		WorkerId:            e.WorkerId,
+		ProcessingStartedAt: e.ProcessingStartedAt,
+		Failure:             rateFailureToDomain(e),
+		ArchivedAt:          e.ArchivedAt,
+		CompletedAt:         e.CompletedAt,
+		CreatedAt:           e.CreatedAt,
+		UpdatedAt:           e.UpdatedAt,
+	}
+}
+
+func rateFailureToDomain(e *CurrencyRateEntity) *currency.RateFailure {
+	if e.FailureCode == nil {
+		return nil
 	}
+
+	failure := &currency.RateFailure{Code: currency.RateFailureCode(*e.FailureCode)}
+
+	if e.FailureMessage != nil {
+		failure.Message = *e.FailureMessage
+	}
+
+	return failure
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
+
+// RateHistoryEntity is a daily rate fetched from a provider to fill a time
+// series. It is kept apart from currencies_rates, so backfilled history never
+// shows up as a rate request.
+type RateHistoryEntity struct {
+	BaseCurrency   string          `gorm:"primaryKey"`
+	ResultCurrency string          `gorm:"primaryKey"`
+	RateDate       time.Time       `gorm:"type:date;primaryKey"`
+	Rate           decimal.Decimal `gorm:"type:numeric;not null"`
+	Provider       *string
+	CreatedAt      time.Time `gorm:"not null"`
+}
+
+func (RateHistoryEntity) TableName() string {
+	return "currencies_rates_history"
 }
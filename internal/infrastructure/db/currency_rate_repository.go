@@ -3,8 +3,10 @@ package db
 import (
 	"context"
 	error_utils "currency-rate-app/internal/common/error-utils"
+	"currency-rate-app/internal/domains/callback"
 	"currency-rate-app/internal/domains/currency"
 	"errors"
+	"sort"
 	"time"
 
 	"gorm.io/gorm"
@@ -12,12 +14,25 @@ import (
 )
 
 type CurrencyRepository interface {
-	GetActualRateByCurrency(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode) (*currency.CurrencyRate, error)
+	GetActualRateByCurrency(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, rateDate *time.Time) (*currency.CurrencyRate, error)
 	GetRateById(ctx context.Context, id string) (*currency.CurrencyRate, error)
-	CreateRate(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, idempotencyKey string) (*currency.CurrencyRate, error)
-	UpdateRateStatusByIds(ctx context.Context, ids []string, status currency.CurrencyRateStatus) error
-	SaveRatesByIds(ctx context.Context, ids []string, rate float64) error
-	FetchAndMarkForProcessing(ctx context.Context, limit int) ([]currency.CurrencyRate, error)
+	ListRates(ctx context.Context, query currency.RateListQuery) ([]currency.CurrencyRate, error)
+	GetActualRatesByCurrencies(ctx context.Context, codes []currency.CurrencyCode, rateDate *time.Time) ([]currency.CurrencyRate, error)
+	CreateRate(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, rateDate *time.Time, clientId string, idempotencyKey string, callbackUrl *string) (*currency.CurrencyRate, error)
+	FailRatesByIds(ctx context.Context, lease currency.RateLease, ids []string, failure currency.RateFailure) (int64, error)
+	CancelRate(ctx context.Context, id string) (*currency.CurrencyRate, error)
+	SaveRatesByIds(ctx context.Context, lease currency.RateLease, ids []string, quote currency.RateQuote) (int64, error)
+	RetryRatesByIds(ctx context.Context, lease currency.RateLease, ids []string, nextAttemptAt time.Time, failure currency.RateFailure) (int64, error)
+	FetchAndMarkForProcessing(ctx context.Context, workerId string, limit int) ([]currency.CurrencyRate, error)
+	ReclaimExpiredRates(ctx context.Context, startedBefore time.Time, maxAttempts int, limit int) ([]currency.CurrencyRate, error)
+	GetRateHistory(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, from time.Time, to time.Time) ([]currency.TimeSeriesPoint, error)
+	SaveRateHistory(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, points []currency.TimeSeriesPoint) error
+	CreateRateBatch(ctx context.Context, clientId string, idempotencyKey string, requestHash string, items []currency.RateBatchItem) (*currency.RateBatch, error)
+	GetRateBatchById(ctx context.Context, id string) (*currency.RateBatch, error)
+	RequeueRate(ctx context.Context, id string, actor string) (*currency.CurrencyRate, error)
+	RequeueFailedRates(ctx context.Context, filter currency.FailedRateFilter, limit int, actor string) ([]currency.CurrencyRate, error)
+	ArchiveRate(ctx context.Context, id string, actor string) (*currency.CurrencyRate, error)
+	GetRateAdminActions(ctx context.Context, id string) ([]currency.RateAdminAction, error)
 }
 
 type currencyRepositoryImpl struct {
@@ -32,11 +47,11 @@ func (repo *currencyRepositoryImpl) GetActualRateByCurrency(
 	ctx context.Context,
 	baseCurrency currency.CurrencyCode,
 	resultCurrency currency.CurrencyCode,
+	rateDate *time.Time,
 ) (*currency.CurrencyRate, error) {
 	var task CurrencyRateEntity
 
-	err := repo.db.
-		WithContext(ctx).
+	err := whereRateDate(repo.db.WithContext(ctx), rateDate).
 		Where(&CurrencyRateEntity{
 			BaseCurrency:   string(baseCurrency),
 			ResultCurrency: string(resultCurrency),
@@ -56,6 +71,35 @@ func (repo *currencyRepositoryImpl) GetActualRateByCurrency(
 	return toDomain(&task), nil
 }
 
+// GetActualRatesByCurrencies returns the freshest completed rate of every pair
+// between the codes that has one, in a single query.
+func (repo *currencyRepositoryImpl) GetActualRatesByCurrencies(
+	ctx context.Context,
+	codes []currency.CurrencyCode,
+	rateDate *time.Time,
+) ([]currency.CurrencyRate, error) {
+	var entities []CurrencyRateEntity
+
+	err := whereRateDate(repo.db.WithContext(ctx), rateDate).
+		Select("DISTINCT ON (base_currency, result_currency) *").
+		Where(&CurrencyRateEntity{Status: string(currency.CurrencyRateStatusCompleted)}).
+		Where("base_currency IN ? AND result_currency IN ?", codes, codes).
+		Order("base_currency, result_currency, completed_at DESC").
+		Find(&entities).Error
+
+	if err != nil {
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	rates := make([]currency.CurrencyRate, 0, len(entities))
+
+	for _, e := range entities {
+		rates = append(rates, *toDomain(&e))
+	}
+
+	return rates, nil
+}
+
 func (repo *currencyRepositoryImpl) GetRateById(ctx context.Context, id string) (*currency.CurrencyRate, error) {
 	var task CurrencyRateEntity
 
@@ -72,21 +116,67 @@ func (repo *currencyRepositoryImpl) GetRateById(ctx context.Context, id string)
 	return toDomain(&task), nil
 }
 
+// ListRates pages with a keyset on (sort column, id), so deep pages cost the
+// same as the first one. It returns up to query.Limit rows.
+func (repo *currencyRepositoryImpl) ListRates(ctx context.Context, query currency.RateListQuery) ([]currency.CurrencyRate, error) {
+	var entities []CurrencyRateEntity
+
+	tx := whereRateFilter(repo.db.WithContext(ctx), query.Filter)
+
+	column := "created_at"
+
+	if query.SortField == currency.RateSortUpdatedAt {
+		column = "updated_at"
+	}
+
+	direction, comparison := "ASC", ">"
+
+	if query.Desc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if query.Cursor != nil {
+		tx = tx.Where("("+column+", id) "+comparison+" (?, ?)", query.Cursor.Value, query.Cursor.Id)
+	}
+
+	err := tx.Order(column + " " + direction).Order("id " + direction).Limit(query.Limit).Find(&entities).Error
+
+	if err != nil {
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	rates := make([]currency.CurrencyRate, 0, len(entities))
+
+	for _, e := range entities {
+		rates = append(rates, *toDomain(&e))
+	}
+
+	return rates, nil
+}
+
+// CreateRate dedups on the idempotency key of the client, an empty client id
+// is the key space of anonymous callers.
 func (repo *currencyRepositoryImpl) CreateRate(
 	ctx context.Context,
 	baseCurrency currency.CurrencyCode,
 	resultCurrency currency.CurrencyCode,
+	rateDate *time.Time,
+	clientId string,
 	idempotencyKey string,
+	callbackUrl *string,
 ) (*currency.CurrencyRate, error) {
 	entity := &CurrencyRateEntity{
 		BaseCurrency:   string(baseCurrency),
 		ResultCurrency: string(resultCurrency),
+		RateDate:       rateDate,
+		ClientId:       clientId,
 		IdempotencyKey: idempotencyKey,
 		Status:         string(currency.CurrencyRateStatusPending),
+		CallbackUrl:    callbackUrl,
 	}
 
 	res := repo.db.WithContext(ctx).Clauses(
-		clause.OnConflict{DoNothing: true, Columns: []clause.Column{{Name: "idempotency_key"}}},
+		clause.OnConflict{DoNothing: true, Columns: clientKeyColumns},
 	).Create(&entity)
 
 	if res.Error != nil {
@@ -95,13 +185,15 @@ func (repo *currencyRepositoryImpl) CreateRate(
 
 	if res.RowsAffected == 0 {
 		var existingEntity CurrencyRateEntity
-		err := repo.db.Where(&CurrencyRateEntity{IdempotencyKey: idempotencyKey}).First(&existingEntity).Error
+		err := repo.db.Where("client_id = ? AND idempotency_key = ?", clientId, idempotencyKey).First(&existingEntity).Error
 
 		if err != nil {
 			return nil, error_utils.ErrInternalServerError(err.Error())
 		}
 
-		if existingEntity.BaseCurrency != string(baseCurrency) || existingEntity.ResultCurrency != string(resultCurrency) {
+		if existingEntity.BaseCurrency != string(baseCurrency) ||
+			existingEntity.ResultCurrency != string(resultCurrency) ||
+			!currency.SameRateDate(existingEntity.RateDate, rateDate) {
 			return nil, error_utils.ErrBusinessLogic("CurrencyRateIdempotencyConflict")
 		}
 
@@ -111,38 +203,176 @@ func (repo *currencyRepositoryImpl) CreateRate(
 	return toDomain(entity), nil
 }
 
-func (repo *currencyRepositoryImpl) UpdateRateStatusByIds(
+// FailRatesByIds moves rates claimed with the lease to FAILED with the reason
+// and schedules the completion callbacks. Rates that are no longer held by the
+// lease, e.g. reclaimed after it expired, are left as is. Returns the number
+// of failed rates.
+func (repo *currencyRepositoryImpl) FailRatesByIds(
 	ctx context.Context,
+	lease currency.RateLease,
 	ids []string,
-	status currency.CurrencyRateStatus,
-) error {
-	err := repo.db.WithContext(ctx).Where("id IN ?", ids).UpdateColumns(&CurrencyRateEntity{Status: string(status)}).Error
+	failure currency.RateFailure,
+) (int64, error) {
+	now := time.Now()
+	var entities []CurrencyRateEntity
+
+	err := repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := whereLease(tx.Model(&entities), lease, ids).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "callback_url"}}}).
+			UpdateColumns(&CurrencyRateEntity{
+				Status:         string(currency.CurrencyRateStatusFailed),
+				FailureCode:    (*string)(&failure.Code),
+				FailureMessage: &failure.Message,
+				UpdatedAt:      now,
+			}).Error
+
+		if err != nil {
+			return err
+		}
+
+		return enqueueCallbacks(tx, entities, callback.EventRateFailed, now)
+	})
+
+	if err != nil {
+		return 0, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	return int64(len(entities)), nil
+}
+
+// CancelRate moves a PENDING rate to CANCELLED. The status check is part of
+// the update: a row claimed by FetchAndMarkForProcessing is locked until it is
+// PROCESSING, so the update then matches nothing, and a cancelled row is no
+// longer PENDING for the worker.
+func (repo *currencyRepositoryImpl) CancelRate(ctx context.Context, id string) (*currency.CurrencyRate, error) {
+	now := time.Now()
+	var entities []CurrencyRateEntity
 
-	return err
+	err := repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entities).
+			Clauses(clause.Returning{}).
+			Where(&CurrencyRateEntity{Id: id, Status: string(currency.CurrencyRateStatusPending)}).
+			UpdateColumns(&CurrencyRateEntity{Status: string(currency.CurrencyRateStatusCancelled), UpdatedAt: now}).Error
+
+		if err != nil {
+			return err
+		}
+
+		return enqueueCallbacks(tx, entities, callback.EventRateCancelled, now)
+	})
+
+	if err != nil {
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	if len(entities) == 0 {
+		existing, err := repo.GetRateById(ctx, id)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, currency.ErrCurrencyRateNotCancellable(existing.Status)
+	}
+
+	return toDomain(&entities[0]), nil
 }
 
-func (repo *currencyRepositoryImpl) SaveRatesByIds(ctx context.Context, ids []string, rate float64) error {
+// SaveRatesByIds completes rates claimed with the lease, like FailRatesByIds
+// it skips rates that are no longer held by the lease. Returns the number of
+// completed rates.
+func (repo *currencyRepositoryImpl) SaveRatesByIds(
+	ctx context.Context,
+	lease currency.RateLease,
+	ids []string,
+	quote currency.RateQuote,
+) (int64, error) {
 	now := time.Now()
+	var entities []CurrencyRateEntity
+
+	err := repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := whereLease(tx.Model(&entities), lease, ids).
+			Clauses(clause.Returning{Columns: []clause.Column{
+				{Name: "id"},
+				{Name: "base_currency"},
+				{Name: "result_currency"},
+				{Name: "rate_date"},
+				{Name: "callback_url"},
+			}}).
+			UpdateColumns(&CurrencyRateEntity{
+				Status:        string(currency.CurrencyRateStatusCompleted),
+				UpdatedAt:     now,
+				CompletedAt:   &now,
+				Rate:          &quote.Rate,
+				PivotCurrency: (*string)(quote.PivotCurrency),
+				Provider:      optionalString(quote.Provider),
+			}).Error
+
+		if err != nil {
+			return err
+		}
 
-	err := repo.db.WithContext(ctx).Where("id IN ?", ids).
+		if err := publishRateEvents(tx, entities, quote, now); err != nil {
+			return err
+		}
+
+		return enqueueCallbacks(tx, entities, callback.EventRateCompleted, now)
+	})
+
+	if err != nil {
+		return 0, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	return int64(len(entities)), nil
+}
+
+// RetryRatesByIds returns rates claimed with the lease to PENDING, they are
+// picked up again once nextAttemptAt has passed. Returns the number of
+// rescheduled rates.
+func (repo *currencyRepositoryImpl) RetryRatesByIds(
+	ctx context.Context,
+	lease currency.RateLease,
+	ids []string,
+	nextAttemptAt time.Time,
+	failure currency.RateFailure,
+) (int64, error) {
+	res := whereLease(repo.db.WithContext(ctx).Model(&CurrencyRateEntity{}), lease, ids).
 		UpdateColumns(&CurrencyRateEntity{
-			Status:      string(currency.CurrencyRateStatusCompleted),
-			UpdatedAt:   now,
-			CompletedAt: &now,
-			Rate:        &rate,
-		}).Error
+			Status:         string(currency.CurrencyRateStatusPending),
+			NextAttemptAt:  &nextAttemptAt,
+			FailureCode:    (*string)(&failure.Code),
+			FailureMessage: &failure.Message,
+			UpdatedAt:      time.Now(),
+		})
+
+	if res.Error != nil {
+		return 0, error_utils.ErrInternalServerError(res.Error.Error())
+	}
+
+	return res.RowsAffected, nil
+}
 
-	return err
+// whereLease matches the rates of ids that are still PROCESSING under the
+// lease.
+func whereLease(db *gorm.DB, lease currency.RateLease, ids []string) *gorm.DB {
+	return db.
+		Where("id IN ?", ids).
+		Where(&CurrencyRateEntity{Status: string(currency.CurrencyRateStatusProcessing)}).
+		Where("worker_id = ? AND processing_started_at = ?", lease.WorkerId, lease.StartedAt)
 }
 
-func (repo *currencyRepositoryImpl) FetchAndMarkForProcessing(ctx context.Context, limit int) ([]currency.CurrencyRate, error) {
+// FetchAndMarkForProcessing claims due PENDING rates for the worker and counts
+// the attempt. The claim is a lease starting now, see ReclaimExpiredRates.
+func (repo *currencyRepositoryImpl) FetchAndMarkForProcessing(ctx context.Context, workerId string, limit int) ([]currency.CurrencyRate, error) {
 	var entities []CurrencyRateEntity
+	now := time.Now()
 
 	err := repo.db.Transaction(func(tx *gorm.DB) error {
 		subquery := tx.
 			WithContext(ctx).
 			Model(&CurrencyRateEntity{}).
 			Where(&CurrencyRateEntity{Status: string(currency.CurrencyRateStatusPending)}).
+			Where("next_attempt_at IS NULL OR next_attempt_at <= ?", now).
 			Order("created_at ASC").
 			Limit(limit).
 			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
@@ -152,9 +382,12 @@ func (repo *currencyRepositoryImpl) FetchAndMarkForProcessing(ctx context.Contex
 			WithContext(ctx).
 			Clauses(clause.Returning{}).
 			Where("id IN (?)", subquery).
-			UpdateColumns(&CurrencyRateEntity{
-				Status:    string(currency.CurrencyRateStatusProcessing),
-				UpdatedAt: time.Now(),
+			UpdateColumns(map[string]any{
+				"status":                string(currency.CurrencyRateStatusProcessing),
+				"attempts":              gorm.Expr("attempts + 1"),
+				"worker_id":             workerId,
+				"processing_started_at": now,
+				"updated_at":            now,
 			}).Error
 
 		return err
@@ -170,3 +403,241 @@ func (repo *currencyRepositoryImpl) FetchAndMarkForProcessing(ctx context.Contex
 	}
 	return models, nil
 }
+
+// ReclaimExpiredRates takes back PROCESSING rates whose lease started before
+// startedBefore: they return to PENDING, or become FAILED once they used up
+// maxAttempts. Rates claimed before leases existed are judged by updated_at.
+// The returned rates carry the new status and the expired lease.
+func (repo *currencyRepositoryImpl) ReclaimExpiredRates(
+	ctx context.Context,
+	startedBefore time.Time,
+	maxAttempts int,
+	limit int,
+) ([]currency.CurrencyRate, error) {
+	var reclaimed, failed []CurrencyRateEntity
+	now := time.Now()
+	failureCode := string(currency.RateFailureLeaseExpired)
+	failureMessage := "worker lease expired"
+
+	err := repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []string
+
+		err := tx.Model(&CurrencyRateEntity{}).
+			Where(&CurrencyRateEntity{Status: string(currency.CurrencyRateStatusProcessing)}).
+			Where("COALESCE(processing_started_at, updated_at) < ?", startedBefore).
+			Order("created_at ASC").
+			Limit(limit).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Pluck("id", &ids).Error
+
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		err = tx.Model(&failed).
+			Clauses(clause.Returning{}).
+			Where("id IN ? AND attempts >= ?", ids, maxAttempts).
+			UpdateColumns(&CurrencyRateEntity{
+				Status:         string(currency.CurrencyRateStatusFailed),
+				FailureCode:    &failureCode,
+				FailureMessage: &failureMessage,
+				UpdatedAt:      now,
+			}).Error
+
+		if err != nil {
+			return err
+		}
+
+		if err := enqueueCallbacks(tx, failed, callback.EventRateFailed, now); err != nil {
+			return err
+		}
+
+		return tx.Model(&reclaimed).
+			Clauses(clause.Returning{}).
+			Where("id IN ? AND attempts < ?", ids, maxAttempts).
+			UpdateColumns(&CurrencyRateEntity{
+				Status:         string(currency.CurrencyRateStatusPending),
+				NextAttemptAt:  &now,
+				FailureCode:    &failureCode,
+				FailureMessage: &failureMessage,
+				UpdatedAt:      now,
+			}).Error
+	})
+
+	if err != nil {
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	models := make([]currency.CurrencyRate, 0, len(failed)+len(reclaimed))
+	for _, e := range append(failed, reclaimed...) {
+		models = append(models, *toDomain(&e))
+	}
+
+	return models, nil
+}
+
+// GetRateHistory returns one rate for every business date of the pair in
+// [from, to], ordered by date: the freshest completed rate request, otherwise
+// the rate backfilled from a provider.
+func (repo *currencyRepositoryImpl) GetRateHistory(
+	ctx context.Context,
+	baseCurrency currency.CurrencyCode,
+	resultCurrency currency.CurrencyCode,
+	from time.Time,
+	to time.Time,
+) ([]currency.TimeSeriesPoint, error) {
+	db := repo.db.WithContext(ctx)
+	fromDate, toDate := from.Format(currency.RateDateLayout), to.Format(currency.RateDateLayout)
+
+	var entities []CurrencyRateEntity
+
+	err := db.
+		Select("DISTINCT ON (rate_date) *").
+		Where(&CurrencyRateEntity{
+			BaseCurrency:   string(baseCurrency),
+			ResultCurrency: string(resultCurrency),
+			Status:         string(currency.CurrencyRateStatusCompleted),
+		}).
+		Where("rate_date BETWEEN ? AND ?", fromDate, toDate).
+		Order("rate_date ASC, completed_at DESC").
+		Find(&entities).Error
+
+	if err != nil {
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	var history []RateHistoryEntity
+
+	err = db.
+		Where(&RateHistoryEntity{BaseCurrency: string(baseCurrency), ResultCurrency: string(resultCurrency)}).
+		Where("rate_date BETWEEN ? AND ?", fromDate, toDate).
+		Order("rate_date ASC").
+		Find(&history).Error
+
+	if err != nil {
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	points := make([]currency.TimeSeriesPoint, 0, len(entities)+len(history))
+	known := make(map[string]bool, len(entities))
+
+	for _, e := range entities {
+		points = append(points, currency.TimeSeriesPoint{Date: e.RateDate.UTC(), Rate: *e.Rate})
+		known[e.RateDate.UTC().Format(currency.RateDateLayout)] = true
+	}
+
+	for _, e := range history {
+		if known[e.RateDate.UTC().Format(currency.RateDateLayout)] {
+			continue
+		}
+
+		point := currency.TimeSeriesPoint{Date: e.RateDate.UTC(), Rate: e.Rate, FromProvider: true}
+
+		if e.Provider != nil {
+			point.Provider = *e.Provider
+		}
+
+		points = append(points, point)
+	}
+
+	sort.Slice(points, func(i, j int) bool { return points[i].Date.Before(points[j].Date) })
+
+	return points, nil
+}
+
+// SaveRateHistory stores provider points in currencies_rates_history, a date
+// already stored for the pair is kept.
+func (repo *currencyRepositoryImpl) SaveRateHistory(
+	ctx context.Context,
+	baseCurrency currency.CurrencyCode,
+	resultCurrency currency.CurrencyCode,
+	points []currency.TimeSeriesPoint,
+) error {
+	if len(points) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	entities := make([]RateHistoryEntity, 0, len(points))
+
+	for _, p := range points {
+		entities = append(entities, RateHistoryEntity{
+			BaseCurrency:   string(baseCurrency),
+			ResultCurrency: string(resultCurrency),
+			RateDate:       p.Date,
+			Rate:           p.Rate,
+			Provider:       optionalString(p.Provider),
+			CreatedAt:      now,
+		})
+	}
+
+	err := repo.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&entities).Error
+
+	if err != nil {
+		return error_utils.ErrInternalServerError(err.Error())
+	}
+
+	return nil
+}
+
+// whereRateFilter applies the list filter of rates, zero fields don't filter.
+func whereRateFilter(tx *gorm.DB, filter currency.RateFilter) *gorm.DB {
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, 0, len(filter.Statuses))
+
+		for _, status := range filter.Statuses {
+			statuses = append(statuses, string(status))
+		}
+
+		tx = tx.Where("status IN ?", statuses)
+	}
+
+	tx = tx.Where(&CurrencyRateEntity{
+		BaseCurrency:   string(filter.BaseCurrency),
+		ResultCurrency: string(filter.ResultCurrency),
+		IdempotencyKey: filter.IdempotencyKey,
+	})
+
+	if filter.CreatedFrom != nil {
+		tx = tx.Where("created_at >= ?", *filter.CreatedFrom)
+	}
+
+	if filter.CreatedTo != nil {
+		tx = tx.Where("created_at < ?", *filter.CreatedTo)
+	}
+
+	if filter.CompletedFrom != nil {
+		tx = tx.Where("completed_at >= ?", *filter.CompletedFrom)
+	}
+
+	if filter.CompletedTo != nil {
+		tx = tx.Where("completed_at < ?", *filter.CompletedTo)
+	}
+
+	if filter.UpdatedFrom != nil {
+		tx = tx.Where("updated_at >= ?", *filter.UpdatedFrom)
+	}
+
+	if filter.UpdatedTo != nil {
+		tx = tx.Where("updated_at < ?", *filter.UpdatedTo)
+	}
+
+	if filter.Archived != nil {
+		if *filter.Archived {
+			tx = tx.Where("archived_at IS NOT NULL")
+		} else {
+			tx = tx.Where("archived_at IS NULL")
+		}
+	}
+
+	return tx
+}
+
+// whereRateDate scopes a query to one business date, nil means "latest" rows.
+func whereRateDate(tx *gorm.DB, rateDate *time.Time) *gorm.DB {
+	if rateDate == nil {
+		return tx.Where("rate_date IS NULL")
+	}
+
+	return tx.Where("rate_date = ?", rateDate.UTC().Format(currency.RateDateLayout))
+}
@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"currency-rate-app/internal/domains/currency"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func claimRate(t *testing.T, repo *currencyRepositoryImpl, workerId string, id string) currency.CurrencyRate {
+	t.Helper()
+
+	claimed, err := repo.FetchAndMarkForProcessing(context.Background(), workerId, 1000)
+	require.NoError(t, err)
+
+	for _, rate := range claimed {
+		if rate.Id == id {
+			return rate
+		}
+	}
+
+	t.Fatalf("rate %s wasn't claimed by %s", id, workerId)
+
+	return currency.CurrencyRate{}
+}
+
+func TestCurrencyRepository_ReclaimedRateIsNotOverwrittenByOldWorker(t *testing.T) {
+	repo := NewCurrencyRepository(openTestDB(t))
+	ctx := context.Background()
+
+	created, err := repo.CreateRate(ctx, currency.USD, currency.EUR, nil, "", fmt.Sprintf("lease-%d", time.Now().UnixNano()), nil)
+	require.NoError(t, err)
+
+	oldClaim := claimRate(t, repo, "worker-1", created.Id)
+
+	_, err = repo.ReclaimExpiredRates(ctx, time.Now().Add(time.Hour), 10, 1000)
+	require.NoError(t, err)
+
+	newClaim := claimRate(t, repo, "worker-2", created.Id)
+	quote := currency.RateQuote{Rate: decimal.RequireFromString("0.9"), Provider: "frankfurter"}
+	failure := currency.RateFailure{Code: currency.RateFailureProviderUnavailable, Message: "timeout"}
+
+	saved, err := repo.SaveRatesByIds(ctx, oldClaim.Lease(), []string{created.Id}, quote)
+	require.NoError(t, err)
+	assert.Zero(t, saved)
+
+	retried, err := repo.RetryRatesByIds(ctx, oldClaim.Lease(), []string{created.Id}, time.Now(), failure)
+	require.NoError(t, err)
+	assert.Zero(t, retried)
+
+	failed, err := repo.FailRatesByIds(ctx, oldClaim.Lease(), []string{created.Id}, failure)
+	require.NoError(t, err)
+	assert.Zero(t, failed)
+
+	stored, err := repo.GetRateById(ctx, created.Id)
+	require.NoError(t, err)
+	assert.Equal(t, currency.CurrencyRateStatusProcessing, stored.Status)
+	assert.Equal(t, "worker-2", *stored.WorkerId)
+	assert.Nil(t, stored.Rate)
+
+	saved, err = repo.SaveRatesByIds(ctx, newClaim.Lease(), []string{created.Id}, quote)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), saved)
+}
+
+func TestCurrencyRepository_RateHistoryIsStoredApartFromRates(t *testing.T) {
+	tx := openTestDB(t)
+	repo := NewCurrencyRepository(tx)
+	ctx := context.Background()
+	from := time.Date(1999, 1, 4, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+
+	points := []currency.TimeSeriesPoint{
+		{Date: from, Rate: decimal.RequireFromString("0.85"), FromProvider: true, Provider: "Frankfurter"},
+		{Date: to, Rate: decimal.RequireFromString("0.86"), FromProvider: true, Provider: "Frankfurter"},
+	}
+
+	require.NoError(t, repo.SaveRateHistory(ctx, currency.USD, currency.EUR, points))
+	require.NoError(t, repo.SaveRateHistory(ctx, currency.USD, currency.EUR, []currency.TimeSeriesPoint{
+		{Date: from, Rate: decimal.RequireFromString("0.99"), FromProvider: true, Provider: "Frankfurter"},
+	}))
+
+	history, err := repo.GetRateHistory(ctx, currency.USD, currency.EUR, from, to)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, "0.85", history[0].Rate.String(), "a stored date is kept")
+	assert.True(t, history[0].FromProvider)
+	assert.Equal(t, "Frankfurter", history[1].Provider)
+
+	var rates int64
+	require.NoError(t, tx.Model(&CurrencyRateEntity{}).Where("rate_date BETWEEN ? AND ?", from, to).Count(&rates).Error)
+	assert.Zero(t, rates, "history doesn't show up as rate requests")
+}
+
+func TestCurrencyRepository_IdempotencyKeysArePerClient(t *testing.T) {
+	repo := NewCurrencyRepository(openTestDB(t))
+	ctx := context.Background()
+	key := fmt.Sprintf("shared-%d", time.Now().UnixNano())
+
+	first, err := repo.CreateRate(ctx, currency.USD, currency.EUR, nil, "client-a", key, nil)
+	require.NoError(t, err)
+
+	other, err := repo.CreateRate(ctx, currency.USD, currency.MXN, nil, "client-b", key, nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.Id, other.Id, "another client's key is a new rate")
+
+	retried, err := repo.CreateRate(ctx, currency.USD, currency.EUR, nil, "client-a", key, nil)
+	require.NoError(t, err)
+	assert.Equal(t, first.Id, retried.Id)
+
+	_, err = repo.CreateRate(ctx, currency.USD, currency.MXN, nil, "client-a", key, nil)
+	assert.Error(t, err, "the same client reusing the key for another pair conflicts")
+}
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var (
+	testDbOnce sync.Once
+	testDb     *gorm.DB
+	testDbErr  error
+)
+
+// openTestDB returns a transaction on the database of TEST_DATABASE_URL that
+// is rolled back when the test ends. Tests are skipped without the database.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	testDbOnce.Do(func() {
+		testDb, testDbErr = gorm.Open(postgres.Open(url), &gorm.Config{Logger: logger.Discard})
+
+		if testDbErr == nil {
+			testDbErr = Migrate(testDb)
+		}
+	})
+
+	if testDbErr != nil {
+		t.Fatal(testDbErr)
+	}
+
+	tx := testDb.Begin()
+
+	if tx.Error != nil {
+		t.Fatal(tx.Error)
+	}
+
+	t.Cleanup(func() {
+		tx.Rollback()
+	})
+
+	return tx
+}
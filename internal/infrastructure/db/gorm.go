@@ -12,8 +12,8 @@ import (
 	"gorm.io/gorm/logger"
 )
 
-func SetupGorm(cfg *config.Config) *gorm.DB {
-	databaseUrl := fmt.Sprintf(
+func DatabaseUrl(cfg *config.Config) string {
+	return fmt.Sprintf(
 		"postgres://%s:%s@%s:%d/%s",
 		cfg.DatabaseUsername,
 		cfg.DatabasePassword,
@@ -21,7 +21,10 @@ func SetupGorm(cfg *config.Config) *gorm.DB {
 		cfg.DatabasePort,
 		cfg.DatabaseName,
 	)
-	db, err := gorm.Open(postgres.Open(databaseUrl), &gorm.Config{
+}
+
+func SetupGorm(cfg *config.Config) *gorm.DB {
+	db, err := gorm.Open(postgres.Open(DatabaseUrl(cfg)), &gorm.Config{
 		Logger: SlogLogger{},
 	})
 
@@ -29,15 +32,38 @@ func SetupGorm(cfg *config.Config) *gorm.DB {
 		panic(err)
 	}
 
-	err = db.AutoMigrate(&CurrencyRateEntity{})
-
-	if err != nil {
+	if err := Migrate(db); err != nil {
 		panic(err)
 	}
 
 	return db
 }
 
+// Migrate runs the explicit migrations and brings the tables up to date with
+// the entities.
+func Migrate(db *gorm.DB) error {
+	if err := runMigrations(db); err != nil {
+		return err
+	}
+
+	return db.AutoMigrate(
+		&CurrencyRateEntity{},
+		&RateHistoryEntity{},
+		&SpreadEntity{},
+		&SpreadHistoryEntity{},
+		&SubscriptionEntity{},
+		&AlertRuleEntity{},
+		&AlertFiringEntity{},
+		&CallbackDeliveryEntity{},
+		&CallbackAttemptEntity{},
+		&RateEventEntity{},
+		&RateBatchEntity{},
+		&RateBatchItemEntity{},
+		&CurrencyRateAdminActionEntity{},
+		&IdempotencyKeyEntity{},
+	)
+}
+
 type SlogLogger struct{}
 
 func (l SlogLogger) LogMode(logger.LogLevel) logger.Interface { return l }
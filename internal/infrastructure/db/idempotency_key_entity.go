@@ -0,0 +1,44 @@
+package db
+
+import (
+	"currency-rate-app/internal/common/idempotency"
+	"net/http"
+	"time"
+)
+
+type IdempotencyKeyEntity struct {
+	Scope          string `gorm:"primaryKey"`
+	IdempotencyKey string `gorm:"primaryKey"`
+	RequestHash    string `gorm:"not null"`
+	// Response columns stay empty while the request is in flight
+	ResponseStatus *int
+	ResponseHeader http.Header `gorm:"type:jsonb;serializer:json"`
+	ResponseBody   []byte
+	ExpiresAt      time.Time `gorm:"not null;index"`
+	CreatedAt      time.Time `gorm:"not null"`
+	UpdatedAt      time.Time `gorm:"not null"`
+}
+
+func (IdempotencyKeyEntity) TableName() string {
+	return "idempotency_keys"
+}
+
+func idempotencyRecordToDomain(e *IdempotencyKeyEntity) *idempotency.Record {
+	record := &idempotency.Record{
+		Scope:       e.Scope,
+		Key:         e.IdempotencyKey,
+		RequestHash: e.RequestHash,
+		ExpiresAt:   e.ExpiresAt,
+		CreatedAt:   e.CreatedAt,
+	}
+
+	if e.ResponseStatus != nil {
+		record.Response = &idempotency.Response{
+			StatusCode: *e.ResponseStatus,
+			Header:     e.ResponseHeader,
+			Body:       e.ResponseBody,
+		}
+	}
+
+	return record
+}
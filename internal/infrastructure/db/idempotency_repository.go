@@ -0,0 +1,157 @@
+package db
+
+import (
+	"context"
+	error_utils "currency-rate-app/internal/common/error-utils"
+	"currency-rate-app/internal/common/idempotency"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type idempotencyRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepository(db *gorm.DB) *idempotencyRepositoryImpl {
+	return &idempotencyRepositoryImpl{db: db}
+}
+
+var _ idempotency.Store = (*idempotencyRepositoryImpl)(nil)
+
+// Acquire inserts the key, or takes over an expired one. The insert waits for
+// a concurrent insert of the same key to commit, so only one request wins.
+func (repo *idempotencyRepositoryImpl) Acquire(
+	ctx context.Context,
+	scope string,
+	key string,
+	requestHash string,
+	now time.Time,
+	lockedUntil time.Time,
+) (*idempotency.Record, bool, error) {
+	db := repo.db.WithContext(ctx)
+	entity := IdempotencyKeyEntity{
+		Scope:          scope,
+		IdempotencyKey: key,
+		RequestHash:    requestHash,
+		ExpiresAt:      lockedUntil,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	res := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&entity)
+
+	if res.Error != nil {
+		return nil, false, error_utils.ErrInternalServerError(res.Error.Error())
+	}
+
+	if res.RowsAffected == 1 {
+		return idempotencyRecordToDomain(&entity), true, nil
+	}
+
+	res = db.Model(&IdempotencyKeyEntity{}).
+		Where(&IdempotencyKeyEntity{Scope: scope, IdempotencyKey: key}).
+		Where("expires_at <= ?", now).
+		UpdateColumns(map[string]any{
+			"request_hash":    requestHash,
+			"response_status": nil,
+			"response_header": nil,
+			"response_body":   nil,
+			"expires_at":      lockedUntil,
+			"created_at":      now,
+			"updated_at":      now,
+		})
+
+	if res.Error != nil {
+		return nil, false, error_utils.ErrInternalServerError(res.Error.Error())
+	}
+
+	if res.RowsAffected == 1 {
+		return idempotencyRecordToDomain(&entity), true, nil
+	}
+
+	var existing IdempotencyKeyEntity
+
+	err := db.Where(&IdempotencyKeyEntity{Scope: scope, IdempotencyKey: key}).First(&existing).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Released or cleaned up in between, the holder was just finishing.
+		return nil, false, idempotency.ErrIdempotencyRequestInProgress()
+	}
+
+	if err != nil {
+		return nil, false, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	return idempotencyRecordToDomain(&existing), false, nil
+}
+
+// Complete is guarded by the request hash and an empty response, so a request
+// whose lock timed out and was taken over doesn't overwrite the new holder.
+func (repo *idempotencyRepositoryImpl) Complete(
+	ctx context.Context,
+	scope string,
+	key string,
+	requestHash string,
+	response idempotency.Response,
+	expiresAt time.Time,
+) error {
+	header, err := json.Marshal(response.Header)
+
+	if err != nil {
+		return error_utils.ErrInternalServerError(err.Error())
+	}
+
+	err = repo.db.WithContext(ctx).
+		Model(&IdempotencyKeyEntity{}).
+		Where(&IdempotencyKeyEntity{Scope: scope, IdempotencyKey: key, RequestHash: requestHash}).
+		Where("response_status IS NULL").
+		UpdateColumns(map[string]any{
+			"response_status": response.StatusCode,
+			"response_header": string(header),
+			"response_body":   response.Body,
+			"expires_at":      expiresAt,
+			"updated_at":      time.Now(),
+		}).Error
+
+	if err != nil {
+		return error_utils.ErrInternalServerError(err.Error())
+	}
+
+	return nil
+}
+
+func (repo *idempotencyRepositoryImpl) Release(ctx context.Context, scope string, key string, requestHash string) error {
+	err := repo.db.WithContext(ctx).
+		Where(&IdempotencyKeyEntity{Scope: scope, IdempotencyKey: key, RequestHash: requestHash}).
+		Where("response_status IS NULL").
+		Delete(&IdempotencyKeyEntity{}).Error
+
+	if err != nil {
+		return error_utils.ErrInternalServerError(err.Error())
+	}
+
+	return nil
+}
+
+func (repo *idempotencyRepositoryImpl) DeleteExpired(ctx context.Context, now time.Time, limit int) (int64, error) {
+	db := repo.db.WithContext(ctx)
+	subquery := db.
+		Model(&IdempotencyKeyEntity{}).
+		Select("scope", "idempotency_key").
+		Where("expires_at <= ?", now).
+		Limit(limit)
+
+	res := db.
+		Where("(scope, idempotency_key) IN (?)", subquery).
+		Delete(&IdempotencyKeyEntity{})
+
+	if res.Error != nil {
+		return 0, error_utils.ErrInternalServerError(res.Error.Error())
+	}
+
+	return res.RowsAffected, nil
+}
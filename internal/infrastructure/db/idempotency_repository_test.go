@@ -0,0 +1,91 @@
+package db
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"currency-rate-app/internal/common/idempotency"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyRepository_AcquireCompleteReplay(t *testing.T) {
+	repo := NewIdempotencyRepository(openTestDB(t))
+	ctx := context.Background()
+	now := time.Now()
+
+	record, acquired, err := repo.Acquire(ctx, "client:a", "key-1", "hash-1", now, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, acquired)
+	assert.True(t, record.InFlight())
+
+	record, acquired, err = repo.Acquire(ctx, "client:a", "key-1", "hash-1", now, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, acquired)
+	assert.True(t, record.InFlight())
+
+	response := idempotency.Response{StatusCode: http.StatusAccepted, Header: http.Header{"Location": {"/v1/currencies/1/status"}}, Body: []byte(`{"id":"1"}`)}
+
+	require.NoError(t, repo.Complete(ctx, "client:a", "key-1", "hash-2", idempotency.Response{StatusCode: http.StatusOK}, now.Add(time.Hour)))
+	require.NoError(t, repo.Complete(ctx, "client:a", "key-1", "hash-1", response, now.Add(time.Hour)))
+
+	record, acquired, err = repo.Acquire(ctx, "client:a", "key-1", "hash-1", now, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, acquired)
+	require.False(t, record.InFlight())
+	assert.Equal(t, response, *record.Response)
+
+	_, acquired, err = repo.Acquire(ctx, "client:b", "key-1", "hash-1", now, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, acquired, "keys of another client don't collide")
+}
+
+func TestIdempotencyRepository_ReleaseAndTakeOver(t *testing.T) {
+	repo := NewIdempotencyRepository(openTestDB(t))
+	ctx := context.Background()
+	now := time.Now()
+
+	_, _, err := repo.Acquire(ctx, "client:a", "key-1", "hash-1", now, now.Add(time.Minute))
+	require.NoError(t, err)
+	require.NoError(t, repo.Release(ctx, "client:a", "key-1", "hash-1"))
+
+	_, acquired, err := repo.Acquire(ctx, "client:a", "key-1", "hash-2", now, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, acquired, "a released key is free")
+
+	later := now.Add(2 * time.Minute)
+	record, acquired, err := repo.Acquire(ctx, "client:a", "key-1", "hash-3", later, later.Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, acquired, "an expired lock is taken over")
+	assert.Equal(t, "hash-3", record.RequestHash)
+
+	require.NoError(t, repo.Complete(ctx, "client:a", "key-1", "hash-2", idempotency.Response{StatusCode: http.StatusOK}, later.Add(time.Hour)))
+	require.NoError(t, repo.Release(ctx, "client:a", "key-1", "hash-2"))
+
+	record, acquired, err = repo.Acquire(ctx, "client:a", "key-1", "hash-3", later, later.Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, acquired)
+	assert.True(t, record.InFlight(), "the request that lost the key doesn't touch the new holder")
+}
+
+func TestIdempotencyRepository_DeleteExpired(t *testing.T) {
+	repo := NewIdempotencyRepository(openTestDB(t))
+	ctx := context.Background()
+	now := time.Now()
+
+	_, _, err := repo.Acquire(ctx, "client:a", "expired", "hash", now.Add(-time.Hour), now.Add(-time.Minute))
+	require.NoError(t, err)
+	_, _, err = repo.Acquire(ctx, "client:a", "live", "hash", now, now.Add(time.Minute))
+	require.NoError(t, err)
+
+	deleted, err := repo.DeleteExpired(ctx, now, 1000)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, deleted, int64(1))
+
+	_, acquired, err := repo.Acquire(ctx, "client:a", "live", "hash", now, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, acquired, "unexpired keys are kept")
+}
@@ -0,0 +1,135 @@
+package db
+
+import (
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Explicit migrations for changes AutoMigrate can't express safely (type
+// changes, data backfills). They run before AutoMigrate, once per database.
+type migration struct {
+	Name string
+	Up   func(tx *gorm.DB) error
+}
+
+var migrations = []migration{
+	{Name: "0001_currencies_rates_rate_numeric", Up: migrateRateToNumeric},
+	{Name: "0002_currencies_rates_history", Up: migrateRateHistory},
+	{Name: "0003_idempotency_keys_per_client", Up: migrateIdempotencyKeysPerClient},
+}
+
+type SchemaMigrationEntity struct {
+	Name      string    `gorm:"primaryKey"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (SchemaMigrationEntity) TableName() string {
+	return "schema_migrations"
+}
+
+// runMigrations applies pending migrations in one transaction under an
+// advisory lock, so instances starting together don't run the same migration
+// twice: the second one waits and then finds it recorded.
+func runMigrations(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", SchemaMigrationEntity{}.TableName()).Error; err != nil {
+			return err
+		}
+
+		if err := tx.AutoMigrate(&SchemaMigrationEntity{}); err != nil {
+			return err
+		}
+
+		for _, m := range migrations {
+			var count int64
+
+			if err := tx.Model(&SchemaMigrationEntity{}).Where(&SchemaMigrationEntity{Name: m.Name}).Count(&count).Error; err != nil {
+				return err
+			}
+
+			if count > 0 {
+				continue
+			}
+
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+
+			slog.Info("Migration applied", slog.String("name", m.Name))
+
+			if err := tx.Create(&SchemaMigrationEntity{Name: m.Name, AppliedAt: time.Now()}).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Rates used to be stored as double precision. Casting through numeric keeps
+// the value postgres prints for the float, which is what clients have seen.
+func migrateRateToNumeric(tx *gorm.DB) error {
+	if !tx.Migrator().HasTable(&CurrencyRateEntity{}) {
+		return nil
+	}
+
+	return tx.Exec("ALTER TABLE currencies_rates ALTER COLUMN rate TYPE numeric USING rate::numeric").Error
+}
+
+// Time series backfills used to be stored as completed rate requests keyed
+// "timeseries:<base>:<result>:<date>". They move to currencies_rates_history.
+// A database older than the time series has no rate_date or provider yet
+// (AutoMigrate adds them after the migrations), so there is nothing to move.
+func migrateRateHistory(tx *gorm.DB) error {
+	if err := tx.AutoMigrate(&RateHistoryEntity{}); err != nil {
+		return err
+	}
+
+	migrator := tx.Migrator()
+
+	if !migrator.HasTable(&CurrencyRateEntity{}) ||
+		!migrator.HasColumn(&CurrencyRateEntity{}, "RateDate") ||
+		!migrator.HasColumn(&CurrencyRateEntity{}, "Provider") {
+		return nil
+	}
+
+	err := tx.Exec(`
+		INSERT INTO currencies_rates_history (base_currency, result_currency, rate_date, rate, provider, created_at)
+		SELECT base_currency, result_currency, rate_date, rate, provider, created_at
+		FROM currencies_rates
+		WHERE idempotency_key LIKE 'timeseries:%' AND rate_date IS NOT NULL AND rate IS NOT NULL
+		ON CONFLICT DO NOTHING`).Error
+
+	if err != nil {
+		return err
+	}
+
+	return tx.Exec("DELETE FROM currencies_rates WHERE idempotency_key LIKE 'timeseries:%'").Error
+}
+
+// Idempotency keys of rates and batches used to be unique across clients. The
+// client id joins the unique index in place of the key alone, existing rows
+// become keys of anonymous callers.
+func migrateIdempotencyKeysPerClient(tx *gorm.DB) error {
+	for _, table := range []string{CurrencyRateEntity{}.TableName(), RateBatchEntity{}.TableName()} {
+		if !tx.Migrator().HasTable(table) {
+			continue
+		}
+
+		statements := []string{
+			"ALTER TABLE " + table + " ADD COLUMN IF NOT EXISTS client_id text NOT NULL DEFAULT ''",
+			"CREATE UNIQUE INDEX IF NOT EXISTS idx_" + table + "_client_key ON " + table + " (client_id, idempotency_key)",
+			"DROP INDEX IF EXISTS idx_" + table + "_idempotency_key",
+		}
+
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
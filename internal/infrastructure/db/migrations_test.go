@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"currency-rate-app/internal/domains/currency"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunMigrations_ConcurrentInstances(t *testing.T) {
+	openTestDB(t)
+
+	errs := make([]error, 4)
+	var wg sync.WaitGroup
+
+	for i := range errs {
+		wg.Go(func() {
+			errs[i] = runMigrations(testDb)
+		})
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+
+	var count int64
+	require.NoError(t, testDb.Model(&SchemaMigrationEntity{}).Count(&count).Error)
+	assert.Equal(t, int64(len(migrations)), count, "every migration is recorded once")
+}
+
+// baselineSchema is currencies_rates as the first release created it, before
+// any of the columns AutoMigrate adds after the migrations.
+const baselineSchema = `
+	CREATE TABLE currencies_rates (
+		id uuid DEFAULT gen_random_uuid() PRIMARY KEY,
+		idempotency_key text NOT NULL,
+		base_currency text NOT NULL,
+		result_currency text NOT NULL,
+		status text NOT NULL,
+		rate double precision,
+		completed_at timestamptz,
+		created_at timestamptz NOT NULL,
+		updated_at timestamptz NOT NULL
+	);
+	CREATE UNIQUE INDEX idx_currencies_rates_idempotency_key ON currencies_rates (idempotency_key)`
+
+func TestMigrate_FromBaselineSchema(t *testing.T) {
+	tx := openTestDB(t)
+	schema := fmt.Sprintf("baseline_%d", time.Now().UnixNano())
+
+	require.NoError(t, tx.Exec("CREATE SCHEMA "+schema).Error)
+	require.NoError(t, tx.Exec("SET LOCAL search_path TO "+schema).Error)
+	require.NoError(t, tx.Exec(baselineSchema).Error)
+	require.NoError(t, tx.Exec(`
+		INSERT INTO currencies_rates (idempotency_key, base_currency, result_currency, status, rate, completed_at, created_at, updated_at)
+		VALUES ('baseline-1', 'USD', 'EUR', 'COMPLETED', 0.92, now(), now(), now())`).Error)
+
+	require.NoError(t, Migrate(tx))
+
+	rate, err := NewCurrencyRepository(tx).GetActualRateByCurrency(context.Background(), currency.USD, currency.EUR, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "0.92", rate.Rate.String())
+
+	var applied int64
+	require.NoError(t, tx.Model(&SchemaMigrationEntity{}).Count(&applied).Error)
+	assert.Equal(t, int64(len(migrations)), applied)
+}
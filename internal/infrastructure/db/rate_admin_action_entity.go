@@ -0,0 +1,28 @@
+package db
+
+import (
+	"currency-rate-app/internal/domains/currency"
+	"time"
+)
+
+type CurrencyRateAdminActionEntity struct {
+	Id        string    `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	RateId    string    `gorm:"type:uuid;not null;index"`
+	Action    string    `gorm:"not null"`
+	Actor     string    `gorm:"not null"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+func (CurrencyRateAdminActionEntity) TableName() string {
+	return "currencies_rates_admin_actions"
+}
+
+func rateAdminActionToDomain(e *CurrencyRateAdminActionEntity) *currency.RateAdminAction {
+	return &currency.RateAdminAction{
+		Id:        e.Id,
+		RateId:    e.RateId,
+		Action:    currency.RateAdminActionType(e.Action),
+		Actor:     e.Actor,
+		CreatedAt: e.CreatedAt,
+	}
+}
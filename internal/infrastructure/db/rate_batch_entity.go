@@ -0,0 +1,69 @@
+package db
+
+import (
+	"currency-rate-app/internal/domains/currency"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// RateBatchEntity keys are unique per API client, like the keys of single
+// rates.
+type RateBatchEntity struct {
+	Id             string `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	ClientId       string `gorm:"not null;default:'';uniqueIndex:idx_currency_rate_batches_client_key,priority:1"`
+	IdempotencyKey string `gorm:"not null;uniqueIndex:idx_currency_rate_batches_client_key,priority:2"`
+	// RequestHash tells a retry of the batch from a different batch sent
+	// with the same key.
+	RequestHash string    `gorm:"not null"`
+	CreatedAt   time.Time `gorm:"not null"`
+}
+
+func (RateBatchEntity) TableName() string {
+	return "currency_rate_batches"
+}
+
+type RateBatchItemEntity struct {
+	BatchId        string     `gorm:"type:uuid;primaryKey"`
+	Position       int        `gorm:"primaryKey"`
+	BaseCurrency   string     `gorm:"not null"`
+	ResultCurrency string     `gorm:"not null"`
+	RateDate       *time.Time `gorm:"type:date"`
+	RateId         *string    `gorm:"type:uuid"`
+	ErrorCode      *string
+	ErrorMessage   *string
+}
+
+func (RateBatchItemEntity) TableName() string {
+	return "currency_rate_batch_items"
+}
+
+// rateBatchItemRow is an item joined with its rate request.
+type rateBatchItemRow struct {
+	RateBatchItemEntity `gorm:"embedded"`
+	Status              *string
+	Rate                *decimal.Decimal
+}
+
+func rateBatchItemToDomain(row *rateBatchItemRow) currency.RateBatchItem {
+	item := currency.RateBatchItem{
+		Position:       row.Position,
+		BaseCurrency:   currency.CurrencyCode(row.BaseCurrency),
+		ResultCurrency: currency.CurrencyCode(row.ResultCurrency),
+		RateDate:       row.RateDate,
+		RateId:         row.RateId,
+		ErrorCode:      row.ErrorCode,
+		ErrorMessage:   row.ErrorMessage,
+	}
+
+	if row.Status != nil {
+		status := currency.CurrencyRateStatus(*row.Status)
+		item.Status = &status
+
+		if status == currency.CurrencyRateStatusCompleted {
+			item.Rate = row.Rate
+		}
+	}
+
+	return item
+}
@@ -0,0 +1,158 @@
+package db
+
+import (
+	"context"
+	error_utils "currency-rate-app/internal/common/error-utils"
+	"currency-rate-app/internal/domains/currency"
+	"errors"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateRateBatch stores the batch, a PENDING rate for every item without an
+// error and the items themselves in one transaction. A retry with the same
+// key and request returns the stored batch.
+func (repo *currencyRepositoryImpl) CreateRateBatch(
+	ctx context.Context,
+	clientId string,
+	idempotencyKey string,
+	requestHash string,
+	items []currency.RateBatchItem,
+) (*currency.RateBatch, error) {
+	batch := RateBatchEntity{ClientId: clientId, IdempotencyKey: idempotencyKey, RequestHash: requestHash, CreatedAt: time.Now()}
+	created := false
+
+	err := repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(
+			clause.OnConflict{DoNothing: true, Columns: clientKeyColumns},
+		).Create(&batch)
+
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+
+		created = true
+
+		return insertRateBatchItems(tx, batch, items)
+	})
+
+	if err != nil {
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	if !created {
+		var existing RateBatchEntity
+
+		err := repo.db.WithContext(ctx).Where("client_id = ? AND idempotency_key = ?", clientId, idempotencyKey).First(&existing).Error
+
+		if err != nil {
+			return nil, error_utils.ErrInternalServerError(err.Error())
+		}
+
+		if existing.RequestHash != requestHash {
+			return nil, error_utils.ErrBusinessLogic("CurrencyRateIdempotencyConflict")
+		}
+
+		batch = existing
+	}
+
+	return repo.GetRateBatchById(ctx, batch.Id)
+}
+
+// insertRateBatchItems keys every rate by the batch id and position, so the
+// rates stay unique without asking the client for a key per item.
+func insertRateBatchItems(tx *gorm.DB, batch RateBatchEntity, items []currency.RateBatchItem) error {
+	rates := make([]CurrencyRateEntity, 0, len(items))
+	rateIndexes := make([]int, 0, len(items))
+
+	for i, item := range items {
+		if item.ErrorCode != nil {
+			continue
+		}
+
+		rates = append(rates, CurrencyRateEntity{
+			ClientId:       batch.ClientId,
+			IdempotencyKey: batch.Id + ":" + strconv.Itoa(item.Position),
+			BaseCurrency:   string(item.BaseCurrency),
+			ResultCurrency: string(item.ResultCurrency),
+			RateDate:       item.RateDate,
+			Status:         string(currency.CurrencyRateStatusPending),
+			CallbackUrl:    item.CallbackUrl,
+		})
+		rateIndexes = append(rateIndexes, i)
+	}
+
+	if len(rates) > 0 {
+		if err := tx.Create(&rates).Error; err != nil {
+			return err
+		}
+	}
+
+	entities := make([]RateBatchItemEntity, 0, len(items))
+
+	for _, item := range items {
+		entities = append(entities, RateBatchItemEntity{
+			BatchId:        batch.Id,
+			Position:       item.Position,
+			BaseCurrency:   string(item.BaseCurrency),
+			ResultCurrency: string(item.ResultCurrency),
+			RateDate:       item.RateDate,
+			ErrorCode:      item.ErrorCode,
+			ErrorMessage:   item.ErrorMessage,
+		})
+	}
+
+	for i, index := range rateIndexes {
+		entities[index].RateId = &rates[i].Id
+	}
+
+	if len(entities) == 0 {
+		return nil
+	}
+
+	return tx.Create(&entities).Error
+}
+
+func (repo *currencyRepositoryImpl) GetRateBatchById(ctx context.Context, id string) (*currency.RateBatch, error) {
+	var batch RateBatchEntity
+
+	err := repo.db.WithContext(ctx).Where(&RateBatchEntity{Id: id}).First(&batch).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, currency.ErrRateBatchNotFound()
+		}
+
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	var rows []rateBatchItemRow
+
+	err = repo.db.WithContext(ctx).
+		Table("currency_rate_batch_items AS i").
+		Select("i.*, r.status, r.rate").
+		Joins("LEFT JOIN currencies_rates r ON r.id = i.rate_id").
+		Where("i.batch_id = ?", id).
+		Order("i.position ASC").
+		Scan(&rows).Error
+
+	if err != nil {
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	items := make([]currency.RateBatchItem, 0, len(rows))
+
+	for _, row := range rows {
+		items = append(items, rateBatchItemToDomain(&row))
+	}
+
+	return &currency.RateBatch{
+		Id:             batch.Id,
+		IdempotencyKey: batch.IdempotencyKey,
+		Items:          items,
+		CreatedAt:      batch.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,182 @@
+package db
+
+import (
+	"context"
+	error_utils "currency-rate-app/internal/common/error-utils"
+	"currency-rate-app/internal/domains/currency"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// requeueColumns return a FAILED rate to the queue as a new request: the
+// attempts start over and the last failure is cleared.
+func requeueColumns(now time.Time) map[string]any {
+	return map[string]any{
+		"status":          string(currency.CurrencyRateStatusPending),
+		"attempts":        0,
+		"next_attempt_at": nil,
+		"failure_code":    nil,
+		"failure_message": nil,
+		"updated_at":      now,
+	}
+}
+
+// RequeueRate returns a FAILED rate that isn't archived to PENDING and records
+// the action.
+func (repo *currencyRepositoryImpl) RequeueRate(ctx context.Context, id string, actor string) (*currency.CurrencyRate, error) {
+	return repo.applyRateAdminAction(ctx, id, actor, currency.RateAdminActionRequeue, requeueColumns)
+}
+
+// ArchiveRate takes a FAILED rate off the dead-letter list for good, an
+// archived rate can't be requeued.
+func (repo *currencyRepositoryImpl) ArchiveRate(ctx context.Context, id string, actor string) (*currency.CurrencyRate, error) {
+	return repo.applyRateAdminAction(ctx, id, actor, currency.RateAdminActionArchive, func(now time.Time) map[string]any {
+		return map[string]any{"archived_at": now, "updated_at": now}
+	})
+}
+
+// RequeueFailedRates requeues up to limit FAILED rates matching the filter,
+// oldest failures first, and records the action for each of them.
+func (repo *currencyRepositoryImpl) RequeueFailedRates(
+	ctx context.Context,
+	filter currency.FailedRateFilter,
+	limit int,
+	actor string,
+) ([]currency.CurrencyRate, error) {
+	var entities []CurrencyRateEntity
+	now := time.Now()
+
+	err := repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []string
+
+		err := whereRateFilter(tx.Model(&CurrencyRateEntity{}), filter.RateFilter()).
+			Order("updated_at ASC").
+			Limit(limit).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Pluck("id", &ids).Error
+
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		err = tx.Model(&entities).
+			Clauses(clause.Returning{}).
+			Where("id IN ?", ids).
+			UpdateColumns(requeueColumns(now)).Error
+
+		if err != nil {
+			return err
+		}
+
+		return createRateAdminActions(tx, entities, currency.RateAdminActionRequeue, actor, now)
+	})
+
+	if err != nil {
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	models := make([]currency.CurrencyRate, 0, len(entities))
+	for _, e := range entities {
+		models = append(models, *toDomain(&e))
+	}
+
+	return models, nil
+}
+
+func (repo *currencyRepositoryImpl) GetRateAdminActions(ctx context.Context, id string) ([]currency.RateAdminAction, error) {
+	if _, err := repo.GetRateById(ctx, id); err != nil {
+		return nil, err
+	}
+
+	var entities []CurrencyRateAdminActionEntity
+
+	err := repo.db.WithContext(ctx).
+		Where(&CurrencyRateAdminActionEntity{RateId: id}).
+		Order("created_at DESC").
+		Find(&entities).Error
+
+	if err != nil {
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	models := make([]currency.RateAdminAction, 0, len(entities))
+	for _, e := range entities {
+		models = append(models, *rateAdminActionToDomain(&e))
+	}
+	return models, nil
+}
+
+// applyRateAdminAction updates a FAILED rate that isn't archived and records
+// the action in the same transaction. The status check is part of the update,
+// so a rate requeued or archived concurrently isn't touched twice.
+func (repo *currencyRepositoryImpl) applyRateAdminAction(
+	ctx context.Context,
+	id string,
+	actor string,
+	action currency.RateAdminActionType,
+	columns func(now time.Time) map[string]any,
+) (*currency.CurrencyRate, error) {
+	var entities []CurrencyRateEntity
+	now := time.Now()
+
+	err := repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entities).
+			Clauses(clause.Returning{}).
+			Where(&CurrencyRateEntity{Id: id, Status: string(currency.CurrencyRateStatusFailed)}).
+			Where("archived_at IS NULL").
+			UpdateColumns(columns(now)).Error
+
+		if err != nil {
+			return err
+		}
+
+		return createRateAdminActions(tx, entities, action, actor, now)
+	})
+
+	if err != nil {
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	if len(entities) == 0 {
+		existing, err := repo.GetRateById(ctx, id)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if existing.ArchivedAt != nil {
+			return nil, currency.ErrCurrencyRateArchived()
+		}
+
+		return nil, currency.ErrCurrencyRateNotFailed(existing.Status)
+	}
+
+	return toDomain(&entities[0]), nil
+}
+
+func createRateAdminActions(
+	tx *gorm.DB,
+	rates []CurrencyRateEntity,
+	action currency.RateAdminActionType,
+	actor string,
+	now time.Time,
+) error {
+	if len(rates) == 0 {
+		return nil
+	}
+
+	actions := make([]CurrencyRateAdminActionEntity, 0, len(rates))
+
+	for _, r := range rates {
+		actions = append(actions, CurrencyRateAdminActionEntity{
+			RateId:    r.Id,
+			Action:    string(action),
+			Actor:     actor,
+			CreatedAt: now,
+		})
+	}
+
+	return tx.Create(&actions).Error
+}
@@ -0,0 +1,34 @@
+package db
+
+import (
+	"currency-rate-app/internal/domains/currency"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type RateEventEntity struct {
+	Id             int64           `gorm:"primaryKey;autoIncrement;index:idx_currency_rate_events_pair,priority:3"`
+	BaseCurrency   string          `gorm:"not null;index:idx_currency_rate_events_pair,priority:1"`
+	ResultCurrency string          `gorm:"not null;index:idx_currency_rate_events_pair,priority:2"`
+	RateDate       *time.Time      `gorm:"type:date"`
+	Rate           decimal.Decimal `gorm:"type:numeric;not null"`
+	PivotCurrency  *string
+	CreatedAt      time.Time `gorm:"not null"`
+}
+
+func (RateEventEntity) TableName() string {
+	return "currency_rate_events"
+}
+
+func rateEventToDomain(e *RateEventEntity) *currency.RateEvent {
+	return &currency.RateEvent{
+		Id:             e.Id,
+		BaseCurrency:   currency.CurrencyCode(e.BaseCurrency),
+		ResultCurrency: currency.CurrencyCode(e.ResultCurrency),
+		RateDate:       e.RateDate,
+		Rate:           e.Rate,
+		PivotCurrency:  (*currency.CurrencyCode)(e.PivotCurrency),
+		CreatedAt:      e.CreatedAt,
+	}
+}
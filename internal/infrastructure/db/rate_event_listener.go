@@ -0,0 +1,126 @@
+package db
+
+import (
+	"context"
+	"currency-rate-app/internal/domains/currency"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const rateEventsCatchUpBatch = 500
+
+// RateEventListener follows RateEventsChannel on a dedicated connection, so
+// every instance sees rates completed by any worker.
+type RateEventListener struct {
+	databaseUrl string
+	repo        RateEventRepository
+	retryDelay  time.Duration
+}
+
+func NewRateEventListener(databaseUrl string, repo RateEventRepository, retryDelay time.Duration) *RateEventListener {
+	return &RateEventListener{databaseUrl: databaseUrl, repo: repo, retryDelay: retryDelay}
+}
+
+// Listen blocks until ctx is done, calling handle for every event. Broken
+// connections are reopened and the events committed meanwhile are read from
+// the table before following the channel again.
+func (l *RateEventListener) Listen(ctx context.Context, handle func(currency.RateEvent)) {
+	lastId := int64(-1)
+
+	for {
+		err := l.listen(ctx, &lastId, handle)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		slog.ErrorContext(ctx, "Rate events listener failed", slog.String("error", err.Error()))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(l.retryDelay):
+		}
+	}
+}
+
+func (l *RateEventListener) listen(ctx context.Context, lastId *int64, handle func(currency.RateEvent)) error {
+	conn, err := pgx.Connect(ctx, l.databaseUrl)
+
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+RateEventsChannel); err != nil {
+		return err
+	}
+
+	if err := l.catchUp(ctx, lastId, handle); err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		var entity RateEventEntity
+
+		if err := json.Unmarshal([]byte(notification.Payload), &entity); err != nil {
+			slog.ErrorContext(ctx, "Invalid rate event payload", slog.String("error", err.Error()))
+
+			continue
+		}
+
+		// Events committed between LISTEN and the end of catchUp arrive
+		// once more as notifications.
+		if entity.Id <= *lastId {
+			continue
+		}
+
+		*lastId = entity.Id
+
+		handle(*rateEventToDomain(&entity))
+	}
+}
+
+// catchUp replays what was missed while disconnected. On the first connect
+// there is nothing to replay, it only remembers where the table ends.
+func (l *RateEventListener) catchUp(ctx context.Context, lastId *int64, handle func(currency.RateEvent)) error {
+	if *lastId < 0 {
+		id, err := l.repo.GetLastRateEventId(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		*lastId = id
+
+		return nil
+	}
+
+	for {
+		events, err := l.repo.GetRateEventsAfter(ctx, *lastId, nil, rateEventsCatchUpBatch)
+
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			*lastId = event.Id
+
+			handle(event)
+		}
+
+		if len(events) < rateEventsCatchUpBatch {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package db
+
+import (
+	"context"
+	error_utils "currency-rate-app/internal/common/error-utils"
+	"currency-rate-app/internal/domains/currency"
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RateEventsChannel is the LISTEN/NOTIFY channel every completed rate is
+// announced on, the payload is the JSON encoded RateEventEntity.
+const RateEventsChannel = "currency_rate_events"
+
+type RateEventRepository interface {
+	// GetRateEventsAfter returns events with id > afterId ordered by id. Empty
+	// pairs matches every pair. Ids are committed in order, see
+	// publishRateEvents, so no event is skipped by resuming after an id.
+	GetRateEventsAfter(ctx context.Context, afterId int64, pairs []currency.CurrencyPair, limit int) ([]currency.RateEvent, error)
+	GetLastRateEventId(ctx context.Context) (int64, error)
+}
+
+type rateEventRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewRateEventRepository(db *gorm.DB) *rateEventRepositoryImpl {
+	return &rateEventRepositoryImpl{db: db}
+}
+
+func (repo *rateEventRepositoryImpl) GetRateEventsAfter(
+	ctx context.Context,
+	afterId int64,
+	pairs []currency.CurrencyPair,
+	limit int,
+) ([]currency.RateEvent, error) {
+	var entities []RateEventEntity
+
+	query := repo.db.WithContext(ctx).Where("id > ?", afterId)
+
+	if len(pairs) > 0 {
+		values := make([][]any, 0, len(pairs))
+
+		for _, pair := range pairs {
+			values = append(values, []any{string(pair.BaseCurrency), string(pair.ResultCurrency)})
+		}
+
+		query = query.Where("(base_currency, result_currency) IN ?", values)
+	}
+
+	err := query.Order("id ASC").Limit(limit).Find(&entities).Error
+
+	if err != nil {
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	events := make([]currency.RateEvent, 0, len(entities))
+
+	for _, e := range entities {
+		events = append(events, *rateEventToDomain(&e))
+	}
+
+	return events, nil
+}
+
+func (repo *rateEventRepositoryImpl) GetLastRateEventId(ctx context.Context) (int64, error) {
+	var id int64
+
+	err := repo.db.WithContext(ctx).Model(&RateEventEntity{}).Select("COALESCE(MAX(id), 0)").Scan(&id).Error
+
+	if err != nil {
+		return 0, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	return id, nil
+}
+
+// publishRateEvents stores one event per completed pair and announces it. It
+// runs in the transaction that completes the rates: postgres delivers the
+// notification on commit, so listeners never see a rolled back rate.
+//
+// Readers resume after the last id they saw, so ids have to become visible in
+// order. Workers complete rates concurrently and a bigserial id taken by one
+// transaction may commit after a greater id of another, which a reader would
+// then skip. The publishing transactions are serialized by an advisory lock
+// held until commit, so an event is committed before any greater id is taken.
+func publishRateEvents(tx *gorm.DB, rates []CurrencyRateEntity, quote currency.RateQuote, now time.Time) error {
+	if len(rates) == 0 {
+		return nil
+	}
+
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", RateEventsChannel).Error; err != nil {
+		return err
+	}
+
+	seen := make(map[string]struct{}, len(rates))
+
+	for _, r := range rates {
+		key := r.BaseCurrency + r.ResultCurrency
+
+		if date := currency.FormatRateDate(r.RateDate); date != nil {
+			key += *date
+		}
+
+		if _, ok := seen[key]; ok {
+			continue
+		}
+
+		seen[key] = struct{}{}
+
+		event := RateEventEntity{
+			BaseCurrency:   r.BaseCurrency,
+			ResultCurrency: r.ResultCurrency,
+			RateDate:       r.RateDate,
+			Rate:           quote.Rate,
+			PivotCurrency:  (*string)(quote.PivotCurrency),
+			CreatedAt:      now,
+		}
+
+		if err := tx.Create(&event).Error; err != nil {
+			return err
+		}
+
+		payload, err := json.Marshal(event)
+
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Exec("SELECT pg_notify(?, ?)", RateEventsChannel, string(payload)).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
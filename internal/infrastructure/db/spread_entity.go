@@ -0,0 +1,54 @@
+package db
+
+import (
+	"currency-rate-app/internal/domains/pricing"
+	"time"
+)
+
+type SpreadEntity struct {
+	Scope     string    `gorm:"primaryKey"`
+	BidBps    int       `gorm:"not null"`
+	AskBps    int       `gorm:"not null"`
+	UpdatedBy string    `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+}
+
+func (SpreadEntity) TableName() string {
+	return "pricing_spreads"
+}
+
+type SpreadHistoryEntity struct {
+	Id        string `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	Scope     string `gorm:"not null;index"`
+	Action    string `gorm:"not null"`
+	BidBps    *int
+	AskBps    *int
+	Actor     string    `gorm:"not null"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+func (SpreadHistoryEntity) TableName() string {
+	return "pricing_spreads_history"
+}
+
+func spreadToDomain(e *SpreadEntity) *pricing.Spread {
+	return &pricing.Spread{
+		Scope:     e.Scope,
+		BidBps:    e.BidBps,
+		AskBps:    e.AskBps,
+		UpdatedBy: e.UpdatedBy,
+		UpdatedAt: e.UpdatedAt,
+	}
+}
+
+func spreadChangeToDomain(e *SpreadHistoryEntity) *pricing.SpreadChange {
+	return &pricing.SpreadChange{
+		Id:        e.Id,
+		Scope:     e.Scope,
+		Action:    pricing.SpreadAction(e.Action),
+		BidBps:    e.BidBps,
+		AskBps:    e.AskBps,
+		Actor:     e.Actor,
+		CreatedAt: e.CreatedAt,
+	}
+}
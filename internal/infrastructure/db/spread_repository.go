@@ -0,0 +1,145 @@
+package db
+
+import (
+	"context"
+	error_utils "currency-rate-app/internal/common/error-utils"
+	"currency-rate-app/internal/domains/pricing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SpreadRepository interface {
+	ListSpreads(ctx context.Context) ([]pricing.Spread, error)
+	GetSpreadsByScopes(ctx context.Context, scopes []string) ([]pricing.Spread, error)
+	SetSpread(ctx context.Context, scope string, bidBps int, askBps int, actor string) (*pricing.Spread, error)
+	DeleteSpread(ctx context.Context, scope string, actor string) error
+	GetSpreadHistory(ctx context.Context, scope string) ([]pricing.SpreadChange, error)
+}
+
+type spreadRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewSpreadRepository(db *gorm.DB) *spreadRepositoryImpl {
+	return &spreadRepositoryImpl{db: db}
+}
+
+func (repo *spreadRepositoryImpl) ListSpreads(ctx context.Context) ([]pricing.Spread, error) {
+	var entities []SpreadEntity
+
+	if err := repo.db.WithContext(ctx).Order("scope ASC").Find(&entities).Error; err != nil {
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	models := make([]pricing.Spread, 0, len(entities))
+	for _, e := range entities {
+		models = append(models, *spreadToDomain(&e))
+	}
+	return models, nil
+}
+
+func (repo *spreadRepositoryImpl) GetSpreadsByScopes(ctx context.Context, scopes []string) ([]pricing.Spread, error) {
+	var entities []SpreadEntity
+
+	if err := repo.db.WithContext(ctx).Where("scope IN ?", scopes).Find(&entities).Error; err != nil {
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	models := make([]pricing.Spread, 0, len(entities))
+	for _, e := range entities {
+		models = append(models, *spreadToDomain(&e))
+	}
+	return models, nil
+}
+
+// SetSpread upserts the spread and appends the change to its history in the
+// same transaction.
+func (repo *spreadRepositoryImpl) SetSpread(
+	ctx context.Context,
+	scope string,
+	bidBps int,
+	askBps int,
+	actor string,
+) (*pricing.Spread, error) {
+	entity := &SpreadEntity{
+		Scope:     scope,
+		BidBps:    bidBps,
+		AskBps:    askBps,
+		UpdatedBy: actor,
+		UpdatedAt: time.Now(),
+	}
+
+	err := repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "scope"}},
+			DoUpdates: clause.AssignmentColumns([]string{"bid_bps", "ask_bps", "updated_by", "updated_at"}),
+		}).Create(entity).Error
+
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(&SpreadHistoryEntity{
+			Scope:     scope,
+			Action:    string(pricing.SpreadActionSet),
+			BidBps:    &bidBps,
+			AskBps:    &askBps,
+			Actor:     actor,
+			CreatedAt: entity.UpdatedAt,
+		}).Error
+	})
+
+	if err != nil {
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	return spreadToDomain(entity), nil
+}
+
+func (repo *spreadRepositoryImpl) DeleteSpread(ctx context.Context, scope string, actor string) error {
+	return repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where(&SpreadEntity{Scope: scope}).Delete(&SpreadEntity{})
+
+		if res.Error != nil {
+			return error_utils.ErrInternalServerError(res.Error.Error())
+		}
+
+		if res.RowsAffected == 0 {
+			return pricing.ErrSpreadNotFound()
+		}
+
+		err := tx.Create(&SpreadHistoryEntity{
+			Scope:     scope,
+			Action:    string(pricing.SpreadActionDelete),
+			Actor:     actor,
+			CreatedAt: time.Now(),
+		}).Error
+
+		if err != nil {
+			return error_utils.ErrInternalServerError(err.Error())
+		}
+
+		return nil
+	})
+}
+
+func (repo *spreadRepositoryImpl) GetSpreadHistory(ctx context.Context, scope string) ([]pricing.SpreadChange, error) {
+	var entities []SpreadHistoryEntity
+
+	err := repo.db.WithContext(ctx).
+		Where(&SpreadHistoryEntity{Scope: scope}).
+		Order("created_at DESC").
+		Find(&entities).Error
+
+	if err != nil {
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	models := make([]pricing.SpreadChange, 0, len(entities))
+	for _, e := range entities {
+		models = append(models, *spreadChangeToDomain(&e))
+	}
+	return models, nil
+}
@@ -0,0 +1,35 @@
+package db
+
+import (
+	"currency-rate-app/internal/domains/currency"
+	"currency-rate-app/internal/domains/subscription"
+	"time"
+)
+
+type SubscriptionEntity struct {
+	Id              string    `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	BaseCurrency    string    `gorm:"not null;uniqueIndex:idx_subscriptions_pair"`
+	ResultCurrency  string    `gorm:"not null;uniqueIndex:idx_subscriptions_pair"`
+	IntervalSeconds int       `gorm:"not null"`
+	NextRunAt       time.Time `gorm:"not null;index"`
+	LastEnqueuedAt  *time.Time
+	CreatedAt       time.Time `gorm:"not null"`
+	UpdatedAt       time.Time `gorm:"not null"`
+}
+
+func (SubscriptionEntity) TableName() string {
+	return "subscriptions"
+}
+
+func subscriptionToDomain(e *SubscriptionEntity) *subscription.Subscription {
+	return &subscription.Subscription{
+		Id:             e.Id,
+		BaseCurrency:   currency.CurrencyCode(e.BaseCurrency),
+		ResultCurrency: currency.CurrencyCode(e.ResultCurrency),
+		Interval:       time.Duration(e.IntervalSeconds) * time.Second,
+		NextRunAt:      e.NextRunAt,
+		LastEnqueuedAt: e.LastEnqueuedAt,
+		CreatedAt:      e.CreatedAt,
+		UpdatedAt:      e.UpdatedAt,
+	}
+}
@@ -0,0 +1,172 @@
+package db
+
+import (
+	"context"
+	error_utils "currency-rate-app/internal/common/error-utils"
+	"currency-rate-app/internal/domains/currency"
+	"currency-rate-app/internal/domains/subscription"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SubscriptionRepository interface {
+	CreateSubscription(ctx context.Context, baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, interval time.Duration) (*subscription.Subscription, error)
+	ListSubscriptions(ctx context.Context) ([]subscription.Subscription, error)
+	GetSubscriptionById(ctx context.Context, id string) (*subscription.Subscription, error)
+	DeleteSubscription(ctx context.Context, id string) error
+	EnqueueDueSubscriptions(ctx context.Context, now time.Time, limit int) ([]subscription.Subscription, error)
+}
+
+type subscriptionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewSubscriptionRepository(db *gorm.DB) *subscriptionRepositoryImpl {
+	return &subscriptionRepositoryImpl{db: db}
+}
+
+func (repo *subscriptionRepositoryImpl) CreateSubscription(
+	ctx context.Context,
+	baseCurrency currency.CurrencyCode,
+	resultCurrency currency.CurrencyCode,
+	interval time.Duration,
+) (*subscription.Subscription, error) {
+	now := time.Now()
+	entity := &SubscriptionEntity{
+		BaseCurrency:    string(baseCurrency),
+		ResultCurrency:  string(resultCurrency),
+		IntervalSeconds: int(interval / time.Second),
+		NextRunAt:       now,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	res := repo.db.WithContext(ctx).Clauses(
+		clause.OnConflict{DoNothing: true, Columns: []clause.Column{{Name: "base_currency"}, {Name: "result_currency"}}},
+	).Create(entity)
+
+	if res.Error != nil {
+		return nil, error_utils.ErrInternalServerError(res.Error.Error())
+	}
+
+	if res.RowsAffected == 0 {
+		return nil, subscription.ErrSubscriptionAlreadyExists()
+	}
+
+	return subscriptionToDomain(entity), nil
+}
+
+func (repo *subscriptionRepositoryImpl) ListSubscriptions(ctx context.Context) ([]subscription.Subscription, error) {
+	var entities []SubscriptionEntity
+
+	if err := repo.db.WithContext(ctx).Order("created_at ASC").Find(&entities).Error; err != nil {
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	models := make([]subscription.Subscription, 0, len(entities))
+	for _, e := range entities {
+		models = append(models, *subscriptionToDomain(&e))
+	}
+	return models, nil
+}
+
+func (repo *subscriptionRepositoryImpl) GetSubscriptionById(ctx context.Context, id string) (*subscription.Subscription, error) {
+	var entity SubscriptionEntity
+
+	err := repo.db.WithContext(ctx).Where(&SubscriptionEntity{Id: id}).First(&entity).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, subscription.ErrSubscriptionNotFound()
+		}
+
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	return subscriptionToDomain(&entity), nil
+}
+
+func (repo *subscriptionRepositoryImpl) DeleteSubscription(ctx context.Context, id string) error {
+	res := repo.db.WithContext(ctx).Where(&SubscriptionEntity{Id: id}).Delete(&SubscriptionEntity{})
+
+	if res.Error != nil {
+		return error_utils.ErrInternalServerError(res.Error.Error())
+	}
+
+	if res.RowsAffected == 0 {
+		return subscription.ErrSubscriptionNotFound()
+	}
+
+	return nil
+}
+
+// EnqueueDueSubscriptions creates a PENDING rate request for every subscription
+// due at now and moves it to its next run, all in one transaction. Due rows
+// are locked with SKIP LOCKED, so schedulers running on several instances
+// split the work instead of enqueuing the same run twice. The idempotency key
+// is bound to the run, which covers the rest: a repeated run is a no-op.
+func (repo *subscriptionRepositoryImpl) EnqueueDueSubscriptions(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]subscription.Subscription, error) {
+	var entities []SubscriptionEntity
+
+	err := repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Where("next_run_at <= ?", now).
+			Order("next_run_at ASC").
+			Limit(limit).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Find(&entities).Error
+
+		if err != nil || len(entities) == 0 {
+			return err
+		}
+
+		rates := make([]CurrencyRateEntity, 0, len(entities))
+		ids := make([]string, 0, len(entities))
+
+		for _, e := range entities {
+			rates = append(rates, CurrencyRateEntity{
+				IdempotencyKey: fmt.Sprintf("subscription:%s:%d", e.Id, e.NextRunAt.Unix()),
+				BaseCurrency:   e.BaseCurrency,
+				ResultCurrency: e.ResultCurrency,
+				Status:         string(currency.CurrencyRateStatusPending),
+			})
+			ids = append(ids, e.Id)
+		}
+
+		err = tx.Clauses(
+			clause.OnConflict{DoNothing: true, Columns: clientKeyColumns},
+		).Create(&rates).Error
+
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&SubscriptionEntity{}).
+			Where("id IN ?", ids).
+			UpdateColumns(map[string]any{
+				"next_run_at":      gorm.Expr("? + interval_seconds * interval '1 second'", now),
+				"last_enqueued_at": now,
+				"updated_at":       now,
+			}).Error
+	})
+
+	if err != nil {
+		return nil, error_utils.ErrInternalServerError(err.Error())
+	}
+
+	models := make([]subscription.Subscription, 0, len(entities))
+	for _, e := range entities {
+		e.LastEnqueuedAt = &now
+		e.NextRunAt = now.Add(time.Duration(e.IntervalSeconds) * time.Second)
+		models = append(models, *subscriptionToDomain(&e))
+	}
+	return models, nil
+}
@@ -0,0 +1,87 @@
+package rates_api
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+
+	error_utils "currency-rate-app/internal/common/error-utils"
+	"currency-rate-app/internal/domains/currency"
+)
+
+// StatusError is a non-200 response of a provider.
+type StatusError struct {
+	Provider   string
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return e.Provider + " response with code " + e.Status
+}
+
+// DecodeError is a provider response that couldn't be read.
+type DecodeError struct {
+	Provider string
+	Err      error
+}
+
+func (e *DecodeError) Error() string {
+	return e.Provider + " response can't be decoded: " + e.Err.Error()
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// PartialRatesError comes with the rates found so far when a provider failed
+// before every wanted rate was found: the missing rates may still exist, Err
+// is why they weren't fetched.
+type PartialRatesError struct {
+	Err error
+}
+
+func (e *PartialRatesError) Error() string {
+	return "rates are missing: " + e.Err.Error()
+}
+
+func (e *PartialRatesError) Unwrap() error {
+	return e.Err
+}
+
+// providerError keeps the unexpected error the callers already handle and the
+// cause for ClassifyError.
+func providerError(err error) error {
+	return &error_utils.CustomError{
+		ErrorType: error_utils.ErrorCodeUnexpected,
+		Code:      "InternalServerError",
+		Message:   err.Error(),
+		Err:       err,
+	}
+}
+
+// ClassifyError maps an error of a RateService call to the failure stored on
+// the rates it was made for.
+func ClassifyError(err error) currency.RateFailure {
+	failure := currency.RateFailure{Code: currency.RateFailureProviderUnavailable, Message: err.Error()}
+
+	var statusErr *StatusError
+	var decodeErr *DecodeError
+	var netErr net.Error
+
+	switch {
+	case errors.As(err, &statusErr):
+		failure.Code = currency.RateFailureProviderRejected
+
+		if statusErr.StatusCode >= http.StatusInternalServerError || statusErr.StatusCode == http.StatusTooManyRequests {
+			failure.Code = currency.RateFailureProviderError
+		}
+	case errors.As(err, &decodeErr):
+		failure.Code = currency.RateFailureProviderBadResponse
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		failure.Code = currency.RateFailureProviderTimeout
+	}
+
+	return failure
+}
@@ -0,0 +1,207 @@
+package rates_api
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"currency-rate-app/internal/common/metrics"
+	"currency-rate-app/internal/domains/currency"
+)
+
+type ProviderHealthConfig struct {
+	// FailureThreshold consecutive failures mark a provider down, it is
+	// skipped for Cooldown and tried again afterwards.
+	FailureThreshold int
+	Cooldown         time.Duration
+}
+
+type providerHealth struct {
+	failures  int
+	downUntil time.Time
+}
+
+// FailoverRateService asks providers in order: the next one is tried when a
+// provider fails or doesn't have some of the wanted rates. Providers known to
+// be down are skipped, unless every provider is down. Rejected requests (e.g.
+// an unsupported currency) fail over without counting against the provider.
+type FailoverRateService struct {
+	providers []RateService
+	config    ProviderHealthConfig
+	now       func() time.Time
+
+	mu     sync.Mutex
+	health map[string]*providerHealth
+}
+
+func NewFailoverRateService(providers []RateService, config ProviderHealthConfig) *FailoverRateService {
+	health := make(map[string]*providerHealth, len(providers))
+
+	for _, provider := range providers {
+		health[provider.Name()] = &providerHealth{}
+	}
+
+	return &FailoverRateService{providers: providers, config: config, now: time.Now, health: health}
+}
+
+func (s *FailoverRateService) Name() string {
+	names := make([]string, 0, len(s.providers))
+
+	for _, provider := range s.providers {
+		names = append(names, provider.Name())
+	}
+
+	return strings.Join(names, ",")
+}
+
+// FetchData merges rates down the chain until every wanted rate is found, a
+// rate of an earlier provider wins. Rates found so far are returned even if
+// the rest of the chain failed, with a PartialRatesError: the missing pairs
+// failed with the last error and aren't known to be unsupported.
+func (s *FailoverRateService) FetchData(baseCurrency currency.CurrencyCode, date *time.Time, wanted ...currency.CurrencyCode) (Rates, error) {
+	res := make(Rates)
+	var lastErr error
+
+	for _, provider := range s.available() {
+		rates, err := provider.FetchData(baseCurrency, date, wanted...)
+
+		if err != nil {
+			s.recordFailure(provider, err)
+			lastErr = err
+
+			continue
+		}
+
+		s.recordSuccess(provider)
+
+		for code, rate := range rates {
+			if _, ok := res[code]; !ok {
+				res[code] = rate
+			}
+		}
+
+		if res.hasAll(wanted) {
+			return res, nil
+		}
+
+		slog.Warn(
+			"Rate provider is missing wanted rates",
+			slog.String("provider", provider.Name()),
+			slog.String("baseCurrency", string(baseCurrency)),
+		)
+	}
+
+	if lastErr == nil {
+		return res, nil
+	}
+
+	if len(res) == 0 {
+		return nil, lastErr
+	}
+
+	return res, &PartialRatesError{Err: lastErr}
+}
+
+// FetchTimeSeries answers with the first provider that has any rate of the
+// pair in the range. Single missing dates aren't failed over, they are
+// usually holidays.
+func (s *FailoverRateService) FetchTimeSeries(
+	baseCurrency currency.CurrencyCode,
+	resultCurrency currency.CurrencyCode,
+	from time.Time,
+	to time.Time,
+) (*TimeSeries, error) {
+	var empty *TimeSeries
+	var lastErr error
+
+	for _, provider := range s.available() {
+		series, err := provider.FetchTimeSeries(baseCurrency, resultCurrency, from, to)
+
+		if err != nil {
+			s.recordFailure(provider, err)
+			lastErr = err
+
+			continue
+		}
+
+		s.recordSuccess(provider)
+
+		if len(series.Rates) > 0 {
+			return series, nil
+		}
+
+		empty = series
+	}
+
+	if empty == nil && lastErr != nil {
+		return nil, lastErr
+	}
+
+	return empty, nil
+}
+
+// available lists providers that aren't down, all of them when every
+// provider is down: a provider that recovered is better than no rates.
+func (s *FailoverRateService) available() []RateService {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	providers := make([]RateService, 0, len(s.providers))
+
+	for _, provider := range s.providers {
+		if now.Before(s.health[provider.Name()].downUntil) {
+			continue
+		}
+
+		providers = append(providers, provider)
+	}
+
+	if len(providers) == 0 {
+		return s.providers
+	}
+
+	return providers
+}
+
+func (s *FailoverRateService) recordFailure(provider RateService, err error) {
+	metrics.RatesProviderFailures.Add(provider.Name(), 1)
+
+	if ClassifyError(err).Code == currency.RateFailureProviderRejected {
+		slog.Warn("Rate provider rejected request", slog.String("provider", provider.Name()), slog.String("error", err.Error()))
+
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	health := s.health[provider.Name()]
+	health.failures++
+
+	if health.failures < s.config.FailureThreshold {
+		slog.Warn("Rate provider failed", slog.String("provider", provider.Name()), slog.String("error", err.Error()))
+
+		return
+	}
+
+	health.downUntil = s.now().Add(s.config.Cooldown)
+
+	slog.Error(
+		"Rate provider marked down",
+		slog.String("provider", provider.Name()),
+		slog.Int("failures", health.failures),
+		slog.Time("downUntil", health.downUntil),
+		slog.String("error", err.Error()),
+	)
+}
+
+func (s *FailoverRateService) recordSuccess(provider RateService) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	health := s.health[provider.Name()]
+	health.failures = 0
+	health.downUntil = time.Time{}
+}
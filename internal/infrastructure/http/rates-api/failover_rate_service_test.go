@@ -0,0 +1,160 @@
+package rates_api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"currency-rate-app/internal/domains/currency"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeProvider struct {
+	name   string
+	rates  map[string]decimal.Decimal
+	series map[string]decimal.Decimal
+	err    error
+	calls  int
+}
+
+func (p *fakeProvider) Name() string {
+	return p.name
+}
+
+func (p *fakeProvider) FetchData(baseCurrency currency.CurrencyCode, date *time.Time, wanted ...currency.CurrencyCode) (Rates, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return NewRates(p.name, p.rates), nil
+}
+
+func (p *fakeProvider) FetchTimeSeries(baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, from time.Time, to time.Time) (*TimeSeries, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &TimeSeries{Provider: p.name, Rates: p.series}, nil
+}
+
+func unavailable() error {
+	return providerError(&StatusError{Provider: "primary", StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable"})
+}
+
+func newTestFailover(providers ...RateService) *FailoverRateService {
+	return NewFailoverRateService(providers, ProviderHealthConfig{FailureThreshold: 2, Cooldown: time.Minute})
+}
+
+func TestFailoverRateService_FailsOverOnError(t *testing.T) {
+	primary := &fakeProvider{name: "primary", err: unavailable()}
+	secondary := &fakeProvider{name: "secondary", rates: map[string]decimal.Decimal{"EUR": decimal.RequireFromString("0.9")}}
+	service := newTestFailover(primary, secondary)
+
+	rates, err := service.FetchData(currency.USD, nil, currency.EUR)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "secondary", rates["EUR"].Provider)
+	assert.Equal(t, "0.9", rates["EUR"].Value.String())
+}
+
+func TestFailoverRateService_FailsOverOnMissingCurrency(t *testing.T) {
+	primary := &fakeProvider{name: "primary", rates: map[string]decimal.Decimal{"EUR": decimal.RequireFromString("0.9")}}
+	secondary := &fakeProvider{name: "secondary", rates: map[string]decimal.Decimal{
+		"EUR": decimal.RequireFromString("0.91"),
+		"THB": decimal.RequireFromString("36"),
+	}}
+	service := newTestFailover(primary, secondary)
+
+	rates, err := service.FetchData(currency.USD, nil, currency.EUR, "THB")
+
+	assert.Nil(t, err)
+	assert.Equal(t, Rate{Value: decimal.RequireFromString("0.9"), Provider: "primary"}, rates["EUR"])
+	assert.Equal(t, Rate{Value: decimal.RequireFromString("36"), Provider: "secondary"}, rates["THB"])
+
+	rates, err = service.FetchData(currency.USD, nil, currency.EUR)
+
+	assert.Nil(t, err)
+	assert.Len(t, rates, 1)
+	assert.Equal(t, 1, secondary.calls, "no failover when the first provider has every wanted rate")
+}
+
+func TestFailoverRateService_PartialRatesWhenLaterProviderFails(t *testing.T) {
+	primary := &fakeProvider{name: "primary", rates: map[string]decimal.Decimal{"EUR": decimal.RequireFromString("0.9")}}
+	secondary := &fakeProvider{name: "secondary", err: unavailable()}
+	service := newTestFailover(primary, secondary)
+
+	rates, err := service.FetchData(currency.USD, nil, currency.EUR, "THB")
+
+	var partialErr *PartialRatesError
+	assert.ErrorAs(t, err, &partialErr)
+	assert.Equal(t, currency.RateFailureProviderError, ClassifyError(err).Code)
+	assert.Equal(t, Rate{Value: decimal.RequireFromString("0.9"), Provider: "primary"}, rates["EUR"])
+	assert.NotContains(t, rates, "THB")
+}
+
+func TestFailoverRateService_SkipsProviderThatIsDown(t *testing.T) {
+	now := time.Now()
+	primary := &fakeProvider{name: "primary", err: unavailable()}
+	secondary := &fakeProvider{name: "secondary", rates: map[string]decimal.Decimal{"EUR": decimal.RequireFromString("0.9")}}
+	service := newTestFailover(primary, secondary)
+	service.now = func() time.Time { return now }
+
+	for range 4 {
+		_, err := service.FetchData(currency.USD, nil, currency.EUR)
+		assert.Nil(t, err)
+	}
+
+	assert.Equal(t, 2, primary.calls, "primary should be skipped after the failure threshold")
+
+	now = now.Add(2 * time.Minute)
+	primary.err = nil
+	primary.rates = map[string]decimal.Decimal{"EUR": decimal.RequireFromString("0.89")}
+
+	rates, err := service.FetchData(currency.USD, nil, currency.EUR)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, primary.calls, "primary should be tried again after the cooldown")
+	assert.Equal(t, "primary", rates["EUR"].Provider)
+}
+
+func TestFailoverRateService_RejectedRequestDoesNotMarkDown(t *testing.T) {
+	primary := &fakeProvider{name: "primary", err: providerError(&StatusError{Provider: "primary", StatusCode: http.StatusNotFound, Status: "404 Not Found"})}
+	secondary := &fakeProvider{name: "secondary", rates: map[string]decimal.Decimal{"EUR": decimal.RequireFromString("0.9")}}
+	service := newTestFailover(primary, secondary)
+
+	for range 4 {
+		_, err := service.FetchData("XAU", nil, currency.EUR)
+		assert.Nil(t, err)
+	}
+
+	assert.Equal(t, 4, primary.calls)
+}
+
+func TestFailoverRateService_AllProvidersDown(t *testing.T) {
+	primary := &fakeProvider{name: "primary", err: unavailable()}
+	secondary := &fakeProvider{name: "secondary", err: errors.New("connection refused")}
+	service := newTestFailover(primary, secondary)
+
+	for range 3 {
+		_, err := service.FetchData(currency.USD, nil, currency.EUR)
+		assert.Equal(t, "connection refused", err.Error())
+	}
+
+	assert.Equal(t, 3, primary.calls, "every provider is tried when all of them are down")
+	assert.Equal(t, 3, secondary.calls)
+}
+
+func TestFailoverRateService_FetchTimeSeries(t *testing.T) {
+	primary := &fakeProvider{name: "primary", series: map[string]decimal.Decimal{}}
+	secondary := &fakeProvider{name: "secondary", series: map[string]decimal.Decimal{"2024-01-05": decimal.RequireFromString("0.9")}}
+	service := newTestFailover(primary, secondary)
+
+	series, err := service.FetchTimeSeries(currency.USD, "THB", time.Now(), time.Now())
+
+	assert.Nil(t, err)
+	assert.Equal(t, "secondary", series.Provider)
+	assert.Len(t, series.Rates, 1)
+}
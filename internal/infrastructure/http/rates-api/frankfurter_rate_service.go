@@ -4,17 +4,26 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/url"
+	"time"
 
-	error_utils "currency-rate-app/internal/common/error-utils"
 	"currency-rate-app/internal/domains/currency"
+
+	"github.com/shopspring/decimal"
 )
 
 type getRatesResponse struct {
-	Base  string             `json:"base"`
-	Date  string             `json:"date"`
-	Rates map[string]float64 `json:"rates"`
+	Base  string                     `json:"base"`
+	Date  string                     `json:"date"`
+	Rates map[string]decimal.Decimal `json:"rates"`
+}
+
+type getTimeSeriesResponse struct {
+	Base  string                                `json:"base"`
+	Rates map[string]map[string]decimal.Decimal `json:"rates"`
 }
 
+const frankfurterProvider = "frankfurter"
+
 type FrankfurterRateService struct {
 	httpClient *http.Client
 	baseUrl    string
@@ -30,8 +39,16 @@ func NewFrankfurterRateService(
 	}
 }
 
-func (s *FrankfurterRateService) FetchData(baseCurrency currency.CurrencyCode) (map[string]float64, error) {
+func (s *FrankfurterRateService) Name() string {
+	return frankfurterProvider
+}
+
+func (s *FrankfurterRateService) FetchData(baseCurrency currency.CurrencyCode, date *time.Time, wanted ...currency.CurrencyCode) (Rates, error) {
 	endpointUrl := s.baseUrl + "/v1/latest"
+
+	if date != nil {
+		endpointUrl = s.baseUrl + "/v1/" + date.UTC().Format(currency.RateDateLayout)
+	}
 	baseURL, _ := url.Parse(endpointUrl)
 	params := url.Values{}
 	params.Add("base", string(baseCurrency))
@@ -46,14 +63,64 @@ func (s *FrankfurterRateService) FetchData(baseCurrency currency.CurrencyCode) (
 	defer res.Body.Close()
 
 	if res.StatusCode != http.StatusOK {
-		return nil, error_utils.ErrInternalServerError("frankfurter response with code" + res.Status)
+		return nil, providerError(&StatusError{Provider: frankfurterProvider, StatusCode: res.StatusCode, Status: res.Status})
 	}
 
 	var body getRatesResponse
 
 	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
-		return nil, error_utils.ErrInternalServerError(err.Error())
+		return nil, providerError(&DecodeError{Provider: frankfurterProvider, Err: err})
+	}
+
+	return NewRates(frankfurterProvider, body.Rates), nil
+}
+
+func (s *FrankfurterRateService) FetchTimeSeries(
+	baseCurrency currency.CurrencyCode,
+	resultCurrency currency.CurrencyCode,
+	from time.Time,
+	to time.Time,
+) (*TimeSeries, error) {
+	endpointUrl := s.baseUrl + "/v1/" + from.UTC().Format(currency.RateDateLayout) + ".." + to.UTC().Format(currency.RateDateLayout)
+	baseURL, _ := url.Parse(endpointUrl)
+	params := url.Values{}
+	params.Add("base", string(baseCurrency))
+	params.Add("symbols", string(resultCurrency))
+	baseURL.RawQuery = params.Encode()
+
+	res, err := s.httpClient.Get(baseURL.String())
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, providerError(&StatusError{Provider: frankfurterProvider, StatusCode: res.StatusCode, Status: res.Status})
+	}
+
+	var body getTimeSeriesResponse
+
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return nil, providerError(&DecodeError{Provider: frankfurterProvider, Err: err})
+	}
+
+	// Frankfurter moves a weekend start date back to the previous business
+	// day, so drop anything outside of the requested range.
+	fromDate := from.UTC().Format(currency.RateDateLayout)
+	toDate := to.UTC().Format(currency.RateDateLayout)
+	series := make(map[string]decimal.Decimal, len(body.Rates))
+
+	for date, dayRates := range body.Rates {
+		if date < fromDate || date > toDate {
+			continue
+		}
+
+		if rate, ok := dayRates[string(resultCurrency)]; ok {
+			series[date] = rate
+		}
 	}
 
-	return body.Rates, nil
+	return &TimeSeries{Provider: frankfurterProvider, Rates: series}, nil
 }
@@ -2,20 +2,50 @@ package rates_api
 
 import (
 	"currency-rate-app/internal/domains/currency"
+	"time"
+
+	"github.com/shopspring/decimal"
 )
 
+const mockProvider = "mock"
+
 type MockRateService struct{}
 
 func NewMockRateService() *MockRateService {
 	return &MockRateService{}
 }
 
-var rates = map[string]float64{
-	"USD": 0.5,
-	"EUR": 1.0,
-	"MXN": 1.5,
+var rates = map[string]decimal.Decimal{
+	"USD": decimal.RequireFromString("0.5"),
+	"EUR": decimal.RequireFromString("1.0"),
+	"MXN": decimal.RequireFromString("1.5"),
+}
+
+func (s *MockRateService) Name() string {
+	return mockProvider
+}
+
+func (s *MockRateService) FetchData(baseCurrency currency.CurrencyCode, date *time.Time, wanted ...currency.CurrencyCode) (Rates, error) {
+	return NewRates(mockProvider, rates), nil
 }
 
-func (s *MockRateService) FetchData(baseCurrency currency.CurrencyCode) (map[string]float64, error) {
-	return rates, nil
+func (s *MockRateService) FetchTimeSeries(
+	baseCurrency currency.CurrencyCode,
+	resultCurrency currency.CurrencyCode,
+	from time.Time,
+	to time.Time,
+) (*TimeSeries, error) {
+	res := make(map[string]decimal.Decimal)
+
+	rate, ok := rates[string(resultCurrency)]
+
+	if !ok {
+		return &TimeSeries{Provider: mockProvider, Rates: res}, nil
+	}
+
+	for _, day := range currency.MissingBusinessDays(from, to, nil) {
+		res[day.Format(currency.RateDateLayout)] = rate
+	}
+
+	return &TimeSeries{Provider: mockProvider, Rates: res}, nil
 }
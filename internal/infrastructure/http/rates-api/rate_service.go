@@ -3,11 +3,60 @@ package rates_api
 import (
 	"currency-rate-app/internal/common/config"
 	"currency-rate-app/internal/domains/currency"
+	"errors"
+	"fmt"
 	"net/http"
+	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 type RateService interface {
-	FetchData(baseCurrency currency.CurrencyCode) (map[string]float64, error)
+	// Name identifies the provider, it is stored on the rates it served.
+	Name() string
+	// FetchData returns rates for the base currency on the given business
+	// date, or the latest available rates when date is nil. Wanted result
+	// currencies tell a failover chain which rates to look for further down
+	// the chain, a single provider answers with every rate it has.
+	FetchData(baseCurrency currency.CurrencyCode, date *time.Time, wanted ...currency.CurrencyCode) (Rates, error)
+	// FetchTimeSeries returns the pair's rates keyed by business date
+	// (YYYY-MM-DD) for every date in [from, to] the provider published.
+	FetchTimeSeries(baseCurrency currency.CurrencyCode, resultCurrency currency.CurrencyCode, from time.Time, to time.Time) (*TimeSeries, error)
+}
+
+// Rate is a rate together with the provider that served it.
+type Rate struct {
+	Value    decimal.Decimal
+	Provider string
+}
+
+// Rates are the rates of one base currency keyed by the result currency. A
+// failover chain may put them together from several providers.
+type Rates map[string]Rate
+
+func NewRates(provider string, values map[string]decimal.Decimal) Rates {
+	rates := make(Rates, len(values))
+
+	for code, value := range values {
+		rates[code] = Rate{Value: value, Provider: provider}
+	}
+
+	return rates
+}
+
+func (r Rates) hasAll(codes []currency.CurrencyCode) bool {
+	for _, code := range codes {
+		if _, ok := r[string(code)]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+type TimeSeries struct {
+	Provider string
+	Rates    map[string]decimal.Decimal
 }
 
 type RateServiceType string
@@ -17,13 +66,48 @@ const (
 	Mock        RateServiceType = "Mock"
 )
 
-func NewRateService(httpClient *http.Client, config config.Config) RateService {
-	switch config.RatesApiType {
-	case "Frankfurter":
-		return NewFrankfurterRateService(httpClient, config.FrankfurterApiURL)
-	case "Mock":
-		return NewMockRateService()
-	default:
-		return NewMockRateService()
+// NewRateService builds the providers of RATES_API_PROVIDERS in order. More
+// than one provider are chained with failover. Mock serves made up rates, it
+// can't be chained: its rates would be stored as real ones whenever a real
+// provider fails. Provider health is tracked by name, so names must differ.
+func NewRateService(httpClient *http.Client, config config.Config) (RateService, error) {
+	providers := make([]RateService, 0, len(config.RatesApiProviders))
+	names := make(map[string]struct{}, len(config.RatesApiProviders))
+
+	for _, providerType := range config.RatesApiProviders {
+		var provider RateService
+
+		switch RateServiceType(providerType) {
+		case Frankfurter:
+			provider = NewFrankfurterRateService(httpClient, config.FrankfurterApiURL)
+		case Mock:
+			if len(config.RatesApiProviders) > 1 {
+				return nil, errors.New("Mock rates provider can't be chained with other providers")
+			}
+
+			provider = NewMockRateService()
+		default:
+			return nil, fmt.Errorf("unknown rates provider %q", providerType)
+		}
+
+		if _, ok := names[provider.Name()]; ok {
+			return nil, fmt.Errorf("rates provider %q is listed more than once", provider.Name())
+		}
+
+		names[provider.Name()] = struct{}{}
+		providers = append(providers, provider)
+	}
+
+	if len(providers) == 0 {
+		return nil, errors.New("no rates provider configured")
 	}
+
+	if len(providers) == 1 {
+		return providers[0], nil
+	}
+
+	return NewFailoverRateService(providers, ProviderHealthConfig{
+		FailureThreshold: config.RatesProviderFailureThreshold,
+		Cooldown:         time.Duration(config.RatesProviderCooldownInSeconds) * time.Second,
+	}), nil
 }
@@ -0,0 +1,39 @@
+package rates_api
+
+import (
+	"net/http"
+	"testing"
+
+	"currency-rate-app/internal/common/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRateService(t *testing.T) {
+	single, err := NewRateService(http.DefaultClient, config.Config{RatesApiProviders: []string{"Frankfurter"}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "frankfurter", single.Name())
+
+	mock, err := NewRateService(http.DefaultClient, config.Config{RatesApiProviders: []string{"Mock"}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "mock", mock.Name())
+}
+
+func TestNewRateService_RejectsInvalidChains(t *testing.T) {
+	chains := [][]string{
+		{"Frankfurter", "Mock"},
+		{"Mock", "Frankfurter"},
+		{"Frankfurter", "Frankfurter"},
+		{"Unknown"},
+		{},
+	}
+
+	for _, chain := range chains {
+		service, err := NewRateService(http.DefaultClient, config.Config{RatesApiProviders: chain})
+
+		assert.Error(t, err, chain)
+		assert.Nil(t, service, chain)
+	}
+}
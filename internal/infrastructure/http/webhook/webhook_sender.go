@@ -0,0 +1,142 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	EventHeader     = "X-Webhook-Event"
+	TimestampHeader = "X-Webhook-Timestamp"
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+// StatusError is returned when the receiver answers with a non-2xx status.
+type StatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return "webhook responded with " + e.Status
+}
+
+type Config struct {
+	// An empty SigningSecret sends payloads without SignatureHeader.
+	SigningSecret string
+	MaxAttempts   int
+	// InitialBackoff doubles after every failed attempt.
+	InitialBackoff time.Duration
+}
+
+// Sender posts JSON payloads signed with HMAC-SHA256. Receivers verify
+// SignatureHeader against "<timestamp>.<body>" and reject stale timestamps.
+type Sender interface {
+	// Send returns the number of attempts made and the last error, if every
+	// attempt failed.
+	Send(ctx context.Context, targetUrl string, event string, body []byte) (int, error)
+}
+
+type hmacSender struct {
+	httpClient *http.Client
+	config     Config
+	now        func() time.Time
+	sleep      func(ctx context.Context, d time.Duration) error
+}
+
+func NewSender(httpClient *http.Client, config Config) Sender {
+	if config.MaxAttempts < 1 {
+		config.MaxAttempts = 1
+	}
+
+	return &hmacSender{httpClient: httpClient, config: config, now: time.Now, sleep: sleepContext}
+}
+
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *hmacSender) Send(ctx context.Context, targetUrl string, event string, body []byte) (int, error) {
+	backoff := s.config.InitialBackoff
+
+	var lastErr error
+
+	for attempt := 1; attempt <= s.config.MaxAttempts; attempt++ {
+		retryable, err := s.post(ctx, targetUrl, event, body)
+
+		if err == nil {
+			return attempt, nil
+		}
+
+		lastErr = err
+
+		if !retryable || attempt == s.config.MaxAttempts {
+			return attempt, lastErr
+		}
+
+		if err := s.sleep(ctx, backoff); err != nil {
+			return attempt, err
+		}
+
+		backoff *= 2
+	}
+
+	return s.config.MaxAttempts, lastErr
+}
+
+func (s *hmacSender) post(ctx context.Context, targetUrl string, event string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, targetUrl, bytes.NewReader(body))
+
+	if err != nil {
+		return false, err
+	}
+
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event)
+	req.Header.Set(TimestampHeader, timestamp)
+
+	if s.config.SigningSecret != "" {
+		req.Header.Set(SignatureHeader, Sign(s.config.SigningSecret, timestamp, body))
+	}
+
+	res, err := s.httpClient.Do(req)
+
+	if err != nil {
+		return true, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return false, nil
+	}
+
+	retryable := res.StatusCode >= 500 || res.StatusCode == http.StatusTooManyRequests || res.StatusCode == http.StatusRequestTimeout
+
+	return retryable, &StatusError{StatusCode: res.StatusCode, Status: res.Status}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}